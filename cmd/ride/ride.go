@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/decompiler"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/parser"
+	"github.com/wavesplatform/gowaves/pkg/ride/mockstate"
+)
+
+var (
+	scriptBase64 = flag.String("script", "", "Base64 encoded compiled script.")
+	txPath       = flag.String("tx", "", "Path to JSON with transaction to verify.")
+	statePath    = flag.String("state", "", "Path to JSON with state fixture: accounts, balances, data entries and transactions.")
+	schemeFlag   = flag.String("scheme", "W", "Address scheme character.")
+	heightFlag   = flag.Uint64("height", 0, "Blockchain height, overrides height of state fixture.")
+	traceFlag    = flag.String("trace", "", "Print evaluation trace of let bindings, function calls and if branches: 'text' or 'json'.")
+)
+
+// result is the outcome of script evaluation.
+type result struct {
+	value bool
+	// thrown is the message of `throw()`, if script was terminated by it.
+	thrown string
+}
+
+func main() {
+	flag.Parse()
+	if *scriptBase64 == "" || *txPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if len(*schemeFlag) != 1 {
+		log.Fatalf("Invalid address scheme %q.", *schemeFlag)
+	}
+	scheme := (*schemeFlag)[0]
+
+	b, err := ioutil.ReadFile(*txPath)
+	if err != nil {
+		log.Fatalf("Failed to read transaction: %v", err)
+	}
+	tx, err := transactionFromJSON(b)
+	if err != nil {
+		log.Fatalf("Invalid transaction: %v", err)
+	}
+	state, height, err := loadState(*statePath, scheme)
+	if err != nil {
+		log.Fatalf("Invalid state: %v", err)
+	}
+	if *heightFlag != 0 {
+		height = *heightFlag
+	}
+
+	var trace *ast.Trace
+	switch *traceFlag {
+	case "":
+	case "text", "json":
+		trace = ast.NewTrace()
+	default:
+		log.Fatalf("Invalid trace format %q.", *traceFlag)
+	}
+
+	rs, err := run(os.Stdout, *scriptBase64, tx, state, scheme, height, trace)
+	if trace != nil {
+		if err := writeTrace(os.Stdout, trace, *traceFlag); err != nil {
+			log.Fatalf("Failed to write trace: %v", err)
+		}
+	}
+	if err != nil {
+		log.Fatalf("Evaluation failed: %v", err)
+	}
+	fmt.Printf("Result: %t\n", rs.value)
+	if rs.thrown != "" {
+		fmt.Printf("Thrown: %s\n", rs.thrown)
+	}
+}
+
+// run writes decompiled script to w and evaluates it with the transaction.
+// Evaluation steps are recorded to the trace, if it is not nil.
+func run(w io.Writer, script string, tx proto.Transaction, state mockstate.MockState, scheme byte, height uint64, trace *ast.Trace) (*result, error) {
+	decoded, err := base64.StdEncoding.DecodeString(script)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid base64 script")
+	}
+	txVars, err := ast.NewVariablesFromTransaction(scheme, tx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert transaction")
+	}
+	variables := map[string]ast.Expr{
+		"height": ast.NewLong(int64(height)),
+	}
+	if parser.IsContract(decoded) {
+		dApp, err := parser.LoadContract(decoded)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build DApp")
+		}
+		_, _ = io.WriteString(w, decompiler.DecompileContract(dApp))
+		if !dApp.HasVerifier() {
+			return nil, errors.New("DApp has no verifier function")
+		}
+		funcs, err := ast.NewFuncScope(dApp.Version)
+		if err != nil {
+			return nil, err
+		}
+		// Verifier is called on behalf of the DApp account, which is the sender of transaction.
+		variables["this"] = txVars["sender"]
+		scope := newScope(ast.NewScope(scheme, state, funcs, variables), trace)
+		dApp.Declare(scope)
+		scope.AddValue(dApp.Verifier.ArgumentName, ast.NewObject(txVars))
+		return evaluate(dApp.Verifier.Func.Body, scope)
+	}
+	s, err := parser.LoadScript(decoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build script")
+	}
+	_, _ = io.WriteString(w, decompiler.Decompile(s))
+	funcs, err := ast.NewFuncScope(s.Version)
+	if err != nil {
+		return nil, err
+	}
+	variables["tx"] = ast.NewObject(txVars)
+	return evaluate(s.Verifier, newScope(ast.NewScope(scheme, state, funcs, variables), trace))
+}
+
+func newScope(s ast.Scope, trace *ast.Trace) ast.Scope {
+	if trace == nil {
+		return s
+	}
+	return ast.NewTracingScope(s, trace)
+}
+
+func writeTrace(w io.Writer, trace *ast.Trace, format string) error {
+	_, _ = fmt.Fprintln(w, "Trace:")
+	if format == "json" {
+		b, err := json.MarshalIndent(trace, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+	trace.Write(w)
+	return nil
+}
+
+func evaluate(e ast.Expr, s ast.Scope) (*result, error) {
+	rs, err := e.Evaluate(s)
+	if err != nil {
+		// Throw may be wrapped by calling functions.
+		if t, ok := errors.Cause(err).(ast.Throw); ok {
+			return &result{thrown: t.Message}, nil
+		}
+		return nil, err
+	}
+	b, ok := rs.(*ast.BooleanExpr)
+	if !ok {
+		return nil, errors.Errorf("expected script result to be *BooleanExpr, found %T", rs)
+	}
+	return &result{value: b.Value}, nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+)
+
+const transferJSON = `{"type":4,"version":2,"id":"CqjGMbrd5bFmLAv2mUSdphEJSgVWkWa6ZtcMkKmgH2ax","proofs":["5W7hjPpgmmhxevCt4A7y9F8oNJ4V9w2g8jhQgx2qGmBTNsP1p1MpQeKF3cvZULwJ7vQthZfSx2BhL6TWkHSVLzvq"],"senderPublicKey":"14ovLL9a6xbBfftyxGNLKMdbnzGgnaFQjmgUJGdho6nY","assetId":null,"feeAssetId":null,"timestamp":1544715621,"amount":15,"fee":10000,"recipient":"3P2USE3iYK5w7jNahAUHTytNbVRccGZwQH3"}`
+
+// {-# STDLIB_VERSION 3 #-}
+// if (wavesBalance(tx.sender) >= 100) then getInteger(tx.sender, "key") == 1 else throw("low balance")
+const balanceScript = `AwMJAABnAAAAAgkBAAAADHdhdmVzQmFsYW5jZQAAAAEIBQAAAAJ0eAAAAAZzZW5kZXIAAAAAAAAAAGQJAAAAAAAAAgkABBoAAAACCAUAAAACdHgAAAAGc2VuZGVyAgAAAANrZXkAAAAAAAAAAAEJAAACAAAAAQIAAAALbG93IGJhbGFuY2Wpc+4L`
+
+func writeFixture(t *testing.T, balance uint64) string {
+	pk, err := crypto.NewPublicKeyFromBase58("14ovLL9a6xbBfftyxGNLKMdbnzGgnaFQjmgUJGdho6nY")
+	require.NoError(t, err)
+	sender, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, pk)
+	require.NoError(t, err)
+	f, err := ioutil.TempFile("", "ride_state")
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = fmt.Fprintf(f, `{
+		"height": 10,
+		"accounts": [{
+			"address": "%s",
+			"aliases": ["sender"],
+			"balances": {"WAVES": %d},
+			"data": [{"key": "key", "type": "integer", "value": 1}]
+		}],
+		"transactions": [{"height": 5, "transaction": %s}]
+	}`, sender.String(), balance, transferJSON)
+	require.NoError(t, err)
+	return f.Name()
+}
+
+func TestLoadState(t *testing.T) {
+	path := writeFixture(t, 100)
+	defer os.Remove(path)
+
+	state, height, err := loadState(path, proto.MainNetScheme)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), height)
+
+	account, err := state.Account(proto.NewRecipientFromAlias(*proto.NewAlias(proto.MainNetScheme, "sender")))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), account.AssetBalance(&proto.OptionalAsset{}))
+	assert.Equal(t, []proto.DataEntry{&proto.IntegerDataEntry{Key: "key", Value: 1}}, account.Data())
+
+	id, err := crypto.NewDigestFromBase58("CqjGMbrd5bFmLAv2mUSdphEJSgVWkWa6ZtcMkKmgH2ax")
+	require.NoError(t, err)
+	txHeight, err := state.TransactionHeightByID(id.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), txHeight)
+}
+
+func TestRun(t *testing.T) {
+	tx, err := transactionFromJSON([]byte(transferJSON))
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		balance uint64
+		result  result
+	}{
+		{100, result{value: true}},
+		{99, result{thrown: "low balance"}},
+	} {
+		path := writeFixture(t, test.balance)
+		state, height, err := loadState(path, proto.MainNetScheme)
+		os.Remove(path)
+		require.NoError(t, err)
+
+		out := new(bytes.Buffer)
+		rs, err := run(out, balanceScript, tx, state, proto.MainNetScheme, height, nil)
+		require.NoError(t, err)
+		assert.Equal(t, test.result, *rs)
+		assert.Contains(t, out.String(), "STDLIB_VERSION 3")
+	}
+}
+
+func TestRunTrace(t *testing.T) {
+	tx, err := transactionFromJSON([]byte(transferJSON))
+	require.NoError(t, err)
+	path := writeFixture(t, 99)
+	defer os.Remove(path)
+	state, height, err := loadState(path, proto.MainNetScheme)
+	require.NoError(t, err)
+
+	trace := ast.NewTrace()
+	_, err = run(ioutil.Discard, balanceScript, tx, state, proto.MainNetScheme, height, trace)
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, writeTrace(out, trace, "text"))
+	assert.Contains(t, out.String(), `wavesBalance(`)
+	assert.Contains(t, out.String(), `-> false -> throw("low balance")`)
+}
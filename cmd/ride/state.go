@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/mockstate"
+)
+
+// stateFixture is a JSON description of blockchain state which is available to script.
+//
+//	{
+//	  "height": 100,
+//	  "accounts": [
+//	    {
+//	      "address": "3P...",
+//	      "aliases": ["alias"],
+//	      "balances": {"WAVES": 100000000, "<asset ID>": 10},
+//	      "data": [{"key": "k", "type": "integer", "value": 1}]
+//	    }
+//	  ],
+//	  "transactions": [{"height": 90, "transaction": {...}}]
+//	}
+type stateFixture struct {
+	Height       uint64               `json:"height"`
+	Accounts     []accountFixture     `json:"accounts"`
+	Transactions []transactionFixture `json:"transactions"`
+}
+
+type accountFixture struct {
+	Address  proto.Address     `json:"address"`
+	Aliases  []string          `json:"aliases"`
+	Balances map[string]uint64 `json:"balances"`
+	Data     proto.DataEntries `json:"data"`
+}
+
+type transactionFixture struct {
+	Height      uint64          `json:"height"`
+	Transaction json.RawMessage `json:"transaction"`
+}
+
+// loadState reads state fixture from file and returns mock state with its accounts and transactions.
+func loadState(path string, scheme byte) (*mockstate.MockStateImpl, uint64, error) {
+	state := &mockstate.MockStateImpl{
+		TransactionsByID:       make(map[string]proto.Transaction),
+		TransactionsHeightByID: make(map[string]uint64),
+		Accounts:               make(map[string]mockstate.Account),
+	}
+	if path == "" {
+		return state, 0, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to read state fixture")
+	}
+	var fixture stateFixture
+	if err := json.Unmarshal(b, &fixture); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to unmarshal state fixture")
+	}
+	for _, a := range fixture.Accounts {
+		account := &mockstate.MockAccount{
+			Assets:       make(map[string]uint64),
+			DataEntries:  a.Data,
+			AddressField: a.Address,
+		}
+		for asset, balance := range a.Balances {
+			// Asset names are normalized to the form used by mock account.
+			optionalAsset, err := proto.NewOptionalAssetFromString(asset)
+			if err != nil {
+				return nil, 0, errors.Wrapf(err, "invalid asset %s of account %s", asset, a.Address.String())
+			}
+			account.Assets[optionalAsset.String()] = balance
+		}
+		state.Accounts[a.Address.String()] = account
+		for _, name := range a.Aliases {
+			alias := proto.NewAlias(scheme, name)
+			if ok, err := alias.Valid(); !ok {
+				return nil, 0, errors.Wrapf(err, "invalid alias %s of account %s", name, a.Address.String())
+			}
+			state.Accounts[alias.String()] = account
+		}
+	}
+	for _, t := range fixture.Transactions {
+		tx, err := transactionFromJSON(t.Transaction)
+		if err != nil {
+			return nil, 0, err
+		}
+		id, err := tx.GetID()
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to get transaction ID")
+		}
+		state.TransactionsByID[base58.Encode(id)] = tx
+		state.TransactionsHeightByID[base58.Encode(id)] = t.Height
+	}
+	return state, fixture.Height, nil
+}
+
+// transactionFromJSON unmarshals transaction of any type and version.
+func transactionFromJSON(b []byte) (proto.Transaction, error) {
+	var tv proto.TransactionTypeVersion
+	if err := json.Unmarshal(b, &tv); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal transaction type")
+	}
+	tx, err := proto.GuessTransactionType(&tv)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, tx); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal transaction")
+	}
+	// ID is optional in JSON, it is calculated from the transaction body.
+	tx.GenerateID()
+	return tx, nil
+}
@@ -26,6 +26,8 @@ type Iterator interface {
 	Key() []byte
 	Value() []byte
 	Next() bool
+	// Seek moves iterator to the first key which is greater or equal to the given one.
+	Seek(key []byte) bool
 	Error() error
 	Release()
 }
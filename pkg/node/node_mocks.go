@@ -226,6 +226,74 @@ func (a *MockStateManager) ApprovalHeight(featureID int16) (uint64, error) {
 	panic("implement me")
 }
 
+func (a *MockStateManager) RetrieveEntry(addr proto.Address, key string) (proto.DataEntry, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) RetrieveEntries(addr proto.Address) ([]proto.DataEntry, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) AccountScript(addr proto.Address) (proto.Script, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) HasVerifier(addr proto.Address) (bool, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) ScriptInfo(addr proto.Address) (*state.ScriptInfo, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) AssetScript(assetID crypto.Digest) (proto.Script, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) OrderVolume(orderID []byte) (*state.OrderVolume, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) TransactionByID(id []byte) (proto.Transaction, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) TransactionHeightByID(id []byte) (uint64, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) TransactionInfoByID(id []byte) (*state.TransactionInfo, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) IsTransactionConfirmed(id []byte) (bool, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) AddressTransactions(addr proto.Address, after []byte, limit int) ([]*state.TransactionInfo, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) AddressAssets(addr proto.Address) ([]*state.AssetBalance, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) AddressNFTs(addr proto.Address, after []byte, limit int) ([]*state.AssetInfo, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) AssetDistribution(assetID crypto.Digest, height uint64, after *proto.Address, limit int) ([]*state.AssetHolder, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) LeaseInfo(leaseID crypto.Digest) (*state.LeaseInfo, error) {
+	panic("implement me")
+}
+
+func (a *MockStateManager) ActiveLeases(addr proto.Address) ([]*state.LeaseInfo, error) {
+	panic("implement me")
+}
+
 func newMockStateWithGenesis() *MockStateManager {
 	sig, _ := crypto.NewSignatureFromBase58("5uqnLK3Z9eiot6FyYBfwUnbyid3abicQbAZjz38GQ1Q8XigQMxTK4C1zNkqS1SVw7FqSidbZKxWAKLVoEsp4nNqa")
 	block := &proto.Block{
@@ -250,7 +250,7 @@ func (tx Genesis) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *Genesis) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -294,7 +294,7 @@ func (tx Genesis) Valid() (bool, error) {
 	return true, nil
 }
 
-func (tx *Genesis) bodyMarshalBinary() ([]byte, error) {
+func (tx *Genesis) BodyMarshalBinary() ([]byte, error) {
 	buf := make([]byte, genesisBodyLen)
 	buf[0] = byte(tx.Type)
 	binary.BigEndian.PutUint64(buf[1:], tx.Timestamp)
@@ -330,7 +330,7 @@ func (tx *Genesis) generateBodyHash(body []byte) crypto.Signature {
 
 //GenerateSigID calculates hash of the transaction and use it as an ID. Also doubled hash is used as a signature.
 func (tx *Genesis) GenerateSigID() error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to generate signature of Genesis transaction")
 	}
@@ -342,7 +342,7 @@ func (tx *Genesis) GenerateSigID() error {
 
 //MarshalBinary writes transaction bytes to slice of bytes.
 func (tx *Genesis) MarshalBinary() ([]byte, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal Genesis transaction to bytes")
 	}
@@ -529,6 +529,15 @@ func (tx *Payment) bodyMarshalBinaryBuffer() []byte {
 	return make([]byte, paymentBodyLen)
 }
 
+func (tx *Payment) BodyMarshalBinary() ([]byte, error) {
+	b := tx.bodyMarshalBinaryBuffer()
+	err := tx.bodyMarshalBinary(b)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
 //MarshalBinary returns a bytes representation of Payment transaction.
 func (tx *Payment) MarshalBinary2(buf []byte) ([]byte, error) {
 	b := tx.bodyMarshalBinaryBuffer()
@@ -61,7 +61,7 @@ func (tx IssueV1) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *IssueV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -92,7 +92,7 @@ func NewUnsignedIssueV1(senderPK crypto.PublicKey, name, description string, qua
 	return &IssueV1{Type: IssueTransaction, Version: 1, Issue: i}
 }
 
-func (tx *IssueV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *IssueV1) BodyMarshalBinary() ([]byte, error) {
 	b, err := tx.Issue.marshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal IssueV1 body")
@@ -123,7 +123,7 @@ func (tx *IssueV1) bodyUnmarshalBinary(data []byte) error {
 
 //Sign uses secretKey to sing the transaction.
 func (tx *IssueV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign IssueV1 transaction")
 	}
@@ -139,7 +139,7 @@ func (tx *IssueV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 	if tx.Signature == nil {
 		return false, errors.New("empty signature")
 	}
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of IssueV1 transaction")
 	}
@@ -149,7 +149,7 @@ func (tx *IssueV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 //MarshalBinary saves transaction's binary representation to slice of bytes.
 func (tx *IssueV1) MarshalBinary() ([]byte, error) {
 	sl := crypto.SignatureSize
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal IssueV1 transaction to bytes")
 	}
@@ -201,7 +201,7 @@ func (tx TransferV1) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *TransferV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -232,7 +232,7 @@ func NewUnsignedTransferV1(senderPK crypto.PublicKey, amountAsset, feeAsset Opti
 	return &TransferV1{Type: TransferTransaction, Version: 1, Transfer: t}
 }
 
-func (tx *TransferV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *TransferV1) BodyMarshalBinary() ([]byte, error) {
 	b, err := tx.Transfer.marshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal TransferV1 body")
@@ -263,7 +263,7 @@ func (tx *TransferV1) bodyUnmarshalBinary(data []byte) error {
 
 //Sign calculates a signature and a digest as an ID of the transaction.
 func (tx *TransferV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign TransferV1 transaction")
 	}
@@ -282,7 +282,7 @@ func (tx *TransferV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 	if tx.Signature == nil {
 		return false, errors.New("empty signature")
 	}
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of TransferV1 transaction")
 	}
@@ -292,7 +292,7 @@ func (tx *TransferV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 //MarshalBinary saves transaction to its binary representation.
 func (tx *TransferV1) MarshalBinary() ([]byte, error) {
 	sl := crypto.SignatureSize
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal TransferV1 transaction to bytes")
 	}
@@ -344,7 +344,7 @@ func (tx ReissueV1) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *ReissueV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -374,7 +374,7 @@ func NewUnsignedReissueV1(senderPK crypto.PublicKey, assetID crypto.Digest, quan
 	return &ReissueV1{Type: ReissueTransaction, Version: 1, Reissue: r}
 }
 
-func (tx *ReissueV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *ReissueV1) BodyMarshalBinary() ([]byte, error) {
 	buf := make([]byte, reissueV1BodyLen)
 	buf[0] = byte(tx.Type)
 	b, err := tx.Reissue.marshalBinary()
@@ -406,7 +406,7 @@ func (tx *ReissueV1) bodyUnmarshalBinary(data []byte) error {
 //Sign use given private key to calculate signature of the transaction.
 //This function also calculates digest of transaction data and assigns it to ID field.
 func (tx *ReissueV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign ReissueV1 transaction")
 	}
@@ -425,7 +425,7 @@ func (tx *ReissueV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 	if tx.Signature == nil {
 		return false, errors.New("empty signature")
 	}
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of ReissueV1 transaction")
 	}
@@ -435,7 +435,7 @@ func (tx *ReissueV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 //MarshalBinary saves the transaction to its binary representation.
 func (tx *ReissueV1) MarshalBinary() ([]byte, error) {
 	sl := crypto.SignatureSize
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal ReissueV1 transaction to bytes")
 	}
@@ -487,7 +487,7 @@ func (tx BurnV1) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *BurnV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -516,7 +516,7 @@ func NewUnsignedBurnV1(senderPK crypto.PublicKey, assetID crypto.Digest, amount,
 	return &BurnV1{Type: BurnTransaction, Version: 1, Burn: b}
 }
 
-func (tx *BurnV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *BurnV1) BodyMarshalBinary() ([]byte, error) {
 	buf := make([]byte, burnV1BodyLen)
 	buf[0] = byte(tx.Type)
 	b, err := tx.Burn.marshalBinary()
@@ -547,7 +547,7 @@ func (tx *BurnV1) bodyUnmarshalBinary(data []byte) error {
 
 //Sign calculates and sets signature and ID of the transaction.
 func (tx *BurnV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign BurnV1 transaction")
 	}
@@ -566,7 +566,7 @@ func (tx *BurnV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 	if tx.Signature == nil {
 		return false, errors.New("empty signature")
 	}
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of BurnV1 transaction")
 	}
@@ -575,7 +575,7 @@ func (tx *BurnV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary saves transaction to
 func (tx *BurnV1) MarshalBinary() ([]byte, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal BurnV1 transaction to bytes")
 	}
@@ -628,7 +628,7 @@ func (tx ExchangeV1) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *ExchangeV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -765,7 +765,7 @@ func (tx ExchangeV1) Valid() (bool, error) {
 	return true, nil
 }
 
-func (tx *ExchangeV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *ExchangeV1) BodyMarshalBinary() ([]byte, error) {
 	bob, err := tx.BuyOrder.MarshalBinary()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to marshal ExchangeV1 body to bytes")
@@ -849,7 +849,7 @@ func (tx *ExchangeV1) bodyUnmarshalBinary(data []byte) (int, error) {
 
 //Sing calculates ID and Signature of the transaction.
 func (tx *ExchangeV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign ExchangeV1 transaction")
 	}
@@ -868,7 +868,7 @@ func (tx *ExchangeV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 	if tx.Signature == nil {
 		return false, errors.New("empty signature")
 	}
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of ExchangeV1 transaction")
 	}
@@ -877,7 +877,7 @@ func (tx *ExchangeV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary saves the transaction to its binary representation.
 func (tx *ExchangeV1) MarshalBinary() ([]byte, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal ExchangeV1 transaction to bytes")
 	}
@@ -928,7 +928,7 @@ func (tx LeaseV1) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *LeaseV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -957,7 +957,7 @@ func NewUnsignedLeaseV1(senderPK crypto.PublicKey, recipient Recipient, amount,
 	return &LeaseV1{Type: LeaseTransaction, Version: 1, Lease: l}
 }
 
-func (tx *LeaseV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *LeaseV1) BodyMarshalBinary() ([]byte, error) {
 	rl := tx.Recipient.len
 	buf := make([]byte, leaseV1BodyLen+rl)
 	buf[0] = byte(tx.Type)
@@ -989,7 +989,7 @@ func (tx *LeaseV1) bodyUnmarshalBinary(data []byte) error {
 
 //Sign calculates ID and Signature of the transaction.
 func (tx *LeaseV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign LeaseV1 transaction")
 	}
@@ -1008,7 +1008,7 @@ func (tx *LeaseV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 	if tx.Signature == nil {
 		return false, errors.New("empty signature")
 	}
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of LeaseV1 transaction")
 	}
@@ -1017,7 +1017,7 @@ func (tx *LeaseV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary saves the transaction to its binary representation.
 func (tx *LeaseV1) MarshalBinary() ([]byte, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal LeaseV1 transaction to bytes")
 	}
@@ -1069,7 +1069,7 @@ func (tx LeaseCancelV1) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *LeaseCancelV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -1097,7 +1097,7 @@ func NewUnsignedLeaseCancelV1(senderPK crypto.PublicKey, leaseID crypto.Digest,
 	return &LeaseCancelV1{Type: LeaseCancelTransaction, Version: 1, LeaseCancel: lc}
 }
 
-func (tx *LeaseCancelV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *LeaseCancelV1) BodyMarshalBinary() ([]byte, error) {
 	buf := make([]byte, leaseCancelV1BodyLen)
 	buf[0] = byte(tx.Type)
 	b, err := tx.LeaseCancel.marshalBinary()
@@ -1128,7 +1128,7 @@ func (tx *LeaseCancelV1) bodyUnmarshalBinary(data []byte) error {
 }
 
 func (tx *LeaseCancelV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign LeaseCancelV1 transaction")
 	}
@@ -1147,7 +1147,7 @@ func (tx *LeaseCancelV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 	if tx.Signature == nil {
 		return false, errors.New("empty signature")
 	}
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of LeaseCancelV1 transaction")
 	}
@@ -1156,7 +1156,7 @@ func (tx *LeaseCancelV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary saves transaction to its binary representation.
 func (tx *LeaseCancelV1) MarshalBinary() ([]byte, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal LeaseCancelV1 transaction to bytes")
 	}
@@ -1231,7 +1231,7 @@ func NewUnsignedCreateAliasV1(senderPK crypto.PublicKey, alias Alias, fee, times
 	return &CreateAliasV1{Type: CreateAliasTransaction, Version: 1, CreateAlias: ca}
 }
 
-func (tx *CreateAliasV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *CreateAliasV1) BodyMarshalBinary() ([]byte, error) {
 	buf := make([]byte, createAliasV1FixedBodyLen+len(tx.Alias.Alias))
 	buf[0] = byte(tx.Type)
 	b, err := tx.CreateAlias.marshalBinary()
@@ -1261,7 +1261,7 @@ func (tx *CreateAliasV1) bodyUnmarshalBinary(data []byte) error {
 }
 
 func (tx *CreateAliasV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign CreateAliasV1 transaction")
 	}
@@ -1278,7 +1278,7 @@ func (tx *CreateAliasV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 	if tx.Signature == nil {
 		return false, errors.New("empty signature")
 	}
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of CreateAliasV1 transaction")
 	}
@@ -1286,7 +1286,7 @@ func (tx *CreateAliasV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 }
 
 func (tx *CreateAliasV1) MarshalBinary() ([]byte, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal CreateAliasV1 transaction to bytes")
 	}
@@ -1395,7 +1395,7 @@ func (tx MassTransferV1) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *MassTransferV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -1465,7 +1465,7 @@ func (tx *MassTransferV1) bodyAndAssetLen() (int, int) {
 	return massTransferV1FixedLen + l + n*massTransferEntryLen + rls + al, l
 }
 
-func (tx *MassTransferV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *MassTransferV1) BodyMarshalBinary() ([]byte, error) {
 	var p int
 	n := len(tx.Transfers)
 	bl, al := tx.bodyAndAssetLen()
@@ -1550,7 +1550,7 @@ func (tx *MassTransferV1) bodyUnmarshalBinary(data []byte) error {
 
 //Sign calculates signature and ID of the transaction.
 func (tx *MassTransferV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign MassTransferV1 transaction")
 	}
@@ -1571,7 +1571,7 @@ func (tx *MassTransferV1) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that the signature is valid for the given public key.
 func (tx *MassTransferV1) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of MassTransferV1 transaction")
 	}
@@ -1580,7 +1580,7 @@ func (tx *MassTransferV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary saves the transaction to its binary representation.
 func (tx *MassTransferV1) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal MassTransferV1 transaction to bytes")
 	}
@@ -1926,7 +1926,7 @@ func (tx SetScriptV1) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *SetScriptV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -1963,6 +1963,11 @@ func (tx SetScriptV1) Valid() (bool, error) {
 	if !validJVMLong(tx.Fee) {
 		return false, errors.New("fee is too big")
 	}
+	if tx.NonEmptyScript() {
+		if _, err := tx.Script.Header(); err != nil {
+			return false, errors.Wrap(err, "invalid script")
+		}
+	}
 	return true, nil
 }
 
@@ -1971,7 +1976,7 @@ func (tx *SetScriptV1) NonEmptyScript() bool {
 	return len(tx.Script) != 0
 }
 
-func (tx *SetScriptV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *SetScriptV1) BodyMarshalBinary() ([]byte, error) {
 	var p int
 	sl := 0
 	if tx.NonEmptyScript() {
@@ -2035,7 +2040,7 @@ func (tx *SetScriptV1) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds signature as a proof at first position.
 func (tx *SetScriptV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign SetScriptV1 transaction")
 	}
@@ -2056,7 +2061,7 @@ func (tx *SetScriptV1) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that first proof is a valid signature.
 func (tx *SetScriptV1) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of SetScriptV1 transaction")
 	}
@@ -2065,7 +2070,7 @@ func (tx *SetScriptV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary writes SetScriptV1 transaction to its bytes representation.
 func (tx *SetScriptV1) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal SetScriptV1 transaction to bytes")
 	}
@@ -2136,7 +2141,7 @@ func (tx SponsorshipV1) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *SponsorshipV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -2179,7 +2184,7 @@ func (tx SponsorshipV1) Valid() (bool, error) {
 	return true, nil
 }
 
-func (tx *SponsorshipV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *SponsorshipV1) BodyMarshalBinary() ([]byte, error) {
 	var p int
 	buf := make([]byte, sponsorshipV1BodyLen)
 	buf[p] = byte(tx.Type)
@@ -2225,7 +2230,7 @@ func (tx *SponsorshipV1) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds signature as a proof at first position.
 func (tx *SponsorshipV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign SponsorshipV1 transaction")
 	}
@@ -2246,7 +2251,7 @@ func (tx *SponsorshipV1) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that first proof is a valid signature.
 func (tx *SponsorshipV1) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of SponsorshipV1 transaction")
 	}
@@ -2255,7 +2260,7 @@ func (tx *SponsorshipV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary writes SponsorshipV1 transaction to its bytes representation.
 func (tx *SponsorshipV1) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal SponsorshipV1 transaction to bytes")
 	}
@@ -2334,7 +2339,7 @@ func (tx SetAssetScriptV1) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *SetAssetScriptV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -2371,7 +2376,10 @@ func (tx SetAssetScriptV1) Valid() (bool, error) {
 	if !validJVMLong(tx.Fee) {
 		return false, errors.New("fee is too big")
 	}
-	//TODO: validate blockchain scheme and script type
+	if err := tx.Script.validateExpression(); err != nil {
+		return false, err
+	}
+	//TODO: validate blockchain scheme
 	return true, nil
 }
 
@@ -2380,7 +2388,7 @@ func (tx *SetAssetScriptV1) NonEmptyScript() bool {
 	return len(tx.Script) != 0
 }
 
-func (tx *SetAssetScriptV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *SetAssetScriptV1) BodyMarshalBinary() ([]byte, error) {
 	var p int
 	sl := 0
 	if tx.NonEmptyScript() {
@@ -2449,7 +2457,7 @@ func (tx *SetAssetScriptV1) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds signature as a proof at first position.
 func (tx *SetAssetScriptV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign SetAssetScriptV1 transaction")
 	}
@@ -2470,7 +2478,7 @@ func (tx *SetAssetScriptV1) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that first proof is a valid signature.
 func (tx *SetAssetScriptV1) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of SetAssetScriptV1 transaction")
 	}
@@ -2479,7 +2487,7 @@ func (tx *SetAssetScriptV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary writes SetAssetScriptV1 transaction to its bytes representation.
 func (tx *SetAssetScriptV1) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal SetAssetScriptV1 transaction to bytes")
 	}
@@ -2548,7 +2556,7 @@ type InvokeScriptV1 struct {
 
 func (tx *InvokeScriptV1) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -2629,7 +2637,7 @@ func (tx InvokeScriptV1) Valid() (bool, error) {
 	return true, nil
 }
 
-func (tx *InvokeScriptV1) bodyMarshalBinary() ([]byte, error) {
+func (tx *InvokeScriptV1) BodyMarshalBinary() ([]byte, error) {
 	p := 0
 	buf := make([]byte, invokeScriptV1FixedBodyLen+tx.ScriptRecipient.len+tx.FunctionCall.binarySize()+tx.Payments.binarySize()+tx.FeeAsset.binarySize())
 	buf[p] = byte(tx.Type)
@@ -2728,7 +2736,7 @@ func (tx *InvokeScriptV1) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds signature as a proof at first position.
 func (tx *InvokeScriptV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign InvokeScriptV1 transaction")
 	}
@@ -2749,7 +2757,7 @@ func (tx *InvokeScriptV1) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that first proof is a valid signature.
 func (tx *InvokeScriptV1) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of InvokeScriptV1 transaction")
 	}
@@ -2758,7 +2766,7 @@ func (tx *InvokeScriptV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary writes InvokeScriptV1 transaction to its bytes representation.
 func (tx *InvokeScriptV1) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal InvokeScriptV1 transaction to bytes")
 	}
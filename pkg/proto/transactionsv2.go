@@ -45,7 +45,7 @@ func (tx IssueV2) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *IssueV2) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -82,7 +82,10 @@ func (tx IssueV2) Valid() (bool, error) {
 	if !ok {
 		return false, err
 	}
-	//TODO: add script and scheme validations
+	if err := tx.Script.validateExpression(); err != nil {
+		return false, err
+	}
+	//TODO: add scheme validation
 	return true, nil
 }
 
@@ -91,7 +94,7 @@ func (tx *IssueV2) NonEmptyScript() bool {
 	return len(tx.Script) != 0
 }
 
-func (tx *IssueV2) bodyMarshalBinary() ([]byte, error) {
+func (tx *IssueV2) BodyMarshalBinary() ([]byte, error) {
 	var p int
 	nl := len(tx.Name)
 	dl := len(tx.Description)
@@ -183,7 +186,7 @@ func (tx *IssueV2) bodyUnmarshalBinary(data []byte) error {
 
 //Sign calculates transaction signature using given secret key.
 func (tx *IssueV2) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign IssueV2 transaction")
 	}
@@ -204,7 +207,7 @@ func (tx *IssueV2) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that the transaction signature is valid for given public key.
 func (tx *IssueV2) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of IssueV2 transaction")
 	}
@@ -213,7 +216,7 @@ func (tx *IssueV2) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary converts transaction to its binary representation.
 func (tx *IssueV2) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal IssueV2 transaction to bytes")
 	}
@@ -465,7 +468,7 @@ func (tx ReissueV2) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *ReissueV2) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -504,7 +507,7 @@ func (tx ReissueV2) Valid() (bool, error) {
 	return true, nil
 }
 
-func (tx *ReissueV2) bodyMarshalBinary() ([]byte, error) {
+func (tx *ReissueV2) BodyMarshalBinary() ([]byte, error) {
 	buf := make([]byte, reissueV2BodyLen)
 	buf[0] = byte(tx.Type)
 	buf[1] = tx.Version
@@ -541,7 +544,7 @@ func (tx *ReissueV2) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds signature as a proof at first position.
 func (tx *ReissueV2) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign ReissueV2 transaction")
 	}
@@ -562,7 +565,7 @@ func (tx *ReissueV2) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that first proof is a valid signature.
 func (tx *ReissueV2) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of ReissueV2 transaction")
 	}
@@ -571,7 +574,7 @@ func (tx *ReissueV2) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary writes ReissueV2 transaction to its bytes representation.
 func (tx *ReissueV2) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal ReissueV2 transaction to bytes")
 	}
@@ -635,7 +638,7 @@ func (tx BurnV2) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *BurnV2) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -673,7 +676,7 @@ func (tx BurnV2) Valid() (bool, error) {
 	return true, nil
 }
 
-func (tx *BurnV2) bodyMarshalBinary() ([]byte, error) {
+func (tx *BurnV2) BodyMarshalBinary() ([]byte, error) {
 	buf := make([]byte, burnV2BodyLen)
 	buf[0] = byte(tx.Type)
 	buf[1] = tx.Version
@@ -710,7 +713,7 @@ func (tx *BurnV2) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds signature as a proof at first position.
 func (tx *BurnV2) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign BurnV2 transaction")
 	}
@@ -731,7 +734,7 @@ func (tx *BurnV2) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that first proof is a valid signature.
 func (tx *BurnV2) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of BurnV2 transaction")
 	}
@@ -740,7 +743,7 @@ func (tx *BurnV2) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary writes BurnV2 transaction to its bytes representation.
 func (tx *BurnV2) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal BurnV2 transaction to bytes")
 	}
@@ -811,7 +814,7 @@ func (tx ExchangeV2) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *ExchangeV2) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -997,7 +1000,7 @@ func (tx *ExchangeV2) marshalAsOrderV3(order Order) ([]byte, error) {
 	return buf, nil
 }
 
-func (tx *ExchangeV2) bodyMarshalBinary() ([]byte, error) {
+func (tx *ExchangeV2) BodyMarshalBinary() ([]byte, error) {
 	var bob []byte
 	var sob []byte
 	var err error
@@ -1139,7 +1142,7 @@ func (tx *ExchangeV2) bodyUnmarshalBinary(data []byte) (int, error) {
 
 //Sign calculates transaction signature using given secret key.
 func (tx *ExchangeV2) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign ExchangeV2 transaction")
 	}
@@ -1160,7 +1163,7 @@ func (tx *ExchangeV2) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that the transaction signature is valid for given public key.
 func (tx *ExchangeV2) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of ExchangeV2 transaction")
 	}
@@ -1169,7 +1172,7 @@ func (tx *ExchangeV2) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary saves the transaction to its binary representation.
 func (tx *ExchangeV2) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal ExchangeV2 transaction to bytes")
 	}
@@ -1286,7 +1289,7 @@ func (tx LeaseV2) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *LeaseV2) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -1315,7 +1318,7 @@ func NewUnsignedLeaseV2(senderPK crypto.PublicKey, recipient Recipient, amount,
 	return &LeaseV2{Type: LeaseTransaction, Version: 2, Lease: l}
 }
 
-func (tx *LeaseV2) bodyMarshalBinary() ([]byte, error) {
+func (tx *LeaseV2) BodyMarshalBinary() ([]byte, error) {
 	rl := tx.Recipient.len
 	buf := make([]byte, leaseV2BodyLen+rl)
 	buf[0] = byte(tx.Type)
@@ -1352,7 +1355,7 @@ func (tx *LeaseV2) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds signature as a proof at first position.
 func (tx *LeaseV2) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign LeaseV2 transaction")
 	}
@@ -1373,7 +1376,7 @@ func (tx *LeaseV2) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that first proof is a valid signature.
 func (tx *LeaseV2) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of LeaseV2 transaction")
 	}
@@ -1382,7 +1385,7 @@ func (tx *LeaseV2) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary saves the transaction to its binary representation.
 func (tx *LeaseV2) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal LeaseV2 transaction to bytes")
 	}
@@ -1447,7 +1450,7 @@ func (tx LeaseCancelV2) GetTypeVersion() TransactionTypeVersion {
 
 func (tx *LeaseCancelV2) GenerateID() {
 	if tx.ID == nil {
-		body, err := tx.bodyMarshalBinary()
+		body, err := tx.BodyMarshalBinary()
 		if err != nil {
 			panic(err.Error())
 		}
@@ -1484,7 +1487,7 @@ func (tx LeaseCancelV2) Valid() (bool, error) {
 	return true, nil
 }
 
-func (tx *LeaseCancelV2) bodyMarshalBinary() ([]byte, error) {
+func (tx *LeaseCancelV2) BodyMarshalBinary() ([]byte, error) {
 	buf := make([]byte, leaseCancelV2BodyLen)
 	buf[0] = byte(tx.Type)
 	buf[1] = tx.Version
@@ -1522,7 +1525,7 @@ func (tx *LeaseCancelV2) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds signature as a proof at first position.
 func (tx *LeaseCancelV2) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign LeaseCancelV2 transaction")
 	}
@@ -1543,7 +1546,7 @@ func (tx *LeaseCancelV2) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that first proof is a valid signature.
 func (tx *LeaseCancelV2) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of LeaseCancelV2 transaction")
 	}
@@ -1552,7 +1555,7 @@ func (tx *LeaseCancelV2) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary saves the transaction to its binary representation.
 func (tx *LeaseCancelV2) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal LeaseCancelV2 transaction to bytes")
 	}
@@ -1640,7 +1643,7 @@ func NewUnsignedCreateAliasV2(senderPK crypto.PublicKey, alias Alias, fee, times
 	return &CreateAliasV2{Type: CreateAliasTransaction, Version: 2, CreateAlias: ca}
 }
 
-func (tx *CreateAliasV2) bodyMarshalBinary() ([]byte, error) {
+func (tx *CreateAliasV2) BodyMarshalBinary() ([]byte, error) {
 	buf := make([]byte, createAliasV2FixedBodyLen+len(tx.Alias.Alias))
 	buf[0] = byte(tx.Type)
 	buf[1] = tx.Version
@@ -1675,7 +1678,7 @@ func (tx *CreateAliasV2) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds signature as a proof at first position.
 func (tx *CreateAliasV2) Sign(secretKey crypto.SecretKey) error {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign CreateAliasV2 transaction")
 	}
@@ -1695,7 +1698,7 @@ func (tx *CreateAliasV2) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that first proof is a valid signature.
 func (tx *CreateAliasV2) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := tx.bodyMarshalBinary()
+	b, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of CreateAliasV2 transaction")
 	}
@@ -1704,7 +1707,7 @@ func (tx *CreateAliasV2) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary saves the transaction to its binary representation.
 func (tx *CreateAliasV2) MarshalBinary() ([]byte, error) {
-	bb, err := tx.bodyMarshalBinary()
+	bb, err := tx.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal CreateAliasV2 transaction to bytes")
 	}
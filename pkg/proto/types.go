@@ -337,25 +337,26 @@ type Order interface {
 	GetAssetPair() AssetPair
 	GetPrice() uint64
 	GetExpiration() uint64
+	GetID() ([]byte, error)
 	Valid() (bool, error)
 }
 
 func OrderToOrderBody(o Order) (OrderBody, error) {
-	switch o.GetVersion() {
-	case 1:
-		o, ok := o.(OrderV1)
-		if !ok {
-			return OrderBody{}, errors.New("failed to cast an order version 1 to OrderV1")
-		}
+	switch o := o.(type) {
+	case OrderV1:
 		return o.OrderBody, nil
-	case 2:
-		o, ok := o.(OrderV2)
-		if !ok {
-			return OrderBody{}, errors.New("failed to cast an order version 2 to OrderV2")
-		}
+	case *OrderV1:
+		return o.OrderBody, nil
+	case OrderV2:
+		return o.OrderBody, nil
+	case *OrderV2:
+		return o.OrderBody, nil
+	case OrderV3:
+		return o.OrderBody, nil
+	case *OrderV3:
 		return o.OrderBody, nil
 	default:
-		return OrderBody{}, errors.New("invalid order version")
+		return OrderBody{}, errors.Errorf("invalid order version %d", o.GetVersion())
 	}
 }
 
@@ -593,7 +594,23 @@ func (o OrderV1) GetExpiration() uint64 {
 	return o.Expiration
 }
 
-func (o *OrderV1) bodyMarshalBinary() ([]byte, error) {
+//GetID returns the ID of the order, it is calculated from order's body if not set.
+func (o OrderV1) GetID() ([]byte, error) {
+	if o.ID != nil {
+		return o.ID.Bytes(), nil
+	}
+	b, err := o.BodyMarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get OrderV1 ID")
+	}
+	d, err := crypto.FastHash(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get OrderV1 ID")
+	}
+	return d.Bytes(), nil
+}
+
+func (o *OrderV1) BodyMarshalBinary() ([]byte, error) {
 	return o.OrderBody.marshalBinary()
 }
 
@@ -603,7 +620,7 @@ func (o *OrderV1) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds a signature to the order.
 func (o *OrderV1) Sign(secretKey crypto.SecretKey) error {
-	b, err := o.bodyMarshalBinary()
+	b, err := o.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign OrderV1")
 	}
@@ -622,7 +639,7 @@ func (o *OrderV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 	if o.Signature == nil {
 		return false, errors.New("empty signature")
 	}
-	b, err := o.bodyMarshalBinary()
+	b, err := o.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of OrderV1")
 	}
@@ -631,7 +648,7 @@ func (o *OrderV1) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary writes order to its bytes representation.
 func (o *OrderV1) MarshalBinary() ([]byte, error) {
-	b, err := o.bodyMarshalBinary()
+	b, err := o.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal OrderV1 to bytes")
 	}
@@ -722,7 +739,23 @@ func (o OrderV2) GetExpiration() uint64 {
 	return o.Expiration
 }
 
-func (o *OrderV2) bodyMarshalBinary() ([]byte, error) {
+//GetID returns the ID of the order, it is calculated from order's body if not set.
+func (o OrderV2) GetID() ([]byte, error) {
+	if o.ID != nil {
+		return o.ID.Bytes(), nil
+	}
+	b, err := o.BodyMarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get OrderV2 ID")
+	}
+	d, err := crypto.FastHash(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get OrderV2 ID")
+	}
+	return d.Bytes(), nil
+}
+
+func (o *OrderV2) BodyMarshalBinary() ([]byte, error) {
 	aal := 0
 	if o.AssetPair.AmountAsset.Present {
 		aal += crypto.DigestSize
@@ -760,7 +793,7 @@ func (o *OrderV2) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds a signature to the order.
 func (o *OrderV2) Sign(secretKey crypto.SecretKey) error {
-	b, err := o.bodyMarshalBinary()
+	b, err := o.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign OrderV2")
 	}
@@ -781,7 +814,10 @@ func (o *OrderV2) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that the order's signature is valid.
 func (o *OrderV2) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := o.bodyMarshalBinary()
+	if o.Proofs == nil {
+		return false, errors.New("empty proofs")
+	}
+	b, err := o.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of OrderV2")
 	}
@@ -790,7 +826,7 @@ func (o *OrderV2) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary writes order to its bytes representation.
 func (o *OrderV2) MarshalBinary() ([]byte, error) {
-	bb, err := o.bodyMarshalBinary()
+	bb, err := o.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal OrderV2 to bytes")
 	}
@@ -889,7 +925,23 @@ func (o OrderV3) GetExpiration() uint64 {
 	return o.Expiration
 }
 
-func (o *OrderV3) bodyMarshalBinary() ([]byte, error) {
+//GetID returns the ID of the order, it is calculated from order's body if not set.
+func (o OrderV3) GetID() ([]byte, error) {
+	if o.ID != nil {
+		return o.ID.Bytes(), nil
+	}
+	b, err := o.BodyMarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get OrderV3 ID")
+	}
+	d, err := crypto.FastHash(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get OrderV3 ID")
+	}
+	return d.Bytes(), nil
+}
+
+func (o *OrderV3) BodyMarshalBinary() ([]byte, error) {
 	aal := 0
 	if o.AssetPair.AmountAsset.Present {
 		aal += crypto.DigestSize
@@ -952,7 +1004,7 @@ func (o *OrderV3) bodyUnmarshalBinary(data []byte) error {
 
 //Sign adds a signature to the order.
 func (o *OrderV3) Sign(secretKey crypto.SecretKey) error {
-	b, err := o.bodyMarshalBinary()
+	b, err := o.BodyMarshalBinary()
 	if err != nil {
 		return errors.Wrap(err, "failed to sign OrderV3")
 	}
@@ -973,7 +1025,10 @@ func (o *OrderV3) Sign(secretKey crypto.SecretKey) error {
 
 //Verify checks that the order's signature is valid.
 func (o *OrderV3) Verify(publicKey crypto.PublicKey) (bool, error) {
-	b, err := o.bodyMarshalBinary()
+	if o.Proofs == nil {
+		return false, errors.New("empty proofs")
+	}
+	b, err := o.BodyMarshalBinary()
 	if err != nil {
 		return false, errors.Wrap(err, "failed to verify signature of OrderV3")
 	}
@@ -982,7 +1037,7 @@ func (o *OrderV3) Verify(publicKey crypto.PublicKey) (bool, error) {
 
 //MarshalBinary writes order to its bytes representation.
 func (o *OrderV3) MarshalBinary() ([]byte, error) {
-	bb, err := o.bodyMarshalBinary()
+	bb, err := o.BodyMarshalBinary()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal OrderV3 to bytes")
 	}
@@ -1665,6 +1720,26 @@ func (s Script) String() string {
 	return sb.String()
 }
 
+// Header validates checksum of the script and decodes its header: content type and version of standard library.
+func (s Script) Header() (*reader.ScriptHeader, error) {
+	return reader.ReadHeader(s)
+}
+
+// validateExpression checks that non-empty script is a valid expression, assets can't have DApp scripts.
+func (s Script) validateExpression() error {
+	if len(s) == 0 {
+		return nil
+	}
+	h, err := s.Header()
+	if err != nil {
+		return errors.Wrap(err, "invalid script")
+	}
+	if h.ContentType != reader.ContentTypeExpression {
+		return errors.Wrap(&reader.ContentTypeError{ContentType: h.ContentType}, "invalid script")
+	}
+	return nil
+}
+
 // MarshalJSON writes Script as JSON
 func (s Script) MarshalJSON() ([]byte, error) {
 	var sb strings.Builder
@@ -68,7 +68,7 @@ func TestMainNetOrder(t *testing.T) {
 		aa, _ := NewOptionalAssetFromString(tc.amountAsset)
 		pa, _ := NewOptionalAssetFromString(tc.priceAsset)
 		o := NewUnsignedOrderV1(spk, mpk, *aa, *pa, tc.orderType, tc.price, tc.amount, tc.timestamp, tc.expiration, tc.fee)
-		if b, err := o.bodyMarshalBinary(); assert.NoError(t, err) {
+		if b, err := o.BodyMarshalBinary(); assert.NoError(t, err) {
 			d, _ := crypto.FastHash(b)
 			assert.Equal(t, id, d)
 			assert.True(t, crypto.Verify(spk, sig, b))
@@ -522,6 +522,42 @@ func TestOrderV3ToJSON(t *testing.T) {
 	}
 }
 
+func TestOrderToOrderBody(t *testing.T) {
+	_, pk := crypto.GenerateKeyPair([]byte("order to order body test seed"))
+	mpk, _ := crypto.NewPublicKeyFromBase58("7kPFrHDiGw1rCm7LPszuECwWYL3dMf6iMifLRDJQZMzy")
+	aa, _ := NewOptionalAssetFromString("8LQW8f7P5d5PZM7GtZEBgaqRPGSzS3DfPuiXrURJ4AJS")
+	pa, _ := NewOptionalAssetFromString("WAVES")
+	o1 := NewUnsignedOrderV1(pk, mpk, *aa, *pa, Buy, 100, 10, 1, 2, 3)
+	o2 := NewUnsignedOrderV2(pk, mpk, *aa, *pa, Buy, 100, 10, 1, 2, 3)
+	o3 := NewUnsignedOrderV3(pk, mpk, *aa, *pa, Buy, 100, 10, 1, 2, 3, *aa)
+	for _, o := range []Order{o1, *o1, o2, *o2, o3, *o3} {
+		if body, err := OrderToOrderBody(o); assert.NoError(t, err) {
+			assert.Equal(t, o1.OrderBody, body)
+		}
+	}
+}
+
+func TestOrderGetID(t *testing.T) {
+	sk, pk := crypto.GenerateKeyPair([]byte("order get id test seed"))
+	mpk, _ := crypto.NewPublicKeyFromBase58("7kPFrHDiGw1rCm7LPszuECwWYL3dMf6iMifLRDJQZMzy")
+	aa, _ := NewOptionalAssetFromString("8LQW8f7P5d5PZM7GtZEBgaqRPGSzS3DfPuiXrURJ4AJS")
+	pa, _ := NewOptionalAssetFromString("WAVES")
+	o1 := NewUnsignedOrderV1(pk, mpk, *aa, *pa, Sell, 100, 10, 1, 2, 3)
+	o2 := NewUnsignedOrderV2(pk, mpk, *aa, *pa, Sell, 100, 10, 1, 2, 3)
+	o3 := NewUnsignedOrderV3(pk, mpk, *aa, *pa, Sell, 100, 10, 1, 2, 3, *aa)
+	unsigned := []Order{*o1, *o2, *o3}
+	require.NoError(t, o1.Sign(sk))
+	require.NoError(t, o2.Sign(sk))
+	require.NoError(t, o3.Sign(sk))
+	for i, o := range []Order{o1, o2, o3} {
+		id, err := o.GetID()
+		require.NoError(t, err)
+		calculated, err := unsigned[i].GetID()
+		require.NoError(t, err)
+		assert.Equal(t, id, calculated)
+	}
+}
+
 func TestIntegerDataEntryBinaryRoundTrip(t *testing.T) {
 	tests := []struct {
 		key   string
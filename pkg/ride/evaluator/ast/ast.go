@@ -6,7 +6,9 @@ import (
 	"github.com/mr-tron/base58/base58"
 	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/mockstate"
 	"io"
+	"strings"
 )
 
 const InstanceFieldName = "$instance"
@@ -27,7 +29,7 @@ func (a Exprs) Write(w io.Writer) {
 }
 
 func (a Exprs) Evaluate(s Scope) (Expr, error) {
-	return nil, errors.New("Exprs Evaluate")
+	return a, nil
 }
 
 func (a Exprs) EvaluateAll(s Scope) (Exprs, error) {
@@ -67,7 +69,7 @@ func (a *Block) Write(w io.Writer) {
 }
 
 func (a *Block) Evaluate(s Scope) (Expr, error) {
-	s.AddValue(a.Let.Name, a.Let.Value)
+	a.Let.Declare(s)
 	return a.Body.Evaluate(s.Clone())
 }
 
@@ -89,6 +91,10 @@ func (a *LetExpr) Write(w io.Writer) {
 	a.Value.Write(w)
 }
 
+func (a *LetExpr) Declare(s Scope) {
+	s.AddValue(a.Name, &lazyValue{expr: a.Value, scope: s})
+}
+
 func NewLet(name string, value Expr) *LetExpr {
 	return &LetExpr{
 		Name:  name,
@@ -96,6 +102,116 @@ func NewLet(name string, value Expr) *LetExpr {
 	}
 }
 
+// lazyValue is a value of let declaration. It is evaluated in the scope of declaration
+// on the first reference and then reused, so the same declaration evaluated
+// in different scopes (e.g. in the body of user function) gets its own value.
+type lazyValue struct {
+	expr      Expr
+	scope     Scope
+	evaluated bool
+	value     Expr
+	err       error
+}
+
+func (a *lazyValue) Write(w io.Writer) {
+	a.expr.Write(w)
+}
+
+func (a *lazyValue) Evaluate(Scope) (Expr, error) {
+	if !a.evaluated {
+		a.value, a.err = a.expr.Evaluate(a.scope.Clone())
+		a.evaluated = true
+	}
+	return a.value, a.err
+}
+
+func (a *lazyValue) Eq(other Expr) (bool, error) {
+	return false, errors.Errorf("trying to compare %T with %T", a, other)
+}
+
+func (a *lazyValue) InstanceOf() string {
+	return "LazyValue"
+}
+
+// Declaration is a let or function declaration, which adds its name to the scope.
+type Declaration interface {
+	Write(io.Writer)
+	Declare(Scope)
+}
+
+type FuncDeclaration struct {
+	Name string
+	Args []string
+	Body Expr
+}
+
+func NewFuncDeclaration(name string, args []string, body Expr) *FuncDeclaration {
+	return &FuncDeclaration{
+		Name: name,
+		Args: args,
+		Body: body,
+	}
+}
+
+func (a *FuncDeclaration) Write(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "func %s(%s) = ", a.Name, strings.Join(a.Args, ", "))
+	a.Body.Write(w)
+}
+
+func (a *FuncDeclaration) Declare(s Scope) {
+	s.AddFunction(a.Name, a.Callable(s))
+}
+
+// Callable returns function which evaluates body of declared function in the given scope
+// with arguments bound to their names.
+func (a *FuncDeclaration) Callable(s Scope) Callable {
+	return func(callScope Scope, e Exprs) (Expr, error) {
+		if l := len(e); l != len(a.Args) {
+			return nil, errors.Errorf("%s: invalid params, expected %d, passed %d", a.Name, len(a.Args), l)
+		}
+		args, err := e.EvaluateAll(callScope)
+		if err != nil {
+			return nil, errors.Wrap(err, a.Name)
+		}
+		funcScope := s.Clone()
+		for i, name := range a.Args {
+			funcScope.AddValue(name, args[i])
+		}
+		return a.Body.Evaluate(funcScope)
+	}
+}
+
+type BlockV2 struct {
+	Decl Declaration
+	Body Expr
+}
+
+func NewBlockV2(decl Declaration, body Expr) *BlockV2 {
+	return &BlockV2{
+		Decl: decl,
+		Body: body,
+	}
+}
+
+func (a *BlockV2) Write(w io.Writer) {
+	a.Decl.Write(w)
+	_, _ = fmt.Fprintf(w, "\n")
+	a.Body.Write(w)
+}
+
+func (a *BlockV2) Evaluate(s Scope) (Expr, error) {
+	a.Decl.Declare(s)
+	return a.Body.Evaluate(s.Clone())
+}
+
+func (a *BlockV2) Eq(other Expr) (bool, error) {
+	return false, errors.Errorf("trying to compare %T with %T", a, other)
+}
+
+func (a *BlockV2) InstanceOf() string {
+	return "BlockV2"
+}
+
 type LongExpr struct {
 	Value int64
 }
@@ -258,14 +374,7 @@ func (a *UserFunction) InstanceOf() string {
 }
 
 type RefExpr struct {
-	Name   string
-	cached bool
-	cache  RefCache
-}
-
-type RefCache struct {
-	Expr Expr
-	Err  error
+	Name string
 }
 
 func (a *RefExpr) Write(w io.Writer) {
@@ -273,25 +382,11 @@ func (a *RefExpr) Write(w io.Writer) {
 }
 
 func (a *RefExpr) Evaluate(s Scope) (Expr, error) {
-
-	if a.cached {
-		return a.cache.Expr, a.cache.Err
-	}
-
 	expr, ok := s.Value(a.Name)
 	if !ok {
 		return nil, errors.Errorf("RefExpr evaluate: not found expr by name %s", a.Name)
 	}
-
-	rs, err := expr.Evaluate(s.Clone())
-
-	a.cache = RefCache{
-		Expr: rs,
-		Err:  err,
-	}
-	a.cached = true
-
-	return a.cache.Expr, a.cache.Err
+	return expr.Evaluate(s.Clone())
 }
 
 func (a *RefExpr) Eq(other Expr) (bool, error) {
@@ -327,6 +422,11 @@ func (a *IfExpr) Write(w io.Writer) {
 }
 
 func (a *IfExpr) Evaluate(s Scope) (Expr, error) {
+	return s.EvaluateIf(a)
+}
+
+// evaluate stores the taken branch in the trace step, if it is not nil.
+func (a *IfExpr) evaluate(s Scope, step *TraceStep) (Expr, error) {
 	cond, err := a.Condition.Evaluate(s.Clone())
 	if err != nil {
 		return nil, err
@@ -336,6 +436,9 @@ func (a *IfExpr) Evaluate(s Scope) (Expr, error) {
 	if !ok {
 		return nil, errors.Errorf("IfExpr evaluate: expected bool in condition found %T", cond)
 	}
+	if step != nil {
+		step.Branch = b.Value
+	}
 
 	if b.Value {
 		return a.True.Evaluate(s.Clone())
@@ -543,6 +646,34 @@ func NewAddressFromString(s string) (AddressExpr, error) {
 	return AddressExpr(addr), err
 }
 
+// NewAssetInfo returns object of type Asset.
+func NewAssetInfo(info *mockstate.AssetInfo) *ObjectExpr {
+	return NewObject(map[string]Expr{
+		"id":              NewBytes(info.ID.Bytes()),
+		"quantity":        NewLong(info.Quantity),
+		"decimals":        NewLong(info.Decimals),
+		"issuer":          NewAddressFromProtoAddress(info.Issuer),
+		"issuerPublicKey": NewBytes(info.IssuerPublicKey.Bytes()),
+		"reissuable":      NewBoolean(info.Reissuable),
+		"scripted":        NewBoolean(info.Scripted),
+		"sponsored":       NewBoolean(info.Sponsored),
+		InstanceFieldName: NewString("Asset"),
+	})
+}
+
+// NewBlockInfo returns object of type BlockInfo.
+func NewBlockInfo(info *mockstate.BlockInfo) *ObjectExpr {
+	return NewObject(map[string]Expr{
+		"timestamp":           NewLong(int64(info.Timestamp)),
+		"height":              NewLong(int64(info.Height)),
+		"baseTarget":          NewLong(int64(info.BaseTarget)),
+		"generationSignature": NewBytes(info.GenerationSignature.Bytes()),
+		"generator":           NewAddressFromProtoAddress(info.Generator),
+		"generatorPublicKey":  NewBytes(info.GeneratorPublicKey.Bytes()),
+		InstanceFieldName:     NewString("BlockInfo"),
+	})
+}
+
 func NewAddressFromProtoAddress(a proto.Address) AddressExpr {
 	return AddressExpr(a)
 }
@@ -626,19 +757,8 @@ func (a *DataEntryListExpr) Get(key string, valueType proto.DataValueType) Expr
 		a.cache()
 	}
 	rs, ok := a.data[key]
-	if ok {
-		if rs.GetValueType() == valueType {
-			switch valueType {
-			case proto.DataInteger:
-				return NewLong(rs.(proto.IntegerDataEntry).Value)
-			case proto.DataString:
-				return NewString(rs.(proto.StringDataEntry).Value)
-			case proto.DataBoolean:
-				return NewBoolean(rs.(proto.BooleanDataEntry).Value)
-			case proto.DataBinary:
-				return NewBytes(rs.(proto.BinaryDataEntry).Value)
-			}
-		}
+	if ok && rs.GetValueType() == valueType {
+		return dataEntryValue(rs)
 	}
 	return Unit{}
 }
@@ -652,16 +772,28 @@ func (a *DataEntryListExpr) GetByIndex(index int, valueType proto.DataValueType)
 	if rs.GetValueType() != valueType {
 		return NewUnit()
 	}
-
-	switch valueType {
-	case proto.DataInteger:
-		return NewLong(rs.(proto.IntegerDataEntry).Value)
-	case proto.DataString:
-		return NewString(rs.(proto.StringDataEntry).Value)
-	case proto.DataBoolean:
-		return NewBoolean(rs.(proto.BooleanDataEntry).Value)
-	case proto.DataBinary:
-		return NewBytes(rs.(proto.BinaryDataEntry).Value)
+	return dataEntryValue(rs)
+}
+
+// dataEntryValue converts value of data entry, unmarshalled entries are pointers.
+func dataEntryValue(e proto.DataEntry) Expr {
+	switch v := e.(type) {
+	case proto.IntegerDataEntry:
+		return NewLong(v.Value)
+	case *proto.IntegerDataEntry:
+		return NewLong(v.Value)
+	case proto.StringDataEntry:
+		return NewString(v.Value)
+	case *proto.StringDataEntry:
+		return NewString(v.Value)
+	case proto.BooleanDataEntry:
+		return NewBoolean(v.Value)
+	case *proto.BooleanDataEntry:
+		return NewBoolean(v.Value)
+	case proto.BinaryDataEntry:
+		return NewBytes(v.Value)
+	case *proto.BinaryDataEntry:
+		return NewBytes(v.Value)
 	default:
 		return NewUnit()
 	}
@@ -27,3 +27,46 @@ func TestDataEntryListExpr_Get(t *testing.T) {
 	lst := NewDataEntryList(d)
 	assert.Equal(t, NewLong(100500), lst.Get("integer", proto.DataInteger))
 }
+
+func TestFuncDeclaration(t *testing.T) {
+	// func inc(x) = { let y = x; y + 1 }
+	body := NewBlockV2(NewLet("y", &RefExpr{Name: "x"}), NewFuncCall(NewNativeFunction(100, 2, Params(&RefExpr{Name: "y"}, NewLong(1)))))
+	funcs, err := NewFuncScope(1)
+	assert.NoError(t, err)
+	s := NewScope(proto.MainNetScheme, nil, funcs, nil)
+	NewFuncDeclaration("inc", []string{"x"}, body).Declare(s)
+
+	// the same declaration evaluates to different values with different arguments
+	call := NewFuncCall(NewUserFunction("inc", 1, Params(NewLong(1))))
+	rs, err := call.Evaluate(s.Clone())
+	assert.NoError(t, err)
+	assert.Equal(t, NewLong(2), rs)
+
+	call = NewFuncCall(NewUserFunction("inc", 1, Params(NewLong(5))))
+	rs, err = call.Evaluate(s.Clone())
+	assert.NoError(t, err)
+	assert.Equal(t, NewLong(6), rs)
+
+	_, err = NewFuncCall(NewUserFunction("inc", 0, nil)).Evaluate(s.Clone())
+	assert.Error(t, err)
+}
+
+func TestArgType(t *testing.T) {
+	assert.Equal(t, "Int", ArgInt.String())
+	assert.Equal(t, "ByteVector|String", (ArgBytes | ArgString).String())
+	assert.True(t, ArgInt.Accepts(NewLong(1)))
+	assert.False(t, ArgInt.Accepts(NewString("1")))
+	assert.True(t, (ArgBoolean | ArgString).Accepts(NewBoolean(true)))
+	assert.True(t, (ArgBoolean | ArgString).Accepts(NewString("true")))
+	assert.False(t, (ArgBoolean | ArgString).Accepts(NewBytes([]byte("true"))))
+}
+
+func TestDataEntryListExpr_Pointers(t *testing.T) {
+	lst := NewDataEntryList([]proto.DataEntry{
+		&proto.IntegerDataEntry{Key: "integer", Value: 100500},
+		&proto.StringDataEntry{Key: "string", Value: "value"},
+	})
+	assert.Equal(t, NewLong(100500), lst.Get("integer", proto.DataInteger))
+	assert.Equal(t, NewString("value"), lst.GetByIndex(1, proto.DataString))
+	assert.Equal(t, NewUnit(), lst.Get("string", proto.DataInteger))
+}
@@ -0,0 +1,275 @@
+package ast
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Compile resolves calls of standard library functions and references to its constants ahead of time,
+// so that evaluation of the compiled expression doesn't look them up in scope by name or ID.
+// Functions and values declared in script shadow the predefined ones, their references are left as is.
+// Compiled expression evaluates to the same result as the original one, but TracingScope can't
+// record calls of resolved functions.
+func Compile(e Expr, funcs *FuncScope) Expr {
+	c := &compiler{funcs: funcs}
+	return c.compile(e, nil)
+}
+
+// Compile returns the script with compiled verifier.
+func (a *Script) Compile() (*Script, error) {
+	funcs, err := NewFuncScope(a.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &Script{
+		Version:  a.Version,
+		Verifier: Compile(a.Verifier, funcs),
+	}, nil
+}
+
+// Compile returns the DApp with compiled global declarations and functions.
+func (a *ContractScript) Compile() (*ContractScript, error) {
+	funcs, err := NewFuncScope(a.Version)
+	if err != nil {
+		return nil, err
+	}
+	c := &compiler{funcs: funcs}
+	out := &ContractScript{
+		Version:      a.Version,
+		Declarations: make([]Declaration, len(a.Declarations)),
+		Callables:    make(map[string]*AnnotatedFunction, len(a.Callables)),
+	}
+	// Global declarations are visible to the following ones and to all functions of DApp.
+	var globals *binding
+	for i, decl := range a.Declarations {
+		out.Declarations[i], globals = c.compileDeclaration(decl, globals)
+	}
+	compileFunction := func(f *AnnotatedFunction) *AnnotatedFunction {
+		names := &binding{name: f.ArgumentName, parent: globals}
+		decl, _ := c.compileDeclaration(f.Func, names)
+		return &AnnotatedFunction{
+			ArgumentName: f.ArgumentName,
+			Func:         decl.(*FuncDeclaration),
+			ArgTypes:     f.ArgTypes,
+		}
+	}
+	for name, callable := range a.Callables {
+		out.Callables[name] = compileFunction(callable)
+	}
+	if a.HasVerifier() {
+		out.Verifier = compileFunction(a.Verifier)
+	}
+	return out, nil
+}
+
+// binding is a name declared in script, value or function, visible to the compiled expression.
+type binding struct {
+	name     string
+	function bool
+	parent   *binding
+}
+
+func (a *binding) declared(name string, function bool) bool {
+	for b := a; b != nil; b = b.parent {
+		if b.name == name && b.function == function {
+			return true
+		}
+	}
+	return false
+}
+
+type compiler struct {
+	funcs *FuncScope
+}
+
+func (c *compiler) compile(e Expr, names *binding) Expr {
+	switch v := e.(type) {
+	case *FuncCall:
+		// Call wrapper doesn't add anything to evaluation of the function.
+		return c.compile(v.Func, names)
+	case *NativeFunction:
+		args := c.compileAll(v.Argv, names)
+		if f, ok := c.funcs.GetByShort(v.FunctionID); ok {
+			return newCompiledCall(v, f, args)
+		}
+		// Unknown function fails on evaluation, as in the original expression.
+		return NewNativeFunction(v.FunctionID, v.Argc, args)
+	case *UserFunction:
+		args := c.compileAll(v.Argv, names)
+		if !names.declared(v.Name, true) {
+			if f, ok := c.funcs.GetByName(v.Name); ok {
+				return newCompiledCall(v, f, args)
+			}
+		}
+		return NewUserFunction(v.Name, v.Argc, args)
+	case *RefExpr:
+		if !names.declared(v.Name, false) {
+			if value, ok := c.funcs.GetValue(v.Name); ok {
+				return &compiledConstant{name: v.Name, value: value}
+			}
+		}
+		return v
+	case *IfExpr:
+		return &compiledIf{
+			IfExpr: NewIf(c.compile(v.Condition, names), c.compile(v.True, names), c.compile(v.False, names)),
+		}
+	case *GetterExpr:
+		return &compiledGetter{GetterExpr: NewGetterExpr(c.compile(v.Object, names), v.Key)}
+	case *Block:
+		decl, names := c.compileDeclaration(v.Let, names)
+		return &Block{Let: decl.(*LetExpr), Body: c.compile(v.Body, names)}
+	case *BlockV2:
+		decl, names := c.compileDeclaration(v.Decl, names)
+		return NewBlockV2(decl, c.compile(v.Body, names))
+	default:
+		return e
+	}
+}
+
+func (c *compiler) compileAll(e Exprs, names *binding) Exprs {
+	out := make(Exprs, len(e))
+	for i, row := range e {
+		out[i] = c.compile(row, names)
+	}
+	return out
+}
+
+// compileDeclaration returns compiled declaration and names visible after it.
+func (c *compiler) compileDeclaration(d Declaration, names *binding) (Declaration, *binding) {
+	switch v := d.(type) {
+	case *LetExpr:
+		// Let is visible in its own value, it is evaluated in the scope of declaration.
+		names = &binding{name: v.Name, parent: names}
+		return NewLet(v.Name, c.compile(v.Value, names)), names
+	case *FuncDeclaration:
+		// Function is added to the scope of declaration, which is the parent scope of its body.
+		names = &binding{name: v.Name, function: true, parent: names}
+		bodyNames := names
+		for _, arg := range v.Args {
+			bodyNames = &binding{name: arg, parent: bodyNames}
+		}
+		return NewFuncDeclaration(v.Name, v.Args, c.compile(v.Body, bodyNames)), names
+	default:
+		return d, names
+	}
+}
+
+// declares tells if evaluation of the expression adds declarations to the scope,
+// so it has to be evaluated in a scope of its own.
+func declares(e Expr) bool {
+	switch e.(type) {
+	case *Block, *BlockV2:
+		return true
+	default:
+		return false
+	}
+}
+
+// scopeFor returns scope to evaluate expression in, it is cloned only if expression declares something.
+func scopeFor(s Scope, e Expr) Scope {
+	if declares(e) {
+		return s.Clone()
+	}
+	return s
+}
+
+// compiledCall is a call of standard library function resolved by compiler.
+type compiledCall struct {
+	// call is the original function call, it is used to write the expression.
+	call Expr
+	f    Callable
+	args Exprs
+	// clone is set if some of arguments declares something, functions of standard library
+	// add nothing to the scope by themselves.
+	clone bool
+}
+
+func newCompiledCall(call Expr, f Callable, args Exprs) *compiledCall {
+	c := &compiledCall{call: call, f: f, args: args}
+	for _, arg := range args {
+		if declares(arg) {
+			c.clone = true
+		}
+	}
+	return c
+}
+
+func (a *compiledCall) Write(w io.Writer) {
+	a.call.Write(w)
+}
+
+func (a *compiledCall) Evaluate(s Scope) (Expr, error) {
+	if a.clone {
+		s = s.Clone()
+	}
+	return a.f(s, a.args)
+}
+
+func (a *compiledCall) Eq(other Expr) (bool, error) {
+	return false, errors.Errorf("trying to compare %T with %T", a, other)
+}
+
+func (a *compiledCall) InstanceOf() string {
+	return a.call.InstanceOf()
+}
+
+// compiledConstant is a reference to constant of standard library.
+// Value is evaluated on each reference like by RefExpr, since some constants, like lastBlock, are expressions.
+type compiledConstant struct {
+	name  string
+	value Expr
+}
+
+func (a *compiledConstant) Write(w io.Writer) {
+	_, _ = io.WriteString(w, a.name)
+}
+
+func (a *compiledConstant) Evaluate(s Scope) (Expr, error) {
+	return a.value.Evaluate(s.Clone())
+}
+
+func (a *compiledConstant) Eq(other Expr) (bool, error) {
+	return false, errors.Errorf("trying to compare %T with %T", a, other)
+}
+
+func (a *compiledConstant) InstanceOf() string {
+	return "Ref"
+}
+
+// compiledIf doesn't clone scope for condition and branches, which declare nothing.
+type compiledIf struct {
+	*IfExpr
+}
+
+func (a *compiledIf) Evaluate(s Scope) (Expr, error) {
+	cond, err := a.Condition.Evaluate(scopeFor(s, a.Condition))
+	if err != nil {
+		return nil, err
+	}
+	b, ok := cond.(*BooleanExpr)
+	if !ok {
+		return nil, errors.Errorf("IfExpr evaluate: expected bool in condition found %T", cond)
+	}
+	if b.Value {
+		return a.True.Evaluate(scopeFor(s, a.True))
+	}
+	return a.False.Evaluate(scopeFor(s, a.False))
+}
+
+// compiledGetter doesn't clone scope for object, which declares nothing.
+type compiledGetter struct {
+	*GetterExpr
+}
+
+func (a *compiledGetter) Evaluate(s Scope) (Expr, error) {
+	val, err := a.Object.Evaluate(scopeFor(s, a.Object))
+	if err != nil {
+		return nil, errors.Wrapf(err, "GetterExpr Evaluate by key %s", a.Key)
+	}
+	obj, ok := val.(*ObjectExpr)
+	if !ok {
+		return nil, errors.Errorf("GetterExpr Evaluate: expected value be *ObjectExpr, got %T", val)
+	}
+	return obj.Get(a.Key)
+}
@@ -0,0 +1,99 @@
+package ast
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/mockstate"
+)
+
+func TestCompile(t *testing.T) {
+	funcs, err := NewFuncScope(3)
+	require.NoError(t, err)
+	isDefined := func(arg Expr) Expr {
+		return NewFuncCall(NewUserFunction("isDefined", 1, Params(arg)))
+	}
+
+	for _, test := range []struct {
+		name   string
+		expr   Expr
+		result Expr
+	}{
+		{"predefined", isDefined(&RefExpr{Name: "unit"}), NewBoolean(false)},
+		{
+			"declared function shadows predefined",
+			NewBlockV2(NewFuncDeclaration("isDefined", []string{"x"}, NewBoolean(true)), isDefined(&RefExpr{Name: "unit"})),
+			NewBoolean(true),
+		},
+		{
+			"declared value shadows constant",
+			NewBlockV2(NewLet("unit", NewLong(5)), isDefined(&RefExpr{Name: "unit"})),
+			NewBoolean(true),
+		},
+		{
+			"function argument shadows constant",
+			NewBlockV2(
+				NewFuncDeclaration("f", []string{"unit"}, isDefined(&RefExpr{Name: "unit"})),
+				NewFuncCall(NewUserFunction("f", 1, Params(NewLong(1)))),
+			),
+			NewBoolean(true),
+		},
+		{
+			"declaration in argument",
+			NewFuncCall(NewNativeFunction(0, 2, Params(
+				NewBlockV2(NewLet("x", NewLong(1)), &RefExpr{Name: "x"}),
+				&RefExpr{Name: "x"},
+			))),
+			NewBoolean(false),
+		},
+	} {
+		s := NewScope(proto.MainNetScheme, nil, funcs, map[string]Expr{"x": NewLong(2)})
+		rs, err := test.expr.Evaluate(s.Clone())
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.result, rs, test.name)
+
+		compiled := Compile(test.expr, funcs)
+		rs, err = compiled.Evaluate(s.Clone())
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.result, rs, test.name)
+
+		expected, actual := new(bytes.Buffer), new(bytes.Buffer)
+		test.expr.Write(expected)
+		compiled.Write(actual)
+		assert.Equal(t, expected.String(), actual.String(), test.name)
+	}
+}
+
+func TestCompile_Resolved(t *testing.T) {
+	funcs, err := NewFuncScope(3)
+	require.NoError(t, err)
+
+	e := Compile(NewFuncCall(NewUserFunction("isDefined", 1, Params(&RefExpr{Name: "unit"}))), funcs)
+	call, ok := e.(*compiledCall)
+	require.True(t, ok)
+	assert.IsType(t, &compiledConstant{}, call.args[0])
+
+	// unknown function is left to fail on evaluation
+	e = Compile(NewFuncCall(NewNativeFunction(10000, 0, nil)), funcs)
+	assert.IsType(t, &NativeFunction{}, e)
+	_, err = e.Evaluate(NewScope(proto.MainNetScheme, nil, funcs, nil))
+	assert.Error(t, err)
+}
+
+func TestCompile_ExpressionConstant(t *testing.T) {
+	funcs, err := NewFuncScope(3)
+	require.NoError(t, err)
+	state := mockstate.MockStateImpl{
+		Blocks: map[uint64]*mockstate.BlockInfo{5: {Timestamp: 1563000000000, Height: 5}},
+	}
+	s := NewScope(proto.MainNetScheme, state, funcs, map[string]Expr{"height": NewLong(5)})
+
+	// lastBlock is evaluated on reference, not returned as is.
+	e := Compile(NewGetterExpr(&RefExpr{Name: "lastBlock"}, "timestamp"), funcs)
+	rs, err := e.Evaluate(s.Clone())
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(1563000000000), rs)
+}
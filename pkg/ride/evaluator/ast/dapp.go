@@ -0,0 +1,391 @@
+package ast
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// ArgType is a type of callable function argument from script metadata.
+// Union types are combinations of the flags.
+type ArgType byte
+
+const (
+	ArgInt ArgType = 1 << iota
+	ArgBytes
+	ArgBoolean
+	ArgString
+)
+
+func (t ArgType) String() string {
+	var names []string
+	for _, v := range []struct {
+		flag ArgType
+		name string
+	}{
+		{ArgInt, "Int"},
+		{ArgBytes, "ByteVector"},
+		{ArgBoolean, "Boolean"},
+		{ArgString, "String"},
+	} {
+		if t&v.flag != 0 {
+			names = append(names, v.name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("Unknown(%d)", byte(t))
+	}
+	return strings.Join(names, "|")
+}
+
+// Accepts tells if the value is of this type.
+func (t ArgType) Accepts(e Expr) bool {
+	switch e.(type) {
+	case *LongExpr:
+		return t&ArgInt != 0
+	case *BytesExpr:
+		return t&ArgBytes != 0
+	case *BooleanExpr:
+		return t&ArgBoolean != 0
+	case *StringExpr:
+		return t&ArgString != 0
+	default:
+		return false
+	}
+}
+
+// AnnotatedFunction is a function of DApp with annotation, e.g. `@Callable(i)` or `@Verifier(tx)`.
+type AnnotatedFunction struct {
+	// Name of annotation argument, the invocation or transaction is bound to it.
+	ArgumentName string
+	Func         *FuncDeclaration
+	// Types of function arguments, empty if script has no metadata.
+	ArgTypes []ArgType
+}
+
+// ContractScript is a DApp script with global declarations, callable functions and optional verifier.
+type ContractScript struct {
+	// Version of standard library.
+	Version      int
+	Declarations []Declaration
+	Callables    map[string]*AnnotatedFunction
+	// Verifier is nil if DApp doesn't verify its transactions.
+	Verifier *AnnotatedFunction
+}
+
+// CallableNames returns sorted names of callable functions.
+func (a *ContractScript) CallableNames() []string {
+	names := make([]string, 0, len(a.Callables))
+	for name := range a.Callables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasVerifier tells if DApp has `@Verifier` function.
+func (a *ContractScript) HasVerifier() bool {
+	return a.Verifier != nil
+}
+
+// Declare adds global declarations of DApp to the scope.
+func (a *ContractScript) Declare(s Scope) {
+	for _, decl := range a.Declarations {
+		decl.Declare(s)
+	}
+}
+
+// Invoke evaluates callable function with given invocation and arguments in the scope.
+// Global declarations should be added to the scope before.
+func (a *ContractScript) Invoke(s Scope, name string, invocation Expr, args Exprs) (Expr, error) {
+	callable, ok := a.Callables[name]
+	if !ok {
+		return nil, errors.Errorf("callable function %s not found", name)
+	}
+	if len(callable.ArgTypes) != 0 {
+		if len(args) != len(callable.ArgTypes) {
+			return nil, errors.Errorf("%s: invalid params, expected %d, passed %d", name, len(callable.ArgTypes), len(args))
+		}
+		for i, t := range callable.ArgTypes {
+			if !t.Accepts(args[i]) {
+				return nil, errors.Errorf("%s: argument %d expected to be %s, found %T", name, i+1, t, args[i])
+			}
+		}
+	}
+	funcScope := s.Clone()
+	funcScope.AddValue(callable.ArgumentName, invocation)
+	return callable.Func.Callable(funcScope)(s, args)
+}
+
+// Verify evaluates verifier function with given transaction in the scope.
+// Global declarations should be added to the scope before.
+func (a *ContractScript) Verify(s Scope, tx Expr) (bool, error) {
+	if a.Verifier == nil {
+		return false, errors.New("DApp has no verifier function")
+	}
+	funcScope := s.Clone()
+	funcScope.AddValue(a.Verifier.ArgumentName, tx)
+	rs, err := a.Verifier.Func.Callable(funcScope)(s, nil)
+	if err != nil {
+		if _, ok := err.(Throw); ok {
+			return false, nil
+		}
+		return false, err
+	}
+	b, ok := rs.(*BooleanExpr)
+	if !ok {
+		return false, errors.Errorf("verifier: expected result to be *BooleanExpr, found %T", rs)
+	}
+	return b.Value, nil
+}
+
+// ScriptTransfer is a transfer from DApp account made by callable function.
+type ScriptTransfer struct {
+	Recipient proto.Recipient
+	Amount    int64
+	Asset     proto.OptionalAsset
+}
+
+// ScriptResult is a result of callable function: data entries to write and transfers.
+type ScriptResult struct {
+	Writes    []proto.DataEntry
+	Transfers []ScriptTransfer
+}
+
+// NewScriptResult converts value returned by callable function,
+// which is WriteSet, TransferSet or ScriptResult, to ScriptResult.
+func NewScriptResult(e Expr) (*ScriptResult, error) {
+	funcName := "NewScriptResult"
+
+	obj, ok := e.(*ObjectExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected *ObjectExpr, found %T", funcName, e)
+	}
+	out := &ScriptResult{}
+	switch obj.InstanceOf() {
+	case "WriteSet":
+		writes, err := writesFromWriteSet(obj)
+		if err != nil {
+			return nil, errors.Wrap(err, funcName)
+		}
+		out.Writes = writes
+	case "TransferSet":
+		transfers, err := transfersFromTransferSet(obj)
+		if err != nil {
+			return nil, errors.Wrap(err, funcName)
+		}
+		out.Transfers = transfers
+	case "ScriptResult":
+		ws, err := obj.Get("writeSet")
+		if err != nil {
+			return nil, errors.Wrap(err, funcName)
+		}
+		ts, err := obj.Get("transferSet")
+		if err != nil {
+			return nil, errors.Wrap(err, funcName)
+		}
+		wsObj, ok := ws.(*ObjectExpr)
+		if !ok {
+			return nil, errors.Errorf("%s: expected WriteSet to be *ObjectExpr, found %T", funcName, ws)
+		}
+		tsObj, ok := ts.(*ObjectExpr)
+		if !ok {
+			return nil, errors.Errorf("%s: expected TransferSet to be *ObjectExpr, found %T", funcName, ts)
+		}
+		out.Writes, err = writesFromWriteSet(wsObj)
+		if err != nil {
+			return nil, errors.Wrap(err, funcName)
+		}
+		out.Transfers, err = transfersFromTransferSet(tsObj)
+		if err != nil {
+			return nil, errors.Wrap(err, funcName)
+		}
+	default:
+		return nil, errors.Errorf("%s: unexpected result type %s", funcName, obj.InstanceOf())
+	}
+	return out, nil
+}
+
+func listField(obj *ObjectExpr, name string) (Exprs, error) {
+	e, err := obj.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	lst, ok := e.(Exprs)
+	if !ok {
+		return nil, errors.Errorf("expected field %s to be Exprs, found %T", name, e)
+	}
+	return lst, nil
+}
+
+func writesFromWriteSet(obj *ObjectExpr) ([]proto.DataEntry, error) {
+	lst, err := listField(obj, "data")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]proto.DataEntry, len(lst))
+	for i, row := range lst {
+		entry, ok := row.(*ObjectExpr)
+		if !ok {
+			return nil, errors.Errorf("expected DataEntry to be *ObjectExpr, found %T", row)
+		}
+		k, err := entry.Get("key")
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(*StringExpr)
+		if !ok {
+			return nil, errors.Errorf("expected key of DataEntry to be *StringExpr, found %T", k)
+		}
+		v, err := entry.Get("value")
+		if err != nil {
+			return nil, err
+		}
+		switch value := v.(type) {
+		case *LongExpr:
+			out[i] = proto.IntegerDataEntry{Key: key.Value, Value: value.Value}
+		case *BooleanExpr:
+			out[i] = proto.BooleanDataEntry{Key: key.Value, Value: value.Value}
+		case *BytesExpr:
+			out[i] = proto.BinaryDataEntry{Key: key.Value, Value: value.Value}
+		case *StringExpr:
+			out[i] = proto.StringDataEntry{Key: key.Value, Value: value.Value}
+		default:
+			return nil, errors.Errorf("unexpected type of DataEntry value %T", v)
+		}
+	}
+	return out, nil
+}
+
+func transfersFromTransferSet(obj *ObjectExpr) ([]ScriptTransfer, error) {
+	lst, err := listField(obj, "transfers")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ScriptTransfer, len(lst))
+	for i, row := range lst {
+		transfer, ok := row.(*ObjectExpr)
+		if !ok {
+			return nil, errors.Errorf("expected ScriptTransfer to be *ObjectExpr, found %T", row)
+		}
+		r, err := transfer.Get("recipient")
+		if err != nil {
+			return nil, err
+		}
+		switch recipient := r.(type) {
+		case AddressExpr:
+			out[i].Recipient = proto.NewRecipientFromAddress(proto.Address(recipient))
+		case AliasExpr:
+			out[i].Recipient = proto.NewRecipientFromAlias(proto.Alias(recipient))
+		default:
+			return nil, errors.Errorf("unexpected type of ScriptTransfer recipient %T", r)
+		}
+		a, err := transfer.Get("amount")
+		if err != nil {
+			return nil, err
+		}
+		amount, ok := a.(*LongExpr)
+		if !ok {
+			return nil, errors.Errorf("expected amount of ScriptTransfer to be *LongExpr, found %T", a)
+		}
+		out[i].Amount = amount.Value
+		as, err := transfer.Get("asset")
+		if err != nil {
+			return nil, err
+		}
+		switch asset := as.(type) {
+		case Unit:
+		case *BytesExpr:
+			id, err := crypto.NewDigestFromBytes(asset.Value)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid asset of ScriptTransfer")
+			}
+			out[i].Asset = proto.OptionalAsset{Present: true, ID: id}
+		default:
+			return nil, errors.Errorf("unexpected type of ScriptTransfer asset %T", as)
+		}
+	}
+	return out, nil
+}
+
+// NewInvocation creates invocation object from InvokeScript transaction.
+func NewInvocation(scheme byte, tx *proto.InvokeScriptV1) (*ObjectExpr, error) {
+	funcName := "NewInvocation"
+
+	fields := make(map[string]Expr)
+	addr, err := proto.NewAddressFromPublicKey(scheme, tx.SenderPK)
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	fields["caller"] = NewAddressFromProtoAddress(addr)
+	fields["callerPublicKey"] = NewBytes(tx.SenderPK.Bytes())
+	payment, err := attachedPayment(tx.Payments)
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	fields["payment"] = payment
+	id, err := tx.GetID()
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	fields["transactionId"] = NewBytes(id)
+	fields["fee"] = NewLong(int64(tx.Fee))
+	fields["feeAssetId"] = optionalAsset(tx.FeeAsset)
+	fields[InstanceFieldName] = NewString("Invocation")
+	return NewObject(fields), nil
+}
+
+// NewArguments converts arguments of function call to expressions.
+func NewArguments(args proto.Arguments) (Exprs, error) {
+	out := make(Exprs, len(args))
+	for i, arg := range args {
+		switch a := arg.(type) {
+		case *proto.IntegerArgument:
+			out[i] = NewLong(a.Value)
+		case proto.IntegerArgument:
+			out[i] = NewLong(a.Value)
+		case *proto.BooleanArgument:
+			out[i] = NewBoolean(a.Value)
+		case proto.BooleanArgument:
+			out[i] = NewBoolean(a.Value)
+		case *proto.BinaryArgument:
+			out[i] = NewBytes(a.Value)
+		case proto.BinaryArgument:
+			out[i] = NewBytes(a.Value)
+		case *proto.StringArgument:
+			out[i] = NewString(a.Value)
+		case proto.StringArgument:
+			out[i] = NewString(a.Value)
+		default:
+			return nil, errors.Errorf("NewArguments: unsupported argument type %T", arg)
+		}
+	}
+	return out, nil
+}
+
+// attachedPayment returns optional payment of InvokeScript transaction.
+func attachedPayment(payments proto.ScriptPayments) (Expr, error) {
+	switch len(payments) {
+	case 0:
+		return NewUnit(), nil
+	case 1:
+		return NewObject(map[string]Expr{
+			"assetId":         optionalAsset(payments[0].Asset),
+			"amount":          NewLong(int64(payments[0].Amount)),
+			InstanceFieldName: NewString("AttachedPayment"),
+		}), nil
+	default:
+		return nil, errors.Errorf("only one payment is allowed, found %d", len(payments))
+	}
+}
+
+func optionalAsset(a proto.OptionalAsset) Expr {
+	if a.Present {
+		return NewBytes(a.ID.Bytes())
+	}
+	return NewUnit()
+}
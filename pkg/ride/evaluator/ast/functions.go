@@ -1,17 +1,30 @@
 package ast
 
 import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rsa"
+	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"github.com/mr-tron/base58/base58"
 	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/ride/mockstate"
+	"golang.org/x/crypto/sha3"
+	"hash"
 	"io"
+	"math"
 	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf16"
 	"unicode/utf8"
 )
 
@@ -19,6 +32,69 @@ const MaxBytesResult = 65536
 const MaxStringResult = 32767
 const DefaultThrowMessage = "Explicit script termination"
 
+// Maximal precision of arguments and result of pow() and log() functions
+const maxDecimalPrecision = 8
+
+// Rounding modes are objects of these types
+const (
+	roundingDown     = "Down"
+	roundingUp       = "Up"
+	roundingHalfUp   = "HalfUp"
+	roundingHalfDown = "HalfDown"
+	roundingHalfEven = "HalfEven"
+	roundingCeiling  = "Ceiling"
+	roundingFloor    = "Floor"
+)
+
+// Digest algorithms of rsaVerify() function are objects of these types
+const (
+	digestNoAlg   = "NoAlg"
+	digestMD5     = "Md5"
+	digestSHA1    = "Sha1"
+	digestSHA224  = "Sha224"
+	digestSHA256  = "Sha256"
+	digestSHA384  = "Sha384"
+	digestSHA512  = "Sha512"
+	digestSHA3224 = "Sha3224"
+	digestSHA3256 = "Sha3256"
+	digestSHA3384 = "Sha3384"
+	digestSHA3512 = "Sha3512"
+)
+
+// rsaDigest is a hash function of RSA signature and DER encoded prefix of its DigestInfo.
+type rsaDigest struct {
+	hash   func() hash.Hash
+	prefix []byte
+}
+
+// Message is signed as is if no digest algorithm is used
+var rsaDigests = map[string]rsaDigest{
+	digestNoAlg:   {},
+	digestMD5:     {md5.New, []byte{0x30, 0x20, 0x30, 0x0c, 0x06, 0x08, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d, 0x02, 0x05, 0x05, 0x00, 0x04, 0x10}},
+	digestSHA1:    {sha1.New, []byte{0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14}},
+	digestSHA224:  {sha256.New224, []byte{0x30, 0x2d, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x04, 0x05, 0x00, 0x04, 0x1c}},
+	digestSHA256:  {sha256.New, []byte{0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20}},
+	digestSHA384:  {sha512.New384, []byte{0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30}},
+	digestSHA512:  {sha512.New, []byte{0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40}},
+	digestSHA3224: {sha3.New224, []byte{0x30, 0x2d, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x07, 0x05, 0x00, 0x04, 0x1c}},
+	digestSHA3256: {sha3.New256, []byte{0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x08, 0x05, 0x00, 0x04, 0x20}},
+	digestSHA3384: {sha3.New384, []byte{0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x09, 0x05, 0x00, 0x04, 0x30}},
+	digestSHA3512: {sha3.New512, []byte{0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x0a, 0x05, 0x00, 0x04, 0x40}},
+}
+
+// Limits of base16 encoding and decoding inputs
+const (
+	maxBase16Bytes  = 8 * 1024
+	maxBase16String = 32 * 1024
+)
+
+// Merkle tree hashes are prefixed to distinguish leafs from internal nodes
+const (
+	merkleLeafPrefix     = 0
+	merkleInternalPrefix = 1
+	merkleLeftSide       = 0
+)
+
 type Throw struct {
 	Message string
 }
@@ -109,6 +185,34 @@ func NativeGetList(s Scope, e Exprs) (Expr, error) {
 	return lst[lng.Value], nil
 }
 
+// Prepend element to list
+func NativeCreateList(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeCreateList"
+
+	if l := len(e); l != 2 {
+		return nil, errors.Errorf("%s: invalid params, expected 2, passed %d", funcName, l)
+	}
+
+	head, err := e[0].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	t, err := e[1].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	tail, ok := t.(Exprs)
+	if !ok {
+		return nil, errors.Errorf("%s: expected second argument Exprs, got %T", funcName, t)
+	}
+
+	out := make(Exprs, 0, len(tail)+1)
+	out = append(out, head)
+	return append(out, tail...), nil
+}
+
 // Internal function to check value type
 func NativeIsInstanceOf(s Scope, e Exprs) (Expr, error) {
 	funcName := "NativeIsInstanceOf"
@@ -243,6 +347,133 @@ func mathLong(funcName string, f func(int64, int64) (Expr, error), s Scope, e Ex
 	return f(first.Value, second.Value)
 }
 
+// Power of decimal numbers
+// pow(base, basePrecision, exponent, exponentPrecision, resultPrecision, rounding)
+func NativePowLong(s Scope, e Exprs) (Expr, error) {
+	return decimalMath("NativePowLong", math.Pow, s, e)
+}
+
+// Logarithm of decimal number
+// log(value, valuePrecision, base, basePrecision, resultPrecision, rounding)
+func NativeLogLong(s Scope, e Exprs) (Expr, error) {
+	return decimalMath("NativeLogLong", func(x, y float64) float64 {
+		return math.Log(x) / math.Log(y)
+	}, s, e)
+}
+
+// decimalMath applies f to decimal arguments like Scala implementation does:
+// arguments are converted to doubles and the result is rounded to given precision.
+func decimalMath(funcName string, f func(float64, float64) float64, s Scope, e Exprs) (Expr, error) {
+	if l := len(e); l != 6 {
+		return nil, errors.Errorf("%s: invalid params, expected 6, passed %d", funcName, l)
+	}
+
+	rs, err := e.EvaluateAll(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	longs := make([]int64, 5)
+	for i := range longs {
+		v, ok := rs[i].(*LongExpr)
+		if !ok {
+			return nil, errors.Errorf("%s: expected argument %d to be *LongExpr, got %T", funcName, i+1, rs[i])
+		}
+		longs[i] = v.Value
+	}
+	for _, p := range []int64{longs[1], longs[3], longs[4]} {
+		if p < 0 || p > maxDecimalPrecision {
+			return nil, errors.Errorf("%s: precision %d out of range 0-%d", funcName, p, maxDecimalPrecision)
+		}
+	}
+
+	mode, ok := rs[5].(*ObjectExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected last argument to be rounding mode, got %T", funcName, rs[5])
+	}
+
+	x := float64(longs[0]) / math.Pow10(int(longs[1]))
+	y := float64(longs[2]) / math.Pow10(int(longs[3]))
+	unscaled, scale, err := decimalFromFloat(f(x, y))
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	out, err := setDecimalScale(unscaled, scale, int(longs[4]), mode.InstanceOf())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	if !out.IsInt64() {
+		return nil, errors.Errorf("%s: long overflow %s", funcName, out.String())
+	}
+
+	return NewLong(out.Int64()), nil
+}
+
+// decimalFromFloat converts float to decimal using its shortest string representation, like Java's BigDecimal.valueOf().
+// Value of the decimal is unscaled * 10^(-scale).
+func decimalFromFloat(f float64) (*big.Int, int, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, 0, errors.Errorf("result %v is not a number", f)
+	}
+	str := strconv.FormatFloat(f, 'e', -1, 64)
+	i := strings.IndexByte(str, 'e')
+	exp, err := strconv.Atoi(str[i+1:])
+	if err != nil {
+		return nil, 0, err
+	}
+	mantissa := str[:i]
+	fracLen := 0
+	if dot := strings.IndexByte(mantissa, '.'); dot >= 0 {
+		fracLen = len(mantissa) - dot - 1
+		mantissa = mantissa[:dot] + mantissa[dot+1:]
+	}
+	unscaled, ok := new(big.Int).SetString(mantissa, 10)
+	if !ok {
+		return nil, 0, errors.Errorf("invalid decimal %s", str)
+	}
+	return unscaled, fracLen - exp, nil
+}
+
+// setDecimalScale returns unscaled value of decimal unscaled * 10^(-scale) with new scale, rounded with given mode.
+func setDecimalScale(unscaled *big.Int, scale, newScale int, mode string) (*big.Int, error) {
+	ten := big.NewInt(10)
+	if newScale >= scale {
+		m := new(big.Int).Exp(ten, big.NewInt(int64(newScale-scale)), nil)
+		return m.Mul(m, unscaled), nil
+	}
+	d := new(big.Int).Exp(ten, big.NewInt(int64(scale-newScale)), nil)
+	q, r := new(big.Int).QuoRem(unscaled, d, new(big.Int))
+	if r.Sign() == 0 {
+		return q, nil
+	}
+	sign := big.NewInt(int64(unscaled.Sign()))
+	half := new(big.Int).Abs(r)
+	half.Mul(half, big.NewInt(2))
+	halfCmp := half.Cmp(d)
+	awayFromZero := false
+	switch mode {
+	case roundingDown:
+	case roundingUp:
+		awayFromZero = true
+	case roundingCeiling:
+		awayFromZero = sign.Sign() > 0
+	case roundingFloor:
+		awayFromZero = sign.Sign() < 0
+	case roundingHalfUp:
+		awayFromZero = halfCmp >= 0
+	case roundingHalfDown:
+		awayFromZero = halfCmp > 0
+	case roundingHalfEven:
+		awayFromZero = halfCmp > 0 || (halfCmp == 0 && q.Bit(0) == 1)
+	default:
+		return nil, errors.Errorf("unknown rounding mode '%s'", mode)
+	}
+	if awayFromZero {
+		q.Add(q, sign)
+	}
+	return q, nil
+}
+
 // Check signature
 // accepts Value, signature and public key
 func NativeSigVerify(s Scope, e Exprs) (Expr, error) {
@@ -352,6 +583,86 @@ func NativeSha256(s Scope, e Exprs) (Expr, error) {
 	return NewBytes(d), nil
 }
 
+// RSA signature verification with digest algorithm
+func NativeRSAVerify(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeRSAVerify"
+
+	if l := len(e); l != 4 {
+		return nil, errors.Errorf("%s: invalid params, expected 4, passed %d", funcName, l)
+	}
+
+	rs, err := e.EvaluateAll(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	digest, ok := rsaDigests[rs[0].InstanceOf()]
+	if !ok {
+		return nil, errors.Errorf("%s: first argument expected to be digest algorithm, found %s", funcName, rs[0].InstanceOf())
+	}
+
+	message, ok := rs[1].(*BytesExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: second argument expected to be *BytesExpr, found %T", funcName, rs[1])
+	}
+
+	signature, ok := rs[2].(*BytesExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: third argument expected to be *BytesExpr, found %T", funcName, rs[2])
+	}
+
+	pkExpr, ok := rs[3].(*BytesExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: fourth argument expected to be *BytesExpr, found %T", funcName, rs[3])
+	}
+
+	pk, err := x509.ParsePKIXPublicKey(pkExpr.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	key, ok := pk.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("%s: expected RSA public key, found %T", funcName, pk)
+	}
+
+	hashed := message.Value
+	if digest.hash != nil {
+		h := digest.hash()
+		h.Write(message.Value)
+		hashed = h.Sum(nil)
+	}
+
+	return NewBoolean(verifyPKCS1v15(key, digest.prefix, hashed, signature.Value)), nil
+}
+
+// verifyPKCS1v15 checks RSASSA-PKCS1-v1_5 signature of hashed message.
+// Signature must be the encrypted DigestInfo: 0x00 || 0x01 || 0xff... || 0x00 || prefix || hashed.
+func verifyPKCS1v15(key *rsa.PublicKey, prefix, hashed, sig []byte) bool {
+	k := (key.N.BitLen() + 7) / 8
+	tLen := len(prefix) + len(hashed)
+	if len(sig) != k || k < tLen+11 {
+		return false
+	}
+
+	c := new(big.Int).SetBytes(sig)
+	if c.Cmp(key.N) >= 0 {
+		return false
+	}
+	m := new(big.Int).Exp(c, big.NewInt(int64(key.E)), key.N).Bytes()
+	em := make([]byte, k)
+	copy(em[k-len(m):], m)
+
+	if em[0] != 0 || em[1] != 1 || em[k-tLen-1] != 0 {
+		return false
+	}
+	for _, b := range em[2 : k-tLen-1] {
+		if b != 0xff {
+			return false
+		}
+	}
+	return bytes.Equal(em[k-tLen:k-len(hashed)], prefix) && bytes.Equal(em[k-len(hashed):], hashed)
+}
+
 // Рeight when transaction was stored to blockchain
 func NativeTransactionHeightByID(s Scope, e Exprs) (Expr, error) {
 	funcName := "NativeTransactionHeightByID"
@@ -415,6 +726,113 @@ func NativeTransactionByID(s Scope, e Exprs) (Expr, error) {
 	return NewObject(vars), nil
 }
 
+// Asset info by its ID, returns Unit if asset is unknown
+func NativeAssetInfo(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeAssetInfo"
+
+	if l := len(e); l != 1 {
+		return nil, errors.Errorf("%s: invalid params, expected 1, passed %d", funcName, l)
+	}
+
+	rs, err := e[0].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	bts, ok := rs.(*BytesExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *BytesExpr, got %T", funcName, rs)
+	}
+
+	id, err := crypto.NewDigestFromBytes(bts.Value)
+	if err != nil {
+		return NewUnit(), nil
+	}
+
+	info, err := s.State().AssetInfo(id)
+	if err != nil {
+		if err == mockstate.ErrNotFound {
+			return NewUnit(), nil
+		}
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	return NewAssetInfo(info), nil
+}
+
+// Block info by height, returns Unit if there is no block at the height
+func NativeBlockInfoByHeight(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeBlockInfoByHeight"
+
+	if l := len(e); l != 1 {
+		return nil, errors.Errorf("%s: invalid params, expected 1, passed %d", funcName, l)
+	}
+
+	rs, err := e[0].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	height, ok := rs.(*LongExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *LongExpr, got %T", funcName, rs)
+	}
+
+	if height.Value <= 0 {
+		return NewUnit(), nil
+	}
+
+	info, err := s.State().BlockInfoByHeight(uint64(height.Value))
+	if err != nil {
+		if err == mockstate.ErrNotFound {
+			return NewUnit(), nil
+		}
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	return NewBlockInfo(info), nil
+}
+
+// Transfer transaction by ID, returns Unit if transaction is unknown or is not a transfer
+func NativeTransferTransactionByID(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeTransferTransactionByID"
+
+	if l := len(e); l != 1 {
+		return nil, errors.Errorf("%s: invalid params, expected 1, passed %d", funcName, l)
+	}
+
+	rs, err := e[0].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	bts, ok := rs.(*BytesExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *BytesExpr, got %T", funcName, rs)
+	}
+
+	tx, err := s.State().TransactionByID(bts.Value)
+	if err != nil {
+		if err == mockstate.ErrNotFound {
+			return NewUnit(), nil
+		}
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	switch tx.(type) {
+	case *proto.TransferV1, *proto.TransferV2:
+	default:
+		return NewUnit(), nil
+	}
+
+	vars, err := NewVariablesFromTransaction(s.Scheme(), tx)
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	return NewObject(vars), nil
+}
+
 // Size of bytes vector
 func NativeSizeBytes(s Scope, e Exprs) (Expr, error) {
 	funcName := "NativeSizeBytes"
@@ -815,8 +1233,13 @@ func NativeAssetBalance(s Scope, e Exprs) (Expr, error) {
 		return nil, errors.Errorf("%s first argument expected to be AddressExpr or AliasExpr, found %T", funcName, addressOrAliasExpr)
 	}
 
+	account, err := s.State().Account(r)
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
 	if _, ok := assetId.(Unit); ok {
-		return NewLong(int64(s.State().Account(r).AssetBalance(&proto.OptionalAsset{}))), nil
+		return NewLong(int64(account.AssetBalance(&proto.OptionalAsset{}))), nil
 	}
 
 	assetBts, ok := assetId.(*BytesExpr)
@@ -829,7 +1252,7 @@ func NativeAssetBalance(s Scope, e Exprs) (Expr, error) {
 		return nil, errors.Wrap(err, funcName)
 	}
 
-	return NewLong(int64(s.State().Account(r).AssetBalance(asset))), nil
+	return NewLong(int64(account.AssetBalance(asset))), nil
 }
 
 // Fail script
@@ -996,53 +1419,394 @@ func NativeToBse64String(s Scope, e Exprs) (Expr, error) {
 	return NewString(encoded), nil
 }
 
-// Get integer from data of DataTransaction
-func NativeDataLongFromArray(s Scope, e Exprs) (Expr, error) {
-	return dataFromArray("NativeDataLongFromArray", s, e, proto.DataInteger)
-}
+// Base16 encode
+func NativeToBase16String(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeToBase16String"
 
-// Get boolean from data of DataTransaction
-func NativeDataBooleanFromArray(s Scope, e Exprs) (Expr, error) {
-	return dataFromArray("NativeDataBooleanFromArray", s, e, proto.DataBoolean)
-}
+	if l := len(e); l != 1 {
+		return nil, errors.Errorf("%s: invalid params, expected 1, passed %d", funcName, l)
+	}
 
-// Get string from data of DataTransaction
-func NativeDataStringFromArray(s Scope, e Exprs) (Expr, error) {
-	return dataFromArray("NativeDataBooleanFromArray", s, e, proto.DataString)
-}
+	first, err := e[0].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
 
-// Get bytes from data of DataTransaction
-func NativeDataBinaryFromArray(s Scope, e Exprs) (Expr, error) {
-	return dataFromArray("NativeDataBooleanFromArray", s, e, proto.DataBinary)
+	b, ok := first.(*BytesExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *BytesExpr, found %T", funcName, first)
+	}
+
+	if l := len(b.Value); l > maxBase16Bytes {
+		return nil, errors.Errorf("%s: input length %d exceeds %d", funcName, l, maxBase16Bytes)
+	}
+
+	return NewString(hex.EncodeToString(b.Value)), nil
 }
 
-func dataFromArray(funcName string, s Scope, e Exprs, valueType proto.DataValueType) (Expr, error) {
-	if l := len(e); l != 2 {
-		return nil, errors.Errorf("%s: invalid params, expected 2, passed %d", funcName, l)
+// Base16 decode
+func NativeFromBase16String(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeFromBase16String"
+
+	if l := len(e); l != 1 {
+		return nil, errors.Errorf("%s: invalid params, expected 1, passed %d", funcName, l)
 	}
 
-	lstExpr, err := e[0].Evaluate(s.Clone())
+	first, err := e[0].Evaluate(s.Clone())
 	if err != nil {
 		return nil, errors.Wrap(err, funcName)
 	}
 
-	keyExpr, err := e[1].Evaluate(s.Clone())
+	str, ok := first.(*StringExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *StringExpr, found %T", funcName, first)
+	}
+
+	if l := len(str.Value); l > maxBase16String {
+		return nil, errors.Errorf("%s: input length %d exceeds %d", funcName, l, maxBase16String)
+	}
+
+	decoded, err := hex.DecodeString(str.Value)
 	if err != nil {
 		return nil, errors.Wrap(err, funcName)
 	}
 
-	lst, ok := lstExpr.(*DataEntryListExpr)
+	return NewBytes(decoded), nil
+}
+
+// Check that value is a leaf of Merkle tree with given root
+func NativeCheckMerkleProof(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeCheckMerkleProof"
+
+	if l := len(e); l != 3 {
+		return nil, errors.Errorf("%s: invalid params, expected 3, passed %d", funcName, l)
+	}
+
+	rs, err := e.EvaluateAll(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	root, ok := rs[0].(*BytesExpr)
 	if !ok {
-		return nil, errors.Errorf("%s expected first argument to be *DataEntryListExpr, found %T", funcName, lstExpr)
+		return nil, errors.Errorf("%s: first argument expected to be *BytesExpr, found %T", funcName, rs[0])
 	}
 
-	key, ok := keyExpr.(*StringExpr)
+	proof, ok := rs[1].(*BytesExpr)
 	if !ok {
-		return nil, errors.Errorf("%s expected second argument to be *StringExpr, found %T", funcName, keyExpr)
+		return nil, errors.Errorf("%s: second argument expected to be *BytesExpr, found %T", funcName, rs[1])
 	}
 
-	return lst.Get(key.Value, valueType), nil
-}
+	value, ok := rs[2].(*BytesExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: third argument expected to be *BytesExpr, found %T", funcName, rs[2])
+	}
+
+	h, err := crypto.FastHash(append([]byte{merkleLeafPrefix}, value.Value...))
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	hash := h.Bytes()
+	// Proof is a sequence of levels: side of the node, size of the sibling hash and the hash itself
+	for p := proof.Value; len(p) > 0; {
+		if len(p) < 2 || len(p) < 2+int(p[1]) {
+			return NewBoolean(false), nil
+		}
+		side, sibling := p[0], p[2:2+int(p[1])]
+		p = p[2+int(p[1]):]
+
+		node := []byte{merkleInternalPrefix}
+		if side == merkleLeftSide {
+			node = append(append(node, hash...), sibling...)
+		} else {
+			node = append(append(node, sibling...), hash...)
+		}
+		h, err := crypto.FastHash(node)
+		if err != nil {
+			return nil, errors.Wrap(err, funcName)
+		}
+		hash = h.Bytes()
+	}
+
+	return NewBoolean(bytes.Equal(hash, root.Value)), nil
+}
+
+// Parse string to integer, returns Unit if string is not a number
+func NativeParseInt(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeParseInt"
+
+	if l := len(e); l != 1 {
+		return nil, errors.Errorf("%s: invalid params, expected 1, passed %d", funcName, l)
+	}
+
+	first, err := e[0].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	str, ok := first.(*StringExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *StringExpr, found %T", funcName, first)
+	}
+
+	i, err := strconv.ParseInt(str.Value, 10, 64)
+	if err != nil {
+		return NewUnit(), nil
+	}
+
+	return NewLong(i), nil
+}
+
+// Decode UTF-8 bytes to string
+func NativeBytesToUTF8String(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeBytesToUTF8String"
+
+	if l := len(e); l != 1 {
+		return nil, errors.Errorf("%s: invalid params, expected 1, passed %d", funcName, l)
+	}
+
+	first, err := e[0].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	b, ok := first.(*BytesExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *BytesExpr, found %T", funcName, first)
+	}
+
+	return NewString(string(b.Value)), nil
+}
+
+// Big endian bytes to integer
+func NativeBytesToLong(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeBytesToLong"
+
+	if l := len(e); l != 1 {
+		return nil, errors.Errorf("%s: invalid params, expected 1, passed %d", funcName, l)
+	}
+
+	first, err := e[0].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	b, ok := first.(*BytesExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *BytesExpr, found %T", funcName, first)
+	}
+
+	return bytesToLong(funcName, b.Value, 0)
+}
+
+// Big endian bytes to integer, starting from offset
+func NativeBytesToLongWithOffset(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeBytesToLongWithOffset"
+
+	if l := len(e); l != 2 {
+		return nil, errors.Errorf("%s: invalid params, expected 2, passed %d", funcName, l)
+	}
+
+	rs, err := e.EvaluateAll(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	b, ok := rs[0].(*BytesExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *BytesExpr, found %T", funcName, rs[0])
+	}
+
+	offset, ok := rs[1].(*LongExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected second argument to be *LongExpr, found %T", funcName, rs[1])
+	}
+
+	return bytesToLong(funcName, b.Value, offset.Value)
+}
+
+func bytesToLong(funcName string, b []byte, offset int64) (Expr, error) {
+	if offset < 0 || offset > int64(len(b))-8 {
+		return nil, errors.Errorf("%s: offset %d out of range of %d bytes", funcName, offset, len(b))
+	}
+	return NewLong(int64(binary.BigEndian.Uint64(b[offset:]))), nil
+}
+
+// Index of substring in characters, returns Unit if string doesn't contain substring
+func NativeIndexOf(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeIndexOf"
+
+	if l := len(e); l != 2 {
+		return nil, errors.Errorf("%s: invalid params, expected 2, passed %d", funcName, l)
+	}
+
+	rs, err := e.EvaluateAll(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	str, ok := rs[0].(*StringExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *StringExpr, found %T", funcName, rs[0])
+	}
+
+	sub, ok := rs[1].(*StringExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected second argument to be *StringExpr, found %T", funcName, rs[1])
+	}
+
+	return indexOf(str.Value, sub.Value, 0), nil
+}
+
+// Index of substring in characters starting from offset, returns Unit if string doesn't contain substring
+func NativeIndexOfWithOffset(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeIndexOfWithOffset"
+
+	if l := len(e); l != 3 {
+		return nil, errors.Errorf("%s: invalid params, expected 3, passed %d", funcName, l)
+	}
+
+	rs, err := e.EvaluateAll(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	str, ok := rs[0].(*StringExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *StringExpr, found %T", funcName, rs[0])
+	}
+
+	sub, ok := rs[1].(*StringExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected second argument to be *StringExpr, found %T", funcName, rs[1])
+	}
+
+	offset, ok := rs[2].(*LongExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected third argument to be *LongExpr, found %T", funcName, rs[2])
+	}
+
+	return indexOf(str.Value, sub.Value, offset.Value), nil
+}
+
+// indexOf returns index of substring starting from offset, both are counted in UTF-16 code units like in Scala implementation.
+func indexOf(str, sub string, offset int64) Expr {
+	s := utf16.Encode([]rune(str))
+	if offset < 0 || offset > int64(len(s)) {
+		return NewUnit()
+	}
+	u := utf16.Encode([]rune(sub))
+	for i := int(offset); i+len(u) <= len(s); i++ {
+		if equalUnits(s[i:i+len(u)], u) {
+			return NewLong(int64(i))
+		}
+	}
+	return NewUnit()
+}
+
+func equalUnits(a, b []uint16) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Split string by separator to list of strings
+func NativeSplitString(s Scope, e Exprs) (Expr, error) {
+	funcName := "NativeSplitString"
+
+	if l := len(e); l != 2 {
+		return nil, errors.Errorf("%s: invalid params, expected 2, passed %d", funcName, l)
+	}
+
+	rs, err := e.EvaluateAll(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	str, ok := rs[0].(*StringExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected first argument to be *StringExpr, found %T", funcName, rs[0])
+	}
+
+	sep, ok := rs[1].(*StringExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: expected second argument to be *StringExpr, found %T", funcName, rs[1])
+	}
+
+	// Empty string is split to list with single empty string, even by empty separator
+	if str.Value == "" {
+		return Exprs{NewString("")}, nil
+	}
+
+	// Empty separator splits string to UTF-16 code units, halves of surrogate pairs become replacement characters
+	if sep.Value == "" {
+		units := utf16.Encode([]rune(str.Value))
+		out := make(Exprs, len(units))
+		for i, u := range units {
+			out[i] = NewString(string(utf16.Decode([]uint16{u})))
+		}
+		return out, nil
+	}
+
+	parts := strings.Split(str.Value, sep.Value)
+	out := make(Exprs, len(parts))
+	for i, part := range parts {
+		out[i] = NewString(part)
+	}
+
+	return out, nil
+}
+
+// Get integer from data of DataTransaction
+func NativeDataLongFromArray(s Scope, e Exprs) (Expr, error) {
+	return dataFromArray("NativeDataLongFromArray", s, e, proto.DataInteger)
+}
+
+// Get boolean from data of DataTransaction
+func NativeDataBooleanFromArray(s Scope, e Exprs) (Expr, error) {
+	return dataFromArray("NativeDataBooleanFromArray", s, e, proto.DataBoolean)
+}
+
+// Get string from data of DataTransaction
+func NativeDataStringFromArray(s Scope, e Exprs) (Expr, error) {
+	return dataFromArray("NativeDataBooleanFromArray", s, e, proto.DataString)
+}
+
+// Get bytes from data of DataTransaction
+func NativeDataBinaryFromArray(s Scope, e Exprs) (Expr, error) {
+	return dataFromArray("NativeDataBooleanFromArray", s, e, proto.DataBinary)
+}
+
+func dataFromArray(funcName string, s Scope, e Exprs, valueType proto.DataValueType) (Expr, error) {
+	if l := len(e); l != 2 {
+		return nil, errors.Errorf("%s: invalid params, expected 2, passed %d", funcName, l)
+	}
+
+	lstExpr, err := e[0].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	keyExpr, err := e[1].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	lst, ok := lstExpr.(*DataEntryListExpr)
+	if !ok {
+		return nil, errors.Errorf("%s expected first argument to be *DataEntryListExpr, found %T", funcName, lstExpr)
+	}
+
+	key, ok := keyExpr.(*StringExpr)
+	if !ok {
+		return nil, errors.Errorf("%s expected second argument to be *StringExpr, found %T", funcName, keyExpr)
+	}
+
+	return lst.Get(key.Value, valueType), nil
+}
 
 // Get integer from account state
 func NativeDataLongFromState(s Scope, e Exprs) (Expr, error) {
@@ -1074,19 +1838,40 @@ func dataFromState(funcName string, s Scope, e Exprs, valueType proto.DataValueT
 		return nil, err
 	}
 
-	if alias, ok := addOrAliasExpr.(AliasExpr); ok {
-		r := proto.NewRecipientFromAlias(proto.Alias(alias))
-		acc := s.State().Account(r)
-		return dataFromArray(funcName, s.Clone(), Params(NewDataEntryList(acc.Data()), e[1]), valueType)
+	var r proto.Recipient
+	switch v := addOrAliasExpr.(type) {
+	case AliasExpr:
+		r = proto.NewRecipientFromAlias(proto.Alias(v))
+	case AddressExpr:
+		r = proto.NewRecipientFromAddress(proto.Address(v))
+	default:
+		return nil, errors.Errorf("%s expected addOrAliasExpr argument to be AliasExpr or AddressExpr, found %T", funcName, addOrAliasExpr)
 	}
 
-	if addr, ok := addOrAliasExpr.(AddressExpr); ok {
-		r := proto.NewRecipientFromAddress(proto.Address(addr))
-		acc := s.State().Account(r)
-		return dataFromArray(funcName, s.Clone(), Params(NewDataEntryList(acc.Data()), e[1]), valueType)
+	keyExpr, err := e[1].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	key, ok := keyExpr.(*StringExpr)
+	if !ok {
+		return nil, errors.Errorf("%s expected second argument to be *StringExpr, found %T", funcName, keyExpr)
 	}
 
-	return nil, errors.Errorf("%s expected addOrAliasExpr argument to be AliasExpr or AddressExpr, found %T", funcName, addOrAliasExpr)
+	account, err := s.State().Account(r)
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	entry, err := account.DataEntry(key.Value)
+	if err == mockstate.ErrNotFound {
+		return NewUnit(), nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	if entry.GetValueType() != valueType {
+		return NewUnit(), nil
+	}
+	return dataEntryValue(entry), nil
 }
 
 func NativeAddressFromRecipient(s Scope, e Exprs) (Expr, error) {
@@ -1106,7 +1891,11 @@ func NativeAddressFromRecipient(s Scope, e Exprs) (Expr, error) {
 		return nil, errors.Errorf("%s expected first argument to be RecipientExpr, found %T", funcName, recipient)
 	}
 
-	return NewAddressFromProtoAddress(s.State().Account(proto.Recipient(recipient)).Address()), nil
+	account, err := s.State().Account(proto.Recipient(recipient))
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	return NewAddressFromProtoAddress(account.Address()), nil
 }
 
 // Fail script without message (default will be used)
@@ -1349,6 +2138,49 @@ func UserDataStringFromArrayByIndex(s Scope, e Exprs) (Expr, error) {
 	return dataFromArrayByIndex(funcName, s, e, proto.DataString)
 }
 
+// Parse string to integer, fails if string is not a number
+func UserParseIntValue(s Scope, e Exprs) (Expr, error) {
+	funcName := "UserParseIntValue"
+
+	rs, err := NativeParseInt(s, e)
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	return UserExtract(s, Params(rs))
+}
+
+// Value of optional or throw if it is Unit
+func UserValue(s Scope, e Exprs) (Expr, error) {
+	funcName := "UserValue"
+
+	if l := len(e); l != 1 {
+		return nil, errors.Errorf("%s: invalid params, expected 1, passed %d", funcName, l)
+	}
+
+	val, err := e[0].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	if val.InstanceOf() == (Unit{}).InstanceOf() {
+		return NativeThrow(s.Clone(), Params(NewString("value() called on unit value")))
+	}
+
+	return val, nil
+}
+
+func UserAddressFromStringValue(s Scope, e Exprs) (Expr, error) {
+	funcName := "UserAddressFromStringValue"
+
+	rs, err := UserAddressFromString(s, e)
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	return UserValue(s, Params(rs))
+}
+
 func UserAddressFromPublicKey(s Scope, e Exprs) (Expr, error) {
 	funcName := "UserAddressFromPublicKey"
 
@@ -1429,6 +2261,135 @@ func UserAlias(s Scope, e Exprs) (Expr, error) {
 	return NewAliasFromProtoAlias(*alias), nil
 }
 
+func UserDataEntry(s Scope, e Exprs) (Expr, error) {
+	funcName := "UserDataEntry"
+
+	if l := len(e); l != 2 {
+		return nil, errors.Errorf("%s: invalid params, expected 2, passed %d", funcName, l)
+	}
+
+	rs, err := e.EvaluateAll(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	key, ok := rs[0].(*StringExpr)
+	if !ok {
+		return nil, errors.Errorf("%s: first argument expected to be *StringExpr, found %T", funcName, rs[0])
+	}
+
+	switch rs[1].(type) {
+	case *LongExpr, *BooleanExpr, *BytesExpr, *StringExpr:
+	default:
+		return nil, errors.Errorf("%s: unexpected type of value %T", funcName, rs[1])
+	}
+
+	return NewObject(map[string]Expr{
+		"key":             key,
+		"value":           rs[1],
+		InstanceFieldName: NewString("DataEntry"),
+	}), nil
+}
+
+func UserWriteSet(s Scope, e Exprs) (Expr, error) {
+	return listObject("UserWriteSet", "WriteSet", "data", "DataEntry", s, e)
+}
+
+func UserScriptTransfer(s Scope, e Exprs) (Expr, error) {
+	funcName := "UserScriptTransfer"
+
+	if l := len(e); l != 3 {
+		return nil, errors.Errorf("%s: invalid params, expected 3, passed %d", funcName, l)
+	}
+
+	rs, err := e.EvaluateAll(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	switch rs[0].(type) {
+	case AddressExpr, AliasExpr:
+	default:
+		return nil, errors.Errorf("%s: first argument expected to be AddressExpr or AliasExpr, found %T", funcName, rs[0])
+	}
+
+	if _, ok := rs[1].(*LongExpr); !ok {
+		return nil, errors.Errorf("%s: second argument expected to be *LongExpr, found %T", funcName, rs[1])
+	}
+
+	switch rs[2].(type) {
+	case *BytesExpr, Unit:
+	default:
+		return nil, errors.Errorf("%s: third argument expected to be *BytesExpr or Unit, found %T", funcName, rs[2])
+	}
+
+	return NewObject(map[string]Expr{
+		"recipient":       rs[0],
+		"amount":          rs[1],
+		"asset":           rs[2],
+		InstanceFieldName: NewString("ScriptTransfer"),
+	}), nil
+}
+
+func UserTransferSet(s Scope, e Exprs) (Expr, error) {
+	return listObject("UserTransferSet", "TransferSet", "transfers", "ScriptTransfer", s, e)
+}
+
+func UserScriptResult(s Scope, e Exprs) (Expr, error) {
+	funcName := "UserScriptResult"
+
+	if l := len(e); l != 2 {
+		return nil, errors.Errorf("%s: invalid params, expected 2, passed %d", funcName, l)
+	}
+
+	rs, err := e.EvaluateAll(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	if rs[0].InstanceOf() != "WriteSet" {
+		return nil, errors.Errorf("%s: first argument expected to be WriteSet, found %s", funcName, rs[0].InstanceOf())
+	}
+
+	if rs[1].InstanceOf() != "TransferSet" {
+		return nil, errors.Errorf("%s: second argument expected to be TransferSet, found %s", funcName, rs[1].InstanceOf())
+	}
+
+	return NewObject(map[string]Expr{
+		"writeSet":        rs[0],
+		"transferSet":     rs[1],
+		InstanceFieldName: NewString("ScriptResult"),
+	}), nil
+}
+
+// listObject creates object of given instance with single field, which is a list of objects of given instance.
+func listObject(funcName, instance, field, elemInstance string, s Scope, e Exprs) (Expr, error) {
+	if l := len(e); l != 1 {
+		return nil, errors.Errorf("%s: invalid params, expected 1, passed %d", funcName, l)
+	}
+
+	rs, err := e[0].Evaluate(s.Clone())
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+
+	lst, ok := rs.(Exprs)
+	if !ok {
+		return nil, errors.Errorf("%s: first argument expected to be Exprs, found %T", funcName, rs)
+	}
+
+	for _, elem := range lst {
+		if elem.InstanceOf() != elemInstance {
+			return nil, errors.Errorf("%s: expected list of %s, found %s", funcName, elemInstance, elem.InstanceOf())
+		}
+	}
+
+	return NewObject(map[string]Expr{
+		field:             lst,
+		InstanceFieldName: NewString(instance),
+	}), nil
+}
+
 func UserWavesBalance(s Scope, e Exprs) (Expr, error) {
 	return NativeAssetBalance(s, append(e, NewUnit()))
 }
@@ -1462,6 +2423,10 @@ func writeNativeFunction(w io.Writer, id int16, e Exprs) {
 		prefix(w, "throw", e)
 	case 103:
 		infix(w, ">=", e)
+	case 108:
+		prefix(w, "pow", e)
+	case 109:
+		prefix(w, "log", e)
 	case 200:
 		prefix(w, "size", e)
 	case 203, 300:
@@ -1485,10 +2450,18 @@ func writeNativeFunction(w io.Writer, id int16, e Exprs) {
 		prefix(w, "blake2b256", e)
 	case 503:
 		prefix(w, "sha256", e)
+	case 504:
+		prefix(w, "rsaVerify", e)
 	case 600:
 		prefix(w, "toBase58String", e)
 	case 601:
 		prefix(w, "fromBase58String", e)
+	case 604:
+		prefix(w, "toBase16String", e)
+	case 605:
+		prefix(w, "fromBase16String", e)
+	case 700:
+		prefix(w, "checkMerkleProof", e)
 	case 1000:
 		prefix(w, "transactionById", e)
 	case 1001:
@@ -1497,7 +2470,19 @@ func writeNativeFunction(w io.Writer, id int16, e Exprs) {
 		prefix(w, "assetBalance", e)
 	case 1060:
 		prefix(w, "addressFromRecipient", e)
+	case 1100:
+		prefix(w, "cons", e)
+	case 1200:
+		prefix(w, "toUtf8String", e)
+	case 1201, 1202:
+		prefix(w, "toInt", e)
+	case 1203, 1204:
+		prefix(w, "indexOf", e)
+	case 1205:
+		prefix(w, "split", e)
+	case 1206:
+		prefix(w, "parseInt", e)
 	default:
-		prefix(w, fmt.Sprintf("FUNCTION_%d(", id), e)
+		prefix(w, fmt.Sprintf("FUNCTION_%d", id), e)
 	}
 }
@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -8,6 +9,7 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/ride/mockstate"
 	"math"
+	"math/big"
 	"testing"
 	"time"
 )
@@ -292,6 +294,292 @@ func TestNativeFractionLong(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestNativeToBase16String(t *testing.T) {
+	rs, err := NativeToBase16String(newEmptyScope(), Params(NewBytes([]byte("hello"))))
+	require.NoError(t, err)
+	assert.Equal(t, NewString("68656c6c6f"), rs)
+
+	_, err = NativeToBase16String(newEmptyScope(), Params(NewBytes(make([]byte, maxBase16Bytes+1))))
+	require.Error(t, err)
+}
+
+func TestNativeFromBase16String(t *testing.T) {
+	rs, err := NativeFromBase16String(newEmptyScope(), Params(NewString("68656C6c6f")))
+	require.NoError(t, err)
+	assert.Equal(t, NewBytes([]byte("hello")), rs)
+
+	_, err = NativeFromBase16String(newEmptyScope(), Params(NewString("6x")))
+	require.Error(t, err)
+}
+
+// RSA signatures of "hello" made by OpenSSL with PKCS#1 v1.5 padding, NOALG signature is made over the message itself.
+var (
+	rsaTestPublicKey  = "MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQDJnVFGqMtHfy+s5kO0i7LF0oocs6yVnxrpzyYA8oYK4DX5UCQ4MFUs+mIRRBQeYhON8LZD7rIwnDU4fQsn37/CbKBBA4u4qT5FAYoFx2BJK5NmF9E9D1Hc7qhYkx/9iCOoHP7FOFPalxP6RhfdcWkwPicKIZWmtSL7iskAtt3m/wIDAQAB"
+	rsaTestSignatures = []struct {
+		instance  string
+		signature string
+	}{
+		{digestNoAlg, "aMJgAxxpSYBSZOTuhAf9CSnQPbXZ7Lmg0nWqB0EiilDp/ShS5Pj5CrjxMkydGEIjGyZ70eHcygzEszD1ZmZzgJM6qZtvaVbbHbXqZ2WqqBww+u/3lQnxfGFhCYffEpjrcyGhS6AojU8yR3TsRgSam0YrkXEb8KVUsKg/8zV05D8="},
+		{digestMD5, "abEkFYYHcu8LTMr4Nt7k1i+7kFP/YRhk9VyzKeYucOinwbkLSnCgDTOBxCoW4uXrDzAs6aTgKkey+tG9IcrslyDC8ln4wUGqCVNY9a0coOytmGEqWZVDA38+QkuOlypKk/wCTuaYuaMGHhqSyPC/US1Mre6D/MmtOAqPOqAhknI="},
+		{digestSHA1, "Q/ZpKJsezEvXaTWEBARsO1m1Y0taMCFtedi9e0oG1hURSuUCjOZXMfrg7Tv24N+8xIQ5vvdBoXg+trRksfym7OB1Uw84sAAYI5BJ1K8qZf5yoHMJH93VfMvSrxoiEVUm+nf6GFXFFijXiJZIGxG+wJMlhHDzeEx40vWLlx/WnjE="},
+		{digestSHA224, "lh+L4STbevYe1IuLJ5LlSblGWZ0eGrdnAJB0XcgySR1NXsQ3mSvPpDj+sK5kKEMHIleyOT3c1Sjv7tCkHW7Tlm7eil7swKW6g66HZc9DutJ8TfiwSbmjcCGV3UXDyOq46RES3lIyzXlOif8kahT1W4iA1wUZZpU8KCIMXmq7w9o="},
+		{digestSHA256, "FmzAnpd/btznLVDI7zs0zRKHOzh0wc6AeSA02+giyw7yQ5Sdbr5WQrzoH6K5qHYdo6UfTFiMsWMkdoTwazqKnZQacVYU7qm0vXOnnvb4HGxptQKwvWXC4nzamrlkNW+EreWvikfy8vFh5abH13eq20lfcY4NWYRO9Sw+IdKul+Q="},
+		{digestSHA384, "oj63+esTfZBjD1eooNfnFzr7yZ/Wkv/+nHKCZNvuRFcnC9o9Oc9LCdItS6290Bt4PgXfTrGOtMVt9kbJhZP67cS/sV48d017YlnKVuJqlAfvcZOeb6cBkb7CCqXhSE0TgMz1GP1uJRBKln1VKgbmf6+Maqqr1oiUI8U0vX6bfs0="},
+		{digestSHA512, "clRnL/IVqrXsJ5CfrFMAcIjuSmFqGTnKWgY2fypMSFQrS2INogtoGEcoG0tG/R4uItAF+vzAmMzdRYmDgHe/qwCe1BW8ZOnDsnPoegYnFBVN1iI8gUqN+hQLEwWnOGc/KC2XLhHrUSIpSLucy+kjA4pRtYGafrrtnH8ofDRJ8Pk="},
+		{digestSHA3224, "gVe3nBiEVTkhI8cSkjEGQ93S0YX/4M9ijH8UcW1g4YyBLmsPgnnq35Qi5BCpERmPssIVCV7UJWkMuyEQLU3NoL5Orfl6r3G+r/J+9ujCWR2mwgUpJfcfT+C9eNrgRoRoCzYbZohNUG7m/xKfQMD9mdgpfyZMjlsfz3Z1tvajQG0="},
+		{digestSHA3256, "Ma8+Sz+JtqWE6pkX5U02fBAPzBGEH6AUdYFT3ny7t5rLYKiTqGZ8m78VbKtSQQvq9M2GwOkuFZjd3ueRLSZBUkcjBs8NvDcHi9qv1kwmg1QuZU28Qqd/ezyMeyfqsHaOXrzPMt8UweccuPhaAA/ZIsCODmpoPz0GmRSHfnetn3s="},
+		{digestSHA3384, "GA35FOWch4Yqp+fSYNM1/9OVUGpn9XZ5NOMtW6z09eEcFg1JsfLJbYTXS++VLEMNdd+6dJWVMK33YR0xvdg2wqFVrNZwGNwof8tddUyhkjIfJhqHc01WBqWqu6yTHHjHIDAOQf4UcXMjaD6l6lKVtIwWHOCdu7S1qgxPOb7sE3g="},
+		{digestSHA3512, "lTVhjz2fSlyPCdMiIL7HIHLiPSSaw1ejsWkSbjL+WrY6WFbHeezvM/8LLPifl0G9kR0pX1NgLKLk016b2w89X3zcpoxsMhcPyBm3hseb2SmpHawWORbLZwnfqxsDxfIPV+uoFHOd/kXqc36G6PI/7BEiCii8DVMTTT4fM5COxOU="},
+	}
+)
+
+func TestNativeRSAVerify(t *testing.T) {
+	pk, err := base64.StdEncoding.DecodeString(rsaTestPublicKey)
+	require.NoError(t, err)
+	message := []byte("hello")
+
+	for i, test := range rsaTestSignatures {
+		sig, err := base64.StdEncoding.DecodeString(test.signature)
+		require.NoError(t, err, test.instance)
+
+		alg := NewObject(map[string]Expr{InstanceFieldName: NewString(test.instance)})
+		rs, err := NativeRSAVerify(newEmptyScope(), Params(alg, NewBytes(message), NewBytes(sig), NewBytes(pk)))
+		require.NoError(t, err, test.instance)
+		assert.Equal(t, NewBoolean(true), rs, test.instance)
+
+		rs, err = NativeRSAVerify(newEmptyScope(), Params(alg, NewBytes([]byte("hellO")), NewBytes(sig), NewBytes(pk)))
+		require.NoError(t, err, test.instance)
+		assert.Equal(t, NewBoolean(false), rs, test.instance)
+
+		// Signature made with another digest has different DigestInfo.
+		other := rsaTestSignatures[(i+1)%len(rsaTestSignatures)]
+		alg = NewObject(map[string]Expr{InstanceFieldName: NewString(other.instance)})
+		rs, err = NativeRSAVerify(newEmptyScope(), Params(alg, NewBytes(message), NewBytes(sig), NewBytes(pk)))
+		require.NoError(t, err, test.instance)
+		assert.Equal(t, NewBoolean(false), rs, test.instance)
+	}
+
+	_, err = NativeRSAVerify(newEmptyScope(), Params(NewUnit(), NewBytes(message), NewBytes(nil), NewBytes(pk)))
+	require.Error(t, err)
+	_, err = NativeRSAVerify(newEmptyScope(), Params(roundingMode(digestSHA256), NewBytes(message), NewBytes(nil), NewBytes([]byte("key"))))
+	require.Error(t, err)
+}
+
+func TestNativeCheckMerkleProof(t *testing.T) {
+	// Tree of leafs "a", "b", "c", "d" with BLAKE2b-256 hashes, leafs are prefixed with 0 and nodes with 1.
+	// Each level of proof is the side of node (0 if it is on the left of its sibling), size and hash of sibling.
+	root, err := hex.DecodeString("421360a6099803b465dff5246cb164c2b6ffac54f17cecc04fa7bfe2561e1804")
+	require.NoError(t, err)
+	proofs := map[string]string{
+		"a": "0020b3d5dedf654e9fc853bdc5daf79330c5a1eaf2b910f2a36c72ef8ea999ccf9530020af66a593dfe7f069b7f3734a87a67df3d87419e270fcf869ab01218e350d3c95",
+		"b": "01207234082e1dd0b5ec0acd71875d61c9f374af30c100bc4de7aa4eb3f15bbed6860020af66a593dfe7f069b7f3734a87a67df3d87419e270fcf869ab01218e350d3c95",
+		"c": "00209fa96117cda5187dd7699a57a16cbf37bb3353c1385e7854ab6e131df63cc6590120ee616625a590167bc4b3dc703ab4f3f2ddecbee6b9d05fee9281f02046e6082e",
+		"d": "0120960259f5c0885e7b7967cc25158bc9069db1ca8222e7beffdfffe5dea02979660120ee616625a590167bc4b3dc703ab4f3f2ddecbee6b9d05fee9281f02046e6082e",
+	}
+	for leaf, p := range proofs {
+		proof, err := hex.DecodeString(p)
+		require.NoError(t, err)
+
+		rs, err := NativeCheckMerkleProof(newEmptyScope(), Params(NewBytes(root), NewBytes(proof), NewBytes([]byte(leaf))))
+		require.NoError(t, err, leaf)
+		assert.Equal(t, NewBoolean(true), rs, leaf)
+
+		rs, err = NativeCheckMerkleProof(newEmptyScope(), Params(NewBytes(root), NewBytes(proof), NewBytes([]byte("e"))))
+		require.NoError(t, err, leaf)
+		assert.Equal(t, NewBoolean(false), rs, leaf)
+
+		// Flipped side of the first level.
+		flipped := append([]byte{1 - proof[0]}, proof[1:]...)
+		rs, err = NativeCheckMerkleProof(newEmptyScope(), Params(NewBytes(root), NewBytes(flipped), NewBytes([]byte(leaf))))
+		require.NoError(t, err, leaf)
+		assert.Equal(t, NewBoolean(false), rs, leaf)
+
+		rs, err = NativeCheckMerkleProof(newEmptyScope(), Params(NewBytes(root), NewBytes(proof[:40]), NewBytes([]byte(leaf))))
+		require.NoError(t, err, leaf)
+		assert.Equal(t, NewBoolean(false), rs, leaf)
+	}
+}
+
+func TestNativeBytesToLong(t *testing.T) {
+	b := []byte{0, 0, 0, 0, 0, 0, 0, 1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	rs, err := NativeBytesToLong(newEmptyScope(), Params(NewBytes(b)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(1), rs)
+
+	rs, err = NativeBytesToLongWithOffset(newEmptyScope(), Params(NewBytes(b), NewLong(8)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(-1), rs)
+
+	rs, err = NativeBytesToLongWithOffset(newEmptyScope(), Params(NewBytes(b), NewLong(1)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(0x1ff), rs)
+
+	_, err = NativeBytesToLongWithOffset(newEmptyScope(), Params(NewBytes(b), NewLong(9)))
+	require.Error(t, err)
+	_, err = NativeBytesToLongWithOffset(newEmptyScope(), Params(NewBytes(b), NewLong(-1)))
+	require.Error(t, err)
+	_, err = NativeBytesToLong(newEmptyScope(), Params(NewBytes(b[:7])))
+	require.Error(t, err)
+}
+
+func TestNativeIndexOf(t *testing.T) {
+	rs, err := NativeIndexOf(newEmptyScope(), Params(NewString("привет мир мир"), NewString("мир")))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(7), rs)
+
+	rs, err = NativeIndexOf(newEmptyScope(), Params(NewString("привет"), NewString("мир")))
+	require.NoError(t, err)
+	assert.Equal(t, NewUnit(), rs)
+
+	rs, err = NativeIndexOfWithOffset(newEmptyScope(), Params(NewString("привет мир мир"), NewString("мир"), NewLong(8)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(11), rs)
+
+	rs, err = NativeIndexOfWithOffset(newEmptyScope(), Params(NewString("привет"), NewString(""), NewLong(6)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(6), rs)
+
+	rs, err = NativeIndexOfWithOffset(newEmptyScope(), Params(NewString("привет"), NewString(""), NewLong(7)))
+	require.NoError(t, err)
+	assert.Equal(t, NewUnit(), rs)
+
+	// Characters outside of BMP take two UTF-16 code units.
+	rs, err = NativeIndexOf(newEmptyScope(), Params(NewString("\U0001F600a\U0001F600b"), NewString("b")))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(5), rs)
+
+	rs, err = NativeIndexOfWithOffset(newEmptyScope(), Params(NewString("\U0001F600a\U0001F600b"), NewString("\U0001F600"), NewLong(1)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(3), rs)
+
+	rs, err = NativeIndexOfWithOffset(newEmptyScope(), Params(NewString("\U0001F600"), NewString(""), NewLong(2)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(2), rs)
+
+	rs, err = NativeIndexOfWithOffset(newEmptyScope(), Params(NewString("\U0001F600"), NewString(""), NewLong(3)))
+	require.NoError(t, err)
+	assert.Equal(t, NewUnit(), rs)
+}
+
+func TestNativeSplitString(t *testing.T) {
+	rs, err := NativeSplitString(newEmptyScope(), Params(NewString("a,b,,c"), NewString(",")))
+	require.NoError(t, err)
+	assert.Equal(t, Exprs{NewString("a"), NewString("b"), NewString(""), NewString("c")}, rs)
+
+	rs, err = NativeSplitString(newEmptyScope(), Params(NewString("абв"), NewString("")))
+	require.NoError(t, err)
+	assert.Equal(t, Exprs{NewString("а"), NewString("б"), NewString("в")}, rs)
+
+	rs, err = NativeSplitString(newEmptyScope(), Params(NewString(""), NewString(",")))
+	require.NoError(t, err)
+	assert.Equal(t, Exprs{NewString("")}, rs)
+
+	// Character outside of BMP is split to halves of surrogate pair.
+	rs, err = NativeSplitString(newEmptyScope(), Params(NewString("a\U0001F600"), NewString("")))
+	require.NoError(t, err)
+	assert.Equal(t, Exprs{NewString("a"), NewString("\uFFFD"), NewString("\uFFFD")}, rs)
+
+	rs, err = NativeSplitString(newEmptyScope(), Params(NewString("a\U0001F600b"), NewString("\U0001F600")))
+	require.NoError(t, err)
+	assert.Equal(t, Exprs{NewString("a"), NewString("b")}, rs)
+}
+
+func roundingMode(instance string) Expr {
+	return NewObject(map[string]Expr{InstanceFieldName: NewString(instance)})
+}
+
+func TestNativePowLong(t *testing.T) {
+	// 1.2 ^ 3.456 = 1.8779...
+	rs, err := NativePowLong(newEmptyScope(), Params(NewLong(12), NewLong(1), NewLong(3456), NewLong(3), NewLong(2), roundingMode(roundingDown)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(187), rs)
+
+	rs, err = NativePowLong(newEmptyScope(), Params(NewLong(12), NewLong(1), NewLong(3456), NewLong(3), NewLong(2), roundingMode(roundingUp)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(188), rs)
+
+	// 2 ^ 10
+	rs, err = NativePowLong(newEmptyScope(), Params(NewLong(2), NewLong(0), NewLong(10), NewLong(0), NewLong(0), roundingMode(roundingHalfEven)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(1024), rs)
+
+	// precision out of range
+	_, err = NativePowLong(newEmptyScope(), Params(NewLong(2), NewLong(9), NewLong(10), NewLong(0), NewLong(0), roundingMode(roundingDown)))
+	require.Error(t, err)
+
+	// overflow
+	_, err = NativePowLong(newEmptyScope(), Params(NewLong(10), NewLong(0), NewLong(20), NewLong(0), NewLong(0), roundingMode(roundingDown)))
+	require.Error(t, err)
+}
+
+func TestNativeLogLong(t *testing.T) {
+	rs, err := NativeLogLong(newEmptyScope(), Params(NewLong(16), NewLong(0), NewLong(2), NewLong(0), NewLong(0), roundingMode(roundingCeiling)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(4), rs)
+
+	// ln(100) = 4.60517...
+	rs, err = NativeLogLong(newEmptyScope(), Params(NewLong(100), NewLong(0), NewLong(271828183), NewLong(8), NewLong(3), roundingMode(roundingHalfUp)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(4605), rs)
+
+	// logarithm of negative number is not a number
+	_, err = NativeLogLong(newEmptyScope(), Params(NewLong(-1), NewLong(0), NewLong(2), NewLong(0), NewLong(0), roundingMode(roundingDown)))
+	require.Error(t, err)
+}
+
+func TestSetDecimalScale(t *testing.T) {
+	for _, c := range []struct {
+		unscaled int64
+		mode     string
+		result   int64
+	}{
+		{25, roundingUp, 3},
+		{25, roundingDown, 2},
+		{25, roundingHalfUp, 3},
+		{25, roundingHalfDown, 2},
+		{25, roundingHalfEven, 2},
+		{35, roundingHalfEven, 4},
+		{-25, roundingCeiling, -2},
+		{-25, roundingFloor, -3},
+		{-25, roundingHalfUp, -3},
+		{26, roundingHalfDown, 3},
+	} {
+		rs, err := setDecimalScale(big.NewInt(c.unscaled), 1, 0, c.mode)
+		require.NoError(t, err)
+		assert.Equal(t, c.result, rs.Int64(), "%d with mode %s", c.unscaled, c.mode)
+	}
+
+	rs, err := setDecimalScale(big.NewInt(5), 0, 2, roundingDown)
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), rs.Int64())
+
+	_, err = setDecimalScale(big.NewInt(25), 1, 0, "Unknown")
+	require.Error(t, err)
+}
+
+func TestNativeParseInt(t *testing.T) {
+	rs, err := NativeParseInt(newEmptyScope(), Params(NewString("-42")))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(-42), rs)
+
+	rs, err = NativeParseInt(newEmptyScope(), Params(NewString("4x")))
+	require.NoError(t, err)
+	assert.Equal(t, NewUnit(), rs)
+
+	rs, err = UserParseIntValue(newEmptyScope(), Params(NewString("42")))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(42), rs)
+
+	_, err = UserParseIntValue(newEmptyScope(), Params(NewString("")))
+	require.Error(t, err)
+}
+
+func TestNativeBytesToUTF8String(t *testing.T) {
+	rs, err := NativeBytesToUTF8String(newEmptyScope(), Params(NewBytes([]byte("привет"))))
+	require.NoError(t, err)
+	assert.Equal(t, NewString("привет"), rs)
+}
+
 func TestNativeStringToBytes(t *testing.T) {
 	rs, err := NativeStringToBytes(newEmptyScope(), NewExprs(NewString("привет")))
 	require.NoError(t, err)
@@ -386,6 +674,151 @@ func TestNativeAssetBalance_FromAlias(t *testing.T) {
 	rs, err := NativeAssetBalance(scope, Params(NewAliasFromProtoAlias(*alias), NewBytes(d.Bytes())))
 	require.NoError(t, err)
 	assert.Equal(t, NewLong(5), rs)
+
+	unknown := proto.NewAlias(scope.Scheme(), "unknown")
+	_, err = NativeAssetBalance(scope, Params(NewAliasFromProtoAlias(*unknown), NewUnit()))
+	assert.Error(t, err)
+}
+
+func TestNativeAssetInfo(t *testing.T) {
+	d, err := crypto.NewDigestFromBase58("BXBUNddxTGTQc3G4qHYn5E67SBwMj18zLncUr871iuRD")
+	require.NoError(t, err)
+	_, pk := crypto.GenerateKeyPair([]byte("asset issuer"))
+	issuer, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, pk)
+	require.NoError(t, err)
+
+	s := mockstate.MockStateImpl{
+		Assets: map[string]*mockstate.AssetInfo{d.String(): {
+			ID:              d,
+			Quantity:        1000,
+			Decimals:        2,
+			Issuer:          issuer,
+			IssuerPublicKey: pk,
+			Reissuable:      true,
+			Scripted:        false,
+			Sponsored:       true,
+		}},
+	}
+
+	rs, err := NativeAssetInfo(newScopeWithState(s), Params(NewBytes(d.Bytes())))
+	require.NoError(t, err)
+	asset, ok := rs.(*ObjectExpr)
+	require.True(t, ok)
+	assert.Equal(t, "Asset", asset.InstanceOf())
+	for name, value := range map[string]Expr{
+		"id":              NewBytes(d.Bytes()),
+		"quantity":        NewLong(1000),
+		"decimals":        NewLong(2),
+		"issuer":          NewAddressFromProtoAddress(issuer),
+		"issuerPublicKey": NewBytes(pk.Bytes()),
+		"reissuable":      NewBoolean(true),
+		"scripted":        NewBoolean(false),
+		"sponsored":       NewBoolean(true),
+	} {
+		field, err := asset.Get(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, value, field, name)
+	}
+
+	rs, err = NativeAssetInfo(newScopeWithState(s), Params(NewBytes(make([]byte, 32))))
+	require.NoError(t, err)
+	assert.Equal(t, NewUnit(), rs)
+
+	rs, err = NativeAssetInfo(newScopeWithState(s), Params(NewBytes([]byte{1, 2, 3})))
+	require.NoError(t, err)
+	assert.Equal(t, NewUnit(), rs)
+}
+
+func TestNativeBlockInfoByHeight(t *testing.T) {
+	_, pk := crypto.GenerateKeyPair([]byte("block generator"))
+	generator, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, pk)
+	require.NoError(t, err)
+	genSig, err := crypto.NewDigestFromBase58("BXBUNddxTGTQc3G4qHYn5E67SBwMj18zLncUr871iuRD")
+	require.NoError(t, err)
+
+	s := mockstate.MockStateImpl{
+		Blocks: map[uint64]*mockstate.BlockInfo{2: {
+			Timestamp:           1563000000000,
+			Height:              2,
+			BaseTarget:          153722867,
+			GenerationSignature: genSig,
+			Generator:           generator,
+			GeneratorPublicKey:  pk,
+		}},
+	}
+	expected := NewObject(map[string]Expr{
+		"timestamp":           NewLong(1563000000000),
+		"height":              NewLong(2),
+		"baseTarget":          NewLong(153722867),
+		"generationSignature": NewBytes(genSig.Bytes()),
+		"generator":           NewAddressFromProtoAddress(generator),
+		"generatorPublicKey":  NewBytes(pk.Bytes()),
+		InstanceFieldName:     NewString("BlockInfo"),
+	})
+
+	rs, err := NativeBlockInfoByHeight(newScopeWithState(s), Params(NewLong(2)))
+	require.NoError(t, err)
+	assert.Equal(t, expected, rs)
+
+	rs, err = NativeBlockInfoByHeight(newScopeWithState(s), Params(NewLong(3)))
+	require.NoError(t, err)
+	assert.Equal(t, NewUnit(), rs)
+
+	rs, err = NativeBlockInfoByHeight(newScopeWithState(s), Params(NewLong(-1)))
+	require.NoError(t, err)
+	assert.Equal(t, NewUnit(), rs)
+
+	// lastBlock of version 3 is the block at current height.
+	scope := NewScope(proto.MainNetScheme, s, newFuncScopeV3(), map[string]Expr{"height": NewLong(2)})
+	rs, err = (&RefExpr{Name: "lastBlock"}).Evaluate(scope)
+	require.NoError(t, err)
+	assert.Equal(t, expected, rs)
+}
+
+func TestNativeTransferTransactionByID(t *testing.T) {
+	secret, public := crypto.GenerateKeyPair([]byte("abcde"))
+	sender, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, public)
+	require.NoError(t, err)
+
+	transfer := proto.NewUnsignedTransferV1(public, proto.OptionalAsset{}, proto.OptionalAsset{}, 1563000000000, 1, 10000, proto.NewRecipientFromAddress(sender), "")
+	require.NoError(t, transfer.Sign(secret))
+	lease := proto.NewUnsignedLeaseV1(public, proto.NewRecipientFromAddress(sender), 1, 10000, 1563000000000)
+	require.NoError(t, lease.Sign(secret))
+
+	s := mockstate.MockStateImpl{
+		TransactionsByID: map[string]proto.Transaction{
+			transfer.ID.String(): transfer,
+			lease.ID.String():    lease,
+		},
+	}
+
+	rs, err := NativeTransferTransactionByID(newScopeWithState(s), Params(NewBytes(transfer.ID.Bytes())))
+	require.NoError(t, err)
+	assert.Equal(t, "TransferTransaction", rs.InstanceOf())
+
+	rs, err = NativeTransferTransactionByID(newScopeWithState(s), Params(NewBytes(lease.ID.Bytes())))
+	require.NoError(t, err)
+	assert.Equal(t, NewUnit(), rs)
+
+	rs, err = NativeTransferTransactionByID(newScopeWithState(s), Params(NewBytes(make([]byte, 64))))
+	require.NoError(t, err)
+	assert.Equal(t, NewUnit(), rs)
+}
+
+func TestUserValue(t *testing.T) {
+	rs, err := UserValue(newEmptyScope(), Params(NewLong(1)))
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(1), rs)
+
+	_, err = UserValue(newEmptyScope(), Params(NewUnit()))
+	require.Error(t, err)
+
+	rs, err = UserAddressFromStringValue(newEmptyScope(), Params(NewString("3PJaDyprvekvPXPuAtxrapacuDJopgJRaU3")))
+	require.NoError(t, err)
+	assert.Equal(t, "AddressExpr", rs.InstanceOf())
+
+	_, err = UserAddressFromStringValue(newEmptyScope(), Params(NewString("invalid")))
+	require.Error(t, err)
 }
 
 func TestNativeDataFromArray(t *testing.T) {
@@ -602,3 +1035,42 @@ func TestUserAlias(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, NewAliasFromProtoAlias(*alias), rs1)
 }
+
+func TestNativeCreateList(t *testing.T) {
+	rs, err := NativeCreateList(newEmptyScope(), Params(NewLong(1), Exprs{}))
+	require.NoError(t, err)
+	assert.Equal(t, Exprs{NewLong(1)}, rs)
+
+	rs, err = NativeCreateList(newEmptyScope(), Params(NewLong(1), Exprs{NewLong(2)}))
+	require.NoError(t, err)
+	assert.Equal(t, Exprs{NewLong(1), NewLong(2)}, rs)
+
+	_, err = NativeCreateList(newEmptyScope(), Params(NewLong(1), NewLong(2)))
+	require.Error(t, err)
+}
+
+func TestUserScriptResult(t *testing.T) {
+	addr, err := proto.NewAddressFromString("3N9WtaPoD1tMrDZRG26wA142Byd35tLhnLU")
+	require.NoError(t, err)
+
+	entry, err := UserDataEntry(newEmptyScope(), Params(NewString("key"), NewLong(5)))
+	require.NoError(t, err)
+	_, err = UserDataEntry(newEmptyScope(), Params(NewString("key"), NewUnit()))
+	require.Error(t, err)
+	writeSet, err := UserWriteSet(newEmptyScope(), Params(Exprs{entry}))
+	require.NoError(t, err)
+
+	transfer, err := UserScriptTransfer(newEmptyScope(), Params(NewAddressFromProtoAddress(addr), NewLong(10), NewUnit()))
+	require.NoError(t, err)
+	_, err = UserTransferSet(newEmptyScope(), Params(Exprs{entry}))
+	require.Error(t, err)
+	transferSet, err := UserTransferSet(newEmptyScope(), Params(Exprs{transfer}))
+	require.NoError(t, err)
+
+	rs, err := UserScriptResult(newEmptyScope(), Params(writeSet, transferSet))
+	require.NoError(t, err)
+	res, err := NewScriptResult(rs)
+	require.NoError(t, err)
+	assert.Equal(t, []proto.DataEntry{proto.IntegerDataEntry{Key: "key", Value: 5}}, res.Writes)
+	assert.Equal(t, []ScriptTransfer{{Recipient: proto.NewRecipientFromAddress(addr), Amount: 10}}, res.Transfers)
+}
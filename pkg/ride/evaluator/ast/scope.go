@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/ride/mockstate"
 )
@@ -12,19 +13,23 @@ type Account interface {
 type Scope interface {
 	Clone() Scope
 	AddValue(name string, expr Expr)
+	AddFunction(name string, f Callable)
 	FuncByShort(int16) (Callable, bool)
 	FuncByName(string) (Callable, bool)
 	Value(string) (Expr, bool)
 	State() mockstate.MockState
 	Scheme() byte
+	// EvaluateIf is a hook for evaluation of if expressions, which lets scopes record the taken branch.
+	EvaluateIf(e *IfExpr) (Expr, error)
 }
 
 type ScopeImpl struct {
-	parent    Scope
-	funcs     *FuncScope
-	variables map[string]Expr
-	state     mockstate.MockState
-	scheme    byte
+	parent        Scope
+	funcs         *FuncScope
+	declaredFuncs map[string]Callable
+	variables     map[string]Expr
+	state         mockstate.MockState
+	scheme        byte
 }
 
 type Callable func(Scope, Exprs) (Expr, error)
@@ -43,6 +48,7 @@ func (a *ScopeImpl) Clone() Scope {
 		funcs:  a.funcs.Clone(),
 		parent: a,
 		state:  a.state,
+		scheme: a.scheme,
 	}
 }
 
@@ -55,9 +61,23 @@ func (a *ScopeImpl) FuncByShort(id int16) (Callable, bool) {
 }
 
 func (a *ScopeImpl) FuncByName(name string) (Callable, bool) {
+	// functions declared in script shadow predefined ones
+	if f, ok := a.declaredFuncs[name]; ok {
+		return f, true
+	}
+	if a.parent != nil {
+		return a.parent.FuncByName(name)
+	}
 	return a.funcs.GetByName(name)
 }
 
+func (a *ScopeImpl) AddFunction(name string, f Callable) {
+	if a.declaredFuncs == nil {
+		a.declaredFuncs = make(map[string]Callable)
+	}
+	a.declaredFuncs[name] = f
+}
+
 func (a *ScopeImpl) AddValue(name string, value Expr) {
 	if a.variables == nil {
 		a.variables = make(map[string]Expr)
@@ -76,28 +96,49 @@ func (a *ScopeImpl) Value(name string) (Expr, bool) {
 	// try find in parent
 	if a.parent != nil {
 		return a.parent.Value(name)
-	} else {
-		return nil, false
 	}
+
+	// constants of standard library are shadowed by script values
+	return a.funcs.GetValue(name)
 }
 
 func (a *ScopeImpl) Scheme() byte {
 	return a.scheme
 }
 
+func (a *ScopeImpl) EvaluateIf(e *IfExpr) (Expr, error) {
+	return e.evaluate(a, nil)
+}
+
+// FuncScope holds functions and constants of the standard library of given version.
 type FuncScope struct {
 	funcs     map[int16]Callable
 	userFuncs map[string]Callable
+	values    map[string]Expr
 }
 
 func EmptyFuncScope() *FuncScope {
 	return &FuncScope{
 		funcs:     make(map[int16]Callable),
 		userFuncs: make(map[string]Callable),
+		values:    make(map[string]Expr),
 	}
 }
 
-func NewFuncScope() *FuncScope {
+// NewFuncScope returns functions and constants available to scripts of the given version of standard library.
+func NewFuncScope(version int) (*FuncScope, error) {
+	switch version {
+	case 1, 2:
+		return newFuncScopeV12(), nil
+	case 3:
+		return newFuncScopeV3(), nil
+	default:
+		return nil, errors.Errorf("unsupported standard library version %d", version)
+	}
+}
+
+// Versions 1 and 2 differ only in transaction types, the functions are the same.
+func newFuncScopeV12() *FuncScope {
 
 	funcs := make(map[int16]Callable)
 
@@ -186,7 +227,75 @@ func NewFuncScope() *FuncScope {
 	return &FuncScope{
 		funcs:     funcs,
 		userFuncs: userFuncs,
+		values:    make(map[string]Expr),
+	}
+}
+
+// Version 3 adds lists, decimal math, DApp results and a few conversions.
+func newFuncScopeV3() *FuncScope {
+	s := newFuncScopeV12()
+
+	s.funcs[108] = NativePowLong
+	s.funcs[109] = NativeLogLong
+	s.funcs[504] = NativeRSAVerify
+	s.funcs[604] = NativeToBase16String
+	s.funcs[605] = NativeFromBase16String
+	s.funcs[700] = NativeCheckMerkleProof
+	s.funcs[1004] = NativeAssetInfo
+	s.funcs[1005] = NativeBlockInfoByHeight
+	s.funcs[1006] = NativeTransferTransactionByID
+	s.funcs[1100] = NativeCreateList
+	s.funcs[1200] = NativeBytesToUTF8String
+	s.funcs[1201] = NativeBytesToLong
+	s.funcs[1202] = NativeBytesToLongWithOffset
+	s.funcs[1203] = NativeIndexOf
+	s.funcs[1204] = NativeIndexOfWithOffset
+	s.funcs[1205] = NativeSplitString
+	s.funcs[1206] = NativeParseInt
+
+	s.userFuncs["parseIntValue"] = UserParseIntValue
+	s.userFuncs["value"] = UserValue
+	s.userFuncs["addressFromStringValue"] = UserAddressFromStringValue
+
+	// type constructors
+	s.userFuncs["DataEntry"] = UserDataEntry
+	s.userFuncs["WriteSet"] = UserWriteSet
+	s.userFuncs["ScriptTransfer"] = UserScriptTransfer
+	s.userFuncs["TransferSet"] = UserTransferSet
+	s.userFuncs["ScriptResult"] = UserScriptResult
+
+	s.values["unit"] = NewUnit()
+	s.values["nil"] = Exprs{}
+	// Block at current height, it is taken from state on reference like blockInfoByHeight(height)
+	s.values["lastBlock"] = NewNativeFunction(1005, 1, Params(&RefExpr{Name: "height"}))
+	for name, instance := range map[string]string{
+		"DOWN":     roundingDown,
+		"UP":       roundingUp,
+		"HALFUP":   roundingHalfUp,
+		"HALFDOWN": roundingHalfDown,
+		"HALFEVEN": roundingHalfEven,
+		"CEILING":  roundingCeiling,
+		"FLOOR":    roundingFloor,
+	} {
+		s.values[name] = NewObject(map[string]Expr{InstanceFieldName: NewString(instance)})
+	}
+	for name, instance := range map[string]string{
+		"NOALG":   digestNoAlg,
+		"MD5":     digestMD5,
+		"SHA1":    digestSHA1,
+		"SHA224":  digestSHA224,
+		"SHA256":  digestSHA256,
+		"SHA384":  digestSHA384,
+		"SHA512":  digestSHA512,
+		"SHA3224": digestSHA3224,
+		"SHA3256": digestSHA3256,
+		"SHA3384": digestSHA3384,
+		"SHA3512": digestSHA3512,
+	} {
+		s.values[name] = NewObject(map[string]Expr{InstanceFieldName: NewString(instance)})
 	}
+
+	return s
 }
 
 func (a *FuncScope) GetByShort(id int16) (Callable, bool) {
@@ -199,6 +308,12 @@ func (a *FuncScope) GetByName(name string) (Callable, bool) {
 	return f, ok
 }
 
+// GetValue returns predefined constant, e.g. `unit`.
+func (a *FuncScope) GetValue(name string) (Expr, bool) {
+	v, ok := a.values[name]
+	return v, ok
+}
+
 func (a *FuncScope) Clone() *FuncScope {
 	return a
 }
@@ -45,3 +45,58 @@ func TestScopeImpl_Scheme(t *testing.T) {
 	s := newEmptyScope()
 	assert.Equal(t, proto.MainNetScheme, s.Scheme())
 }
+
+func TestScopeImpl_AddFunction(t *testing.T) {
+	parent := newEmptyScope()
+	_, ok := parent.FuncByName("f")
+	assert.False(t, ok)
+
+	parent.AddFunction("f", func(Scope, Exprs) (Expr, error) { return NewLong(1), nil })
+	child := parent.Clone()
+	_, ok = child.FuncByName("f")
+	assert.True(t, ok)
+	assert.Equal(t, proto.MainNetScheme, child.Scheme())
+
+	// function declared in child is not visible in parent
+	child.AddFunction("g", func(Scope, Exprs) (Expr, error) { return NewLong(2), nil })
+	_, ok = parent.FuncByName("g")
+	assert.False(t, ok)
+}
+
+func TestNewFuncScope(t *testing.T) {
+	v1, err := NewFuncScope(1)
+	assert.NoError(t, err)
+	_, ok := v1.GetByShort(108)
+	assert.False(t, ok)
+	_, ok = v1.GetByName("WriteSet")
+	assert.False(t, ok)
+	_, ok = v1.GetValue("unit")
+	assert.False(t, ok)
+
+	v3, err := NewFuncScope(3)
+	assert.NoError(t, err)
+	_, ok = v3.GetByShort(108)
+	assert.True(t, ok)
+	for _, id := range []int16{504, 604, 605, 700, 1201, 1202, 1203, 1204, 1205} {
+		_, ok = v1.GetByShort(id)
+		assert.False(t, ok, id)
+		_, ok = v3.GetByShort(id)
+		assert.True(t, ok, id)
+	}
+	_, ok = v3.GetValue("SHA3256")
+	assert.True(t, ok)
+	_, ok = v3.GetByName("WriteSet")
+	assert.True(t, ok)
+
+	// constants are visible in all scopes and can be shadowed
+	s := NewScope(proto.MainNetScheme, mockstate.MockStateImpl{}, v3, nil)
+	e, ok := s.Clone().Value("unit")
+	assert.True(t, ok)
+	assert.Equal(t, NewUnit(), e)
+	s.AddValue("unit", NewLong(1))
+	e, _ = s.Clone().Value("unit")
+	assert.Equal(t, NewLong(1), e)
+
+	_, err = NewFuncScope(4)
+	assert.Error(t, err)
+}
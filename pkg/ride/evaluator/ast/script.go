@@ -0,0 +1,8 @@
+package ast
+
+// Script is an expression script, its expression is a verifier of transactions.
+type Script struct {
+	// Version of standard library.
+	Version  int
+	Verifier Expr
+}
@@ -0,0 +1,282 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/ride/mockstate"
+)
+
+type TraceKind string
+
+const (
+	TraceLet  TraceKind = "let"
+	TraceCall TraceKind = "call"
+	TraceIf   TraceKind = "if"
+)
+
+// TraceStep is a single step of script evaluation: let binding, function call or if expression.
+// Steps made while evaluating the step are nested into it.
+type TraceStep struct {
+	Kind TraceKind
+	// Name of let binding or user function.
+	Name string
+	// FunctionID of native function, valid only for calls with empty Name.
+	FunctionID int16
+	// Args are evaluated arguments of function call.
+	Args Exprs
+	// Condition of if expression and the branch taken.
+	Condition Expr
+	Branch    bool
+	Result    Expr
+	Err       error
+	Steps     []*TraceStep
+}
+
+func (a *TraceStep) Write(w io.Writer) {
+	switch a.Kind {
+	case TraceLet:
+		_, _ = fmt.Fprintf(w, "let %s", a.Name)
+	case TraceCall:
+		if a.Name == "" {
+			writeNativeFunction(w, a.FunctionID, a.Args)
+		} else {
+			prefix(w, a.Name, a.Args)
+		}
+	case TraceIf:
+		_, _ = fmt.Fprint(w, "if ( ")
+		a.Condition.Write(w)
+		_, _ = fmt.Fprintf(w, " ) -> %t", a.Branch)
+		if a.Err != nil {
+			_, _ = fmt.Fprint(w, " -> ")
+			writeTraceError(w, a.Err)
+		}
+		return
+	}
+	_, _ = fmt.Fprint(w, " -> ")
+	if a.Err != nil {
+		writeTraceError(w, a.Err)
+		return
+	}
+	a.Result.Write(w)
+}
+
+func writeTraceError(w io.Writer, err error) {
+	if t, ok := errors.Cause(err).(Throw); ok {
+		_, _ = fmt.Fprintf(w, "throw(%q)", t.Message)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "error: %v", err)
+}
+
+func (a *TraceStep) MarshalJSON() ([]byte, error) {
+	type step struct {
+		Kind       TraceKind    `json:"kind"`
+		Name       string       `json:"name,omitempty"`
+		FunctionID *int16       `json:"function,omitempty"`
+		Args       []string     `json:"args,omitempty"`
+		Condition  string       `json:"condition,omitempty"`
+		Branch     *bool        `json:"branch,omitempty"`
+		Result     string       `json:"result,omitempty"`
+		Error      string       `json:"error,omitempty"`
+		Steps      []*TraceStep `json:"steps,omitempty"`
+	}
+	out := step{Kind: a.Kind, Name: a.Name, Steps: a.Steps}
+	switch a.Kind {
+	case TraceCall:
+		if a.Name == "" {
+			id := a.FunctionID
+			out.FunctionID = &id
+		}
+		out.Args = make([]string, len(a.Args))
+		for i, arg := range a.Args {
+			out.Args[i] = exprString(arg)
+		}
+	case TraceIf:
+		out.Condition = exprString(a.Condition)
+		branch := a.Branch
+		out.Branch = &branch
+	}
+	if a.Err != nil {
+		out.Error = a.Err.Error()
+	} else if a.Result != nil {
+		out.Result = exprString(a.Result)
+	}
+	return json.Marshal(out)
+}
+
+func exprString(e Expr) string {
+	b := new(bytes.Buffer)
+	e.Write(b)
+	return b.String()
+}
+
+// Trace collects steps of script evaluation made in TracingScope.
+type Trace struct {
+	Steps []*TraceStep
+	// stack of steps being evaluated, the last one is the parent of new steps.
+	stack []*TraceStep
+}
+
+func NewTrace() *Trace {
+	return &Trace{}
+}
+
+func (a *Trace) begin(step *TraceStep) {
+	if l := len(a.stack); l > 0 {
+		parent := a.stack[l-1]
+		parent.Steps = append(parent.Steps, step)
+	} else {
+		a.Steps = append(a.Steps, step)
+	}
+	a.stack = append(a.stack, step)
+}
+
+func (a *Trace) end(result Expr, err error) {
+	l := len(a.stack)
+	step := a.stack[l-1]
+	step.Result, step.Err = result, err
+	a.stack = a.stack[:l-1]
+}
+
+// Write writes the trace in human readable form, one step per line, nested steps are indented.
+func (a *Trace) Write(w io.Writer) {
+	writeTraceSteps(w, a.Steps, 0)
+}
+
+func writeTraceSteps(w io.Writer, steps []*TraceStep, depth int) {
+	for _, step := range steps {
+		_, _ = fmt.Fprint(w, strings.Repeat("  ", depth))
+		step.Write(w)
+		_, _ = fmt.Fprintln(w)
+		writeTraceSteps(w, step.Steps, depth+1)
+	}
+}
+
+func (a *Trace) MarshalJSON() ([]byte, error) {
+	if a.Steps == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(a.Steps)
+}
+
+// TracingScope wraps scope and records let bindings, function calls and if branches
+// evaluated in it to the trace. Scopes cloned from TracingScope share its trace.
+type TracingScope struct {
+	scope Scope
+	trace *Trace
+}
+
+func NewTracingScope(s Scope, t *Trace) *TracingScope {
+	return &TracingScope{
+		scope: s,
+		trace: t,
+	}
+}
+
+func (a *TracingScope) Clone() Scope {
+	return NewTracingScope(a.scope.Clone(), a.trace)
+}
+
+func (a *TracingScope) AddValue(name string, expr Expr) {
+	// Only let bindings are traced, function arguments and predefined values are already evaluated.
+	if v, ok := expr.(*lazyValue); ok {
+		expr = &tracedValue{name: name, value: v, trace: a.trace}
+	}
+	a.scope.AddValue(name, expr)
+}
+
+func (a *TracingScope) AddFunction(name string, f Callable) {
+	a.scope.AddFunction(name, f)
+}
+
+func (a *TracingScope) FuncByShort(id int16) (Callable, bool) {
+	f, ok := a.scope.FuncByShort(id)
+	if !ok {
+		return nil, false
+	}
+	return a.traced(f, func() *TraceStep {
+		return &TraceStep{Kind: TraceCall, FunctionID: id}
+	}), true
+}
+
+func (a *TracingScope) FuncByName(name string) (Callable, bool) {
+	f, ok := a.scope.FuncByName(name)
+	if !ok {
+		return nil, false
+	}
+	return a.traced(f, func() *TraceStep {
+		return &TraceStep{Kind: TraceCall, Name: name}
+	}), true
+}
+
+// traced evaluates arguments before the call, so that their steps are recorded before the call result.
+func (a *TracingScope) traced(f Callable, newStep func() *TraceStep) Callable {
+	return func(s Scope, e Exprs) (Expr, error) {
+		step := newStep()
+		a.trace.begin(step)
+		args, err := e.EvaluateAll(s)
+		if err != nil {
+			step.Args = e
+			a.trace.end(nil, err)
+			return nil, err
+		}
+		step.Args = args
+		rs, err := f(s, args)
+		a.trace.end(rs, err)
+		return rs, err
+	}
+}
+
+func (a *TracingScope) Value(name string) (Expr, bool) {
+	return a.scope.Value(name)
+}
+
+func (a *TracingScope) State() mockstate.MockState {
+	return a.scope.State()
+}
+
+func (a *TracingScope) Scheme() byte {
+	return a.scope.Scheme()
+}
+
+func (a *TracingScope) EvaluateIf(e *IfExpr) (Expr, error) {
+	step := &TraceStep{Kind: TraceIf, Condition: e.Condition}
+	a.trace.begin(step)
+	rs, err := e.evaluate(a, step)
+	a.trace.end(rs, err)
+	return rs, err
+}
+
+// tracedValue records evaluation of let binding, which happens on the first reference.
+type tracedValue struct {
+	name  string
+	value *lazyValue
+	trace *Trace
+}
+
+func (a *tracedValue) Write(w io.Writer) {
+	a.value.Write(w)
+}
+
+func (a *tracedValue) Evaluate(s Scope) (Expr, error) {
+	if a.value.evaluated {
+		return a.value.Evaluate(s)
+	}
+	a.trace.begin(&TraceStep{Kind: TraceLet, Name: a.name})
+	rs, err := a.value.Evaluate(s)
+	a.trace.end(rs, err)
+	return rs, err
+}
+
+func (a *tracedValue) Eq(other Expr) (bool, error) {
+	return false, errors.Errorf("trying to compare %T with %T", a, other)
+}
+
+func (a *tracedValue) InstanceOf() string {
+	return a.value.InstanceOf()
+}
@@ -0,0 +1,55 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+func TestTracingScope(t *testing.T) {
+	// let x = 1 + 2; if (x >= limit) then true else throw("small")
+	script := NewBlockV2(
+		NewLet("x", NewFuncCall(NewNativeFunction(100, 2, Params(NewLong(1), NewLong(2))))),
+		NewIf(
+			NewFuncCall(NewNativeFunction(103, 2, Params(&RefExpr{Name: "x"}, &RefExpr{Name: "limit"}))),
+			NewBoolean(true),
+			NewFuncCall(NewNativeFunction(2, 1, Params(NewString("small")))),
+		),
+	)
+	funcs, err := NewFuncScope(3)
+	require.NoError(t, err)
+
+	trace := NewTrace()
+	s := NewTracingScope(NewScope(proto.MainNetScheme, nil, funcs, map[string]Expr{"limit": NewLong(5)}), trace)
+	_, err = script.Evaluate(s)
+	require.Error(t, err)
+	assert.Equal(t, Throw{Message: "small"}, errors.Cause(err))
+
+	text := new(bytes.Buffer)
+	trace.Write(text)
+	assert.Equal(t, `if ( x >= limit ) -> false -> throw("small")
+  3 >= 5 -> false
+    let x -> 3
+      FUNCTION_100(1, 2) -> 3
+  throw("small") -> throw("small")
+`, text.String())
+
+	b, err := json.Marshal(trace)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"kind": "if", "condition": "x >= limit", "branch": false, "error": "small",
+		"steps": [
+			{"kind": "call", "function": 103, "args": ["3", "5"], "result": "false", "steps": [
+				{"kind": "let", "name": "x", "result": "3", "steps": [
+					{"kind": "call", "function": 100, "args": ["1", "2"], "result": "3"}
+				]}
+			]},
+			{"kind": "call", "function": 2, "args": ["\"small\""], "error": "small"}
+		]
+	}]`, string(b))
+}
@@ -2,9 +2,16 @@ package ast
 
 import (
 	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 )
 
+// bodyMarshaler is implemented by transactions and orders, their body bytes are available to scripts.
+type bodyMarshaler interface {
+	BodyMarshalBinary() ([]byte, error)
+}
+
+// NewVariablesFromTransaction creates fields of RIDE transaction object from given transaction.
 func NewVariablesFromTransaction(scheme byte, t proto.Transaction) (map[string]Expr, error) {
 
 	funcName := "NewVariablesFromTransaction"
@@ -15,69 +22,324 @@ func NewVariablesFromTransaction(scheme byte, t proto.Transaction) (map[string]E
 		return nil, errors.Wrap(err, funcName)
 	}
 	out["id"] = NewBytes(tID)
+	out["fee"] = NewLong(int64(t.GetFee()))
+	out["timestamp"] = NewLong(int64(t.GetTimestamp()))
+	out["version"] = NewLong(int64(t.GetTypeVersion().Version))
 
+	var (
+		senderPK crypto.PublicKey
+		proofs   Exprs
+	)
 	switch tx := t.(type) {
-	case *proto.Payment:
-		out["id"] = NewBytes(tx.ID.Bytes())
-		return out, nil
-	case *proto.TransferV1:
-		addr, err := proto.NewAddressFromPublicKey(scheme, tx.SenderPK)
-		if err != nil {
-			return nil, errors.Wrap(err, funcName)
-		}
-		out["sender"] = NewAddressFromProtoAddress(addr)
+	case *proto.Genesis:
+		out["recipient"] = NewAddressFromProtoAddress(tx.Recipient)
 		out["amount"] = NewLong(int64(tx.Amount))
-		out["timestamp"] = NewLong(int64(tx.Timestamp))
-		bts, err := tx.MarshalBinary()
-		if err != nil {
-			return nil, errors.Wrap(err, funcName)
-		}
-		out["bodyBytes"] = NewBytes(bts)
-		out[InstanceFieldName] = NewString("TransferTransaction")
+		out[InstanceFieldName] = NewString("GenesisTransaction")
+		// Genesis transaction has no sender.
 		return out, nil
+	case *proto.Payment:
+		out["recipient"] = NewAddressFromProtoAddress(tx.Recipient)
+		out["amount"] = NewLong(int64(tx.Amount))
+		out[InstanceFieldName] = NewString("PaymentTransaction")
+		senderPK, proofs = tx.SenderPK, signatureProofs(tx.Signature)
+	case *proto.IssueV1:
+		addIssueFields(out, tx.Issue, nil)
+		senderPK, proofs = tx.SenderPK, signatureProofs(tx.Signature)
+	case *proto.IssueV2:
+		addIssueFields(out, tx.Issue, tx.Script)
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.TransferV1:
+		addTransferFields(out, tx.Transfer)
+		senderPK, proofs = tx.SenderPK, signatureProofs(tx.Signature)
 	case *proto.TransferV2:
-		addr, err := proto.NewAddressFromPublicKey(scheme, tx.SenderPK)
+		addTransferFields(out, tx.Transfer)
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.ReissueV1:
+		addReissueFields(out, tx.Reissue)
+		senderPK, proofs = tx.SenderPK, signatureProofs(tx.Signature)
+	case *proto.ReissueV2:
+		addReissueFields(out, tx.Reissue)
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.BurnV1:
+		addBurnFields(out, tx.Burn)
+		senderPK, proofs = tx.SenderPK, signatureProofs(tx.Signature)
+	case *proto.BurnV2:
+		addBurnFields(out, tx.Burn)
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.ExchangeV1:
+		err := addExchangeFields(out, scheme, &tx.BuyOrder, &tx.SellOrder, tx)
 		if err != nil {
 			return nil, errors.Wrap(err, funcName)
 		}
-		out["sender"] = NewAddressFromProtoAddress(addr)
-		out["amount"] = NewLong(int64(tx.Amount))
-		out["timestamp"] = NewLong(int64(tx.Timestamp))
-		bts, err := tx.BodyMarshalBinary()
+		senderPK, proofs = tx.SenderPK, signatureProofs(tx.Signature)
+	case *proto.ExchangeV2:
+		err := addExchangeFields(out, scheme, tx.BuyOrder, tx.SellOrder, tx)
 		if err != nil {
 			return nil, errors.Wrap(err, funcName)
 		}
-		out["bodyBytes"] = NewBytes(bts)
-		out[InstanceFieldName] = NewString("TransferTransaction")
-
-		proofs := Exprs{}
-		for _, row := range tx.Proofs.Proofs {
-			proofs = append(proofs, NewBytes(row.Bytes()))
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.LeaseV1:
+		addLeaseFields(out, tx.Lease)
+		senderPK, proofs = tx.SenderPK, signatureProofs(tx.Signature)
+	case *proto.LeaseV2:
+		addLeaseFields(out, tx.Lease)
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.LeaseCancelV1:
+		addLeaseCancelFields(out, tx.LeaseCancel)
+		senderPK, proofs = tx.SenderPK, signatureProofs(tx.Signature)
+	case *proto.LeaseCancelV2:
+		addLeaseCancelFields(out, tx.LeaseCancel)
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.CreateAliasV1:
+		addCreateAliasFields(out, tx.CreateAlias)
+		senderPK, proofs = tx.SenderPK, signatureProofs(tx.Signature)
+	case *proto.CreateAliasV2:
+		addCreateAliasFields(out, tx.CreateAlias)
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.MassTransferV1:
+		out["assetId"] = optionalAsset(tx.Asset)
+		transfers := make(Exprs, len(tx.Transfers))
+		total := uint64(0)
+		for i, entry := range tx.Transfers {
+			transfers[i] = NewObject(map[string]Expr{
+				"recipient":       recipient(entry.Recipient),
+				"amount":          NewLong(int64(entry.Amount)),
+				InstanceFieldName: NewString("Transfer"),
+			})
+			total += entry.Amount
 		}
-		out["proofs"] = proofs
-		return out, nil
+		out["transfers"] = transfers
+		out["transferCount"] = NewLong(int64(len(tx.Transfers)))
+		out["totalAmount"] = NewLong(int64(total))
+		out["attachment"] = NewBytes([]byte(tx.Attachment))
+		out[InstanceFieldName] = NewString("MassTransferTransaction")
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
 	case *proto.DataV1:
-		addr, err := proto.NewAddressFromPublicKey(scheme, tx.SenderPK)
+		out["data"] = NewDataEntryList(tx.Entries)
+		out[InstanceFieldName] = NewString("DataTransaction")
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.SetScriptV1:
+		out["script"] = optionalScript(tx.Script)
+		out[InstanceFieldName] = NewString("SetScriptTransaction")
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.SponsorshipV1:
+		out["assetId"] = NewBytes(tx.AssetID.Bytes())
+		// Zero fee means cancellation of sponsorship.
+		if tx.MinAssetFee == 0 {
+			out["minSponsoredAssetFee"] = NewUnit()
+		} else {
+			out["minSponsoredAssetFee"] = NewLong(int64(tx.MinAssetFee))
+		}
+		out[InstanceFieldName] = NewString("SponsorFeeTransaction")
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.SetAssetScriptV1:
+		out["assetId"] = NewBytes(tx.AssetID.Bytes())
+		out["script"] = optionalScript(tx.Script)
+		out[InstanceFieldName] = NewString("SetAssetScriptTransaction")
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
+	case *proto.InvokeScriptV1:
+		out["dApp"] = recipient(tx.ScriptRecipient)
+		payment, err := attachedPayment(tx.Payments)
 		if err != nil {
 			return nil, errors.Wrap(err, funcName)
 		}
-		out["sender"] = NewAddressFromProtoAddress(addr)
-		out["timestamp"] = NewLong(int64(tx.Timestamp))
-		bts, err := tx.BodyMarshalBinary()
+		out["payment"] = payment
+		out["feeAssetId"] = optionalAsset(tx.FeeAsset)
+		name := tx.FunctionCall.Name
+		if tx.FunctionCall.Default {
+			name = "default"
+		}
+		out["function"] = NewString(name)
+		args, err := NewArguments(tx.FunctionCall.Arguments)
 		if err != nil {
 			return nil, errors.Wrap(err, funcName)
 		}
-		out["bodyBytes"] = NewBytes(bts)
-		proofs := Exprs{}
-		for _, row := range tx.Proofs.Proofs {
-			proofs = append(proofs, NewBytes(row.Bytes()))
-		}
-		out["proofs"] = proofs
-		out["data"] = NewDataEntryList(tx.Entries)
-		out[InstanceFieldName] = NewString("DataTransaction")
-		return out, nil
+		out["args"] = args
+		out[InstanceFieldName] = NewString("InvokeScriptTransaction")
+		senderPK, proofs = tx.SenderPK, proofsList(tx.Proofs)
 	default:
 		return nil, errors.Errorf("NewVariablesFromTransaction not implemented for %T", tx)
 	}
 
+	body, ok := t.(bodyMarshaler)
+	if !ok {
+		return nil, errors.Errorf("%s: body bytes of %T are not available", funcName, t)
+	}
+	err = addProvenFields(out, scheme, senderPK, body, proofs)
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	return out, nil
+}
+
+// NewVariablesFromOrder creates fields of RIDE order object from given order.
+func NewVariablesFromOrder(scheme byte, o proto.Order) (map[string]Expr, error) {
+
+	funcName := "NewVariablesFromOrder"
+
+	body, err := proto.OrderToOrderBody(o)
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	id, err := o.GetID()
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	out := make(map[string]Expr)
+	out["id"] = NewBytes(id)
+	out["matcherPublicKey"] = NewBytes(body.MatcherPK.Bytes())
+	out["assetPair"] = NewObject(map[string]Expr{
+		"amountAsset":     optionalAsset(body.AssetPair.AmountAsset),
+		"priceAsset":      optionalAsset(body.AssetPair.PriceAsset),
+		InstanceFieldName: NewString("AssetPair"),
+	})
+	orderType := "Buy"
+	if body.OrderType == proto.Sell {
+		orderType = "Sell"
+	}
+	out["orderType"] = NewObject(map[string]Expr{InstanceFieldName: NewString(orderType)})
+	out["price"] = NewLong(int64(body.Price))
+	out["amount"] = NewLong(int64(body.Amount))
+	out["timestamp"] = NewLong(int64(body.Timestamp))
+	out["expiration"] = NewLong(int64(body.Expiration))
+	out["matcherFee"] = NewLong(int64(body.MatcherFee))
+	out[InstanceFieldName] = NewString("Order")
+
+	var proofs Exprs
+	switch order := o.(type) {
+	case *proto.OrderV1:
+		out["matcherFeeAssetId"] = NewUnit()
+		proofs = signatureProofs(order.Signature)
+	case *proto.OrderV2:
+		out["matcherFeeAssetId"] = NewUnit()
+		proofs = proofsList(order.Proofs)
+	case *proto.OrderV3:
+		out["matcherFeeAssetId"] = optionalAsset(order.MatcherFeeAsset)
+		proofs = proofsList(order.Proofs)
+	default:
+		return nil, errors.Errorf("%s: unsupported order type %T", funcName, o)
+	}
+	err = addProvenFields(out, scheme, body.SenderPK, o.(bodyMarshaler), proofs)
+	if err != nil {
+		return nil, errors.Wrap(err, funcName)
+	}
+	return out, nil
+}
+
+// addProvenFields adds sender, body bytes and proofs of signed transaction or order.
+func addProvenFields(out map[string]Expr, scheme byte, senderPK crypto.PublicKey, body bodyMarshaler, proofs Exprs) error {
+	addr, err := proto.NewAddressFromPublicKey(scheme, senderPK)
+	if err != nil {
+		return err
+	}
+	bts, err := body.BodyMarshalBinary()
+	if err != nil {
+		return err
+	}
+	out["sender"] = NewAddressFromProtoAddress(addr)
+	out["senderPublicKey"] = NewBytes(senderPK.Bytes())
+	out["bodyBytes"] = NewBytes(bts)
+	out["proofs"] = proofs
+	return nil
+}
+
+func addIssueFields(out map[string]Expr, i proto.Issue, script proto.Script) {
+	out["quantity"] = NewLong(int64(i.Quantity))
+	out["name"] = NewBytes([]byte(i.Name))
+	out["description"] = NewBytes([]byte(i.Description))
+	out["reissuable"] = NewBoolean(i.Reissuable)
+	out["decimals"] = NewLong(int64(i.Decimals))
+	out["script"] = optionalScript(script)
+	out[InstanceFieldName] = NewString("IssueTransaction")
+}
+
+func addTransferFields(out map[string]Expr, tr proto.Transfer) {
+	out["assetId"] = optionalAsset(tr.AmountAsset)
+	out["feeAssetId"] = optionalAsset(tr.FeeAsset)
+	out["amount"] = NewLong(int64(tr.Amount))
+	out["recipient"] = recipient(tr.Recipient)
+	out["attachment"] = NewBytes([]byte(tr.Attachment))
+	out[InstanceFieldName] = NewString("TransferTransaction")
+}
+
+func addReissueFields(out map[string]Expr, r proto.Reissue) {
+	out["assetId"] = NewBytes(r.AssetID.Bytes())
+	out["quantity"] = NewLong(int64(r.Quantity))
+	out["reissuable"] = NewBoolean(r.Reissuable)
+	out[InstanceFieldName] = NewString("ReissueTransaction")
+}
+
+func addBurnFields(out map[string]Expr, b proto.Burn) {
+	out["assetId"] = NewBytes(b.AssetID.Bytes())
+	out["quantity"] = NewLong(int64(b.Amount))
+	out[InstanceFieldName] = NewString("BurnTransaction")
+}
+
+func addExchangeFields(out map[string]Expr, scheme byte, buy, sell proto.Order, tx proto.Exchange) error {
+	buyOrder, err := NewVariablesFromOrder(scheme, buy)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert buy order")
+	}
+	sellOrder, err := NewVariablesFromOrder(scheme, sell)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert sell order")
+	}
+	out["buyOrder"] = NewObject(buyOrder)
+	out["sellOrder"] = NewObject(sellOrder)
+	out["price"] = NewLong(int64(tx.GetPrice()))
+	out["amount"] = NewLong(int64(tx.GetAmount()))
+	out["buyMatcherFee"] = NewLong(int64(tx.GetBuyMatcherFee()))
+	out["sellMatcherFee"] = NewLong(int64(tx.GetSellMatcherFee()))
+	out[InstanceFieldName] = NewString("ExchangeTransaction")
+	return nil
+}
+
+func addLeaseFields(out map[string]Expr, l proto.Lease) {
+	out["amount"] = NewLong(int64(l.Amount))
+	out["recipient"] = recipient(l.Recipient)
+	out[InstanceFieldName] = NewString("LeaseTransaction")
+}
+
+func addLeaseCancelFields(out map[string]Expr, lc proto.LeaseCancel) {
+	out["leaseId"] = NewBytes(lc.LeaseID.Bytes())
+	out[InstanceFieldName] = NewString("LeaseCancelTransaction")
+}
+
+func addCreateAliasFields(out map[string]Expr, ca proto.CreateAlias) {
+	out["alias"] = NewString(ca.Alias.Alias)
+	out[InstanceFieldName] = NewString("CreateAliasTransaction")
+}
+
+// recipient converts recipient of transaction to Address or Alias.
+func recipient(r proto.Recipient) Expr {
+	if r.Address != nil {
+		return NewAddressFromProtoAddress(*r.Address)
+	}
+	return NewAliasFromProtoAlias(*r.Alias)
+}
+
+func optionalScript(s proto.Script) Expr {
+	if len(s) == 0 {
+		return NewUnit()
+	}
+	return NewBytes(s)
+}
+
+// signatureProofs returns proofs of transaction or order signed with single signature.
+func signatureProofs(s *crypto.Signature) Exprs {
+	if s == nil {
+		return Exprs{}
+	}
+	return Exprs{NewBytes(s.Bytes())}
+}
+
+func proofsList(p *proto.ProofsV1) Exprs {
+	out := Exprs{}
+	if p == nil {
+		return out
+	}
+	for _, row := range p.Proofs {
+		out = append(out, NewBytes(row.Bytes()))
+	}
+	return out
 }
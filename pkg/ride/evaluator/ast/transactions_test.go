@@ -0,0 +1,152 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+func TestNewVariablesFromTransaction(t *testing.T) {
+	sk, pk := crypto.GenerateKeyPair([]byte("transactions test seed"))
+	sender, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, pk)
+	require.NoError(t, err)
+	asset, err := crypto.NewDigestFromBase58("BJ3Q8kNPByCWHwJ3RLn55UPzUDVgnh64EwYAU5iCj6z6")
+	require.NoError(t, err)
+	alias := proto.NewAlias(proto.MainNetScheme, "alias")
+	amountAsset, err := proto.NewOptionalAssetFromDigest(asset)
+	require.NoError(t, err)
+
+	tx := proto.NewUnsignedTransferV1(pk, *amountAsset, proto.OptionalAsset{}, 100, 10, 1, proto.NewRecipientFromAlias(*alias), "attachment")
+	require.NoError(t, tx.Sign(sk))
+	vars, err := NewVariablesFromTransaction(proto.MainNetScheme, tx)
+	require.NoError(t, err)
+	body, err := tx.BodyMarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, NewString("TransferTransaction"), vars[InstanceFieldName])
+	assert.Equal(t, NewBytes(tx.ID.Bytes()), vars["id"])
+	assert.Equal(t, NewLong(1), vars["version"])
+	assert.Equal(t, NewLong(1), vars["fee"])
+	assert.Equal(t, NewLong(100), vars["timestamp"])
+	assert.Equal(t, NewAddressFromProtoAddress(sender), vars["sender"])
+	assert.Equal(t, NewBytes(pk.Bytes()), vars["senderPublicKey"])
+	assert.Equal(t, NewBytes(body), vars["bodyBytes"])
+	assert.Equal(t, Exprs{NewBytes(tx.Signature.Bytes())}, vars["proofs"])
+	assert.Equal(t, NewBytes(asset.Bytes()), vars["assetId"])
+	assert.Equal(t, NewUnit(), vars["feeAssetId"])
+	assert.Equal(t, NewAliasFromProtoAlias(*alias), vars["recipient"])
+	assert.Equal(t, NewBytes([]byte("attachment")), vars["attachment"])
+
+	issue := proto.NewUnsignedIssueV2(proto.MainNetScheme, pk, "name", "description", 1000, 2, true, nil, 100, 1)
+	require.NoError(t, issue.Sign(sk))
+	vars, err = NewVariablesFromTransaction(proto.MainNetScheme, issue)
+	require.NoError(t, err)
+	assert.Equal(t, NewString("IssueTransaction"), vars[InstanceFieldName])
+	assert.Equal(t, NewLong(2), vars["version"])
+	assert.Equal(t, NewBytes([]byte("name")), vars["name"])
+	assert.Equal(t, NewLong(1000), vars["quantity"])
+	assert.Equal(t, NewLong(2), vars["decimals"])
+	assert.Equal(t, NewBoolean(true), vars["reissuable"])
+	assert.Equal(t, NewUnit(), vars["script"])
+	assert.Len(t, vars["proofs"], 1)
+
+	transfers := []proto.MassTransferEntry{
+		{Recipient: proto.NewRecipientFromAddress(sender), Amount: 10},
+		{Recipient: proto.NewRecipientFromAlias(*alias), Amount: 20},
+	}
+	massTransfer := proto.NewUnsignedMassTransferV1(pk, proto.OptionalAsset{}, transfers, 1, 100, "")
+	require.NoError(t, massTransfer.Sign(sk))
+	vars, err = NewVariablesFromTransaction(proto.MainNetScheme, massTransfer)
+	require.NoError(t, err)
+	assert.Equal(t, NewString("MassTransferTransaction"), vars[InstanceFieldName])
+	assert.Equal(t, NewLong(2), vars["transferCount"])
+	assert.Equal(t, NewLong(30), vars["totalAmount"])
+	list, ok := vars["transfers"].(Exprs)
+	require.True(t, ok)
+	require.Len(t, list, 2)
+	assert.Equal(t, "Transfer", list[1].InstanceOf())
+	amount, err := list[1].(*ObjectExpr).Get("amount")
+	require.NoError(t, err)
+	assert.Equal(t, NewLong(20), amount)
+
+	sponsorship := proto.NewUnsignedSponsorshipV1(pk, asset, 0, 1, 100)
+	require.NoError(t, sponsorship.Sign(sk))
+	vars, err = NewVariablesFromTransaction(proto.MainNetScheme, sponsorship)
+	require.NoError(t, err)
+	assert.Equal(t, NewString("SponsorFeeTransaction"), vars[InstanceFieldName])
+	assert.Equal(t, NewUnit(), vars["minSponsoredAssetFee"])
+
+	call := proto.FunctionCall{Name: "deposit", Arguments: proto.Arguments{&proto.IntegerArgument{Value: 5}}}
+	payments := proto.ScriptPayments{{Amount: 7, Asset: proto.OptionalAsset{}}}
+	invoke := proto.NewUnsignedInvokeScriptV1(proto.MainNetScheme, pk, proto.NewRecipientFromAddress(sender), call, payments, proto.OptionalAsset{}, 1, 100)
+	require.NoError(t, invoke.Sign(sk))
+	vars, err = NewVariablesFromTransaction(proto.MainNetScheme, invoke)
+	require.NoError(t, err)
+	assert.Equal(t, NewString("InvokeScriptTransaction"), vars[InstanceFieldName])
+	assert.Equal(t, NewAddressFromProtoAddress(sender), vars["dApp"])
+	assert.Equal(t, NewString("deposit"), vars["function"])
+	assert.Equal(t, Exprs{NewLong(5)}, vars["args"])
+	assert.Equal(t, "AttachedPayment", vars["payment"].InstanceOf())
+
+	genesis := proto.NewUnsignedGenesis(sender, 100, 100)
+	genesis.GenerateID()
+	vars, err = NewVariablesFromTransaction(proto.MainNetScheme, genesis)
+	require.NoError(t, err)
+	assert.Equal(t, NewString("GenesisTransaction"), vars[InstanceFieldName])
+	assert.Equal(t, NewAddressFromProtoAddress(sender), vars["recipient"])
+	assert.NotContains(t, vars, "sender")
+}
+
+func TestNewVariablesFromExchange(t *testing.T) {
+	sk, pk := crypto.GenerateKeyPair([]byte("transactions test seed"))
+	matcherSK, matcherPK := crypto.GenerateKeyPair([]byte("matcher seed"))
+	asset, err := crypto.NewDigestFromBase58("BJ3Q8kNPByCWHwJ3RLn55UPzUDVgnh64EwYAU5iCj6z6")
+	require.NoError(t, err)
+	amountAsset, err := proto.NewOptionalAssetFromDigest(asset)
+	require.NoError(t, err)
+
+	buy := proto.NewUnsignedOrderV3(pk, matcherPK, *amountAsset, proto.OptionalAsset{}, proto.Buy, 10, 100, 100, 200, 3, *amountAsset)
+	require.NoError(t, buy.Sign(sk))
+	sell := proto.NewUnsignedOrderV2(pk, matcherPK, *amountAsset, proto.OptionalAsset{}, proto.Sell, 10, 100, 100, 200, 3)
+	require.NoError(t, sell.Sign(sk))
+	tx := proto.NewUnsignedExchangeV2(buy, sell, 10, 100, 3, 3, 1, 100)
+	require.NoError(t, tx.Sign(matcherSK))
+
+	vars, err := NewVariablesFromTransaction(proto.MainNetScheme, tx)
+	require.NoError(t, err)
+	assert.Equal(t, NewString("ExchangeTransaction"), vars[InstanceFieldName])
+	assert.Equal(t, NewLong(10), vars["price"])
+	assert.Equal(t, NewLong(100), vars["amount"])
+	assert.Equal(t, NewBytes(matcherPK.Bytes()), vars["senderPublicKey"])
+
+	buyOrder, ok := vars["buyOrder"].(*ObjectExpr)
+	require.True(t, ok)
+	assert.Equal(t, "Order", buyOrder.InstanceOf())
+	orderType, err := buyOrder.Get("orderType")
+	require.NoError(t, err)
+	assert.Equal(t, "Buy", orderType.InstanceOf())
+	feeAsset, err := buyOrder.Get("matcherFeeAssetId")
+	require.NoError(t, err)
+	assert.Equal(t, NewBytes(asset.Bytes()), feeAsset)
+	body, err := buy.BodyMarshalBinary()
+	require.NoError(t, err)
+	bodyBytes, err := buyOrder.Get("bodyBytes")
+	require.NoError(t, err)
+	assert.Equal(t, NewBytes(body), bodyBytes)
+
+	sellOrder, ok := vars["sellOrder"].(*ObjectExpr)
+	require.True(t, ok)
+	orderType, err = sellOrder.Get("orderType")
+	require.NoError(t, err)
+	assert.Equal(t, "Sell", orderType.InstanceOf())
+	feeAsset, err = sellOrder.Get("matcherFeeAssetId")
+	require.NoError(t, err)
+	assert.Equal(t, NewUnit(), feeAsset)
+	pair, err := sellOrder.Get("assetPair")
+	require.NoError(t, err)
+	amountAssetExpr, err := pair.(*ObjectExpr).Get("amountAsset")
+	require.NoError(t, err)
+	assert.Equal(t, NewBytes(asset.Bytes()), amountAssetExpr)
+}
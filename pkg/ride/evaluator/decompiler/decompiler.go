@@ -0,0 +1,569 @@
+package decompiler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+)
+
+// Precedence of expressions, operands of lower precedence are enclosed in parentheses.
+const (
+	precLowest = iota // if and match
+	precOr
+	precAnd
+	precEquality
+	precComparison
+	precAdditive
+	precMultiplicative
+	precUnary
+	precPrimary
+)
+
+const (
+	indentation = "    "
+	// matchPrefix starts names of values bound to the subject of match expression by compiler.
+	matchPrefix = "$match"
+	// Longer byte vectors are written in base64, base58 is too slow to encode and hard to read for them.
+	maxBase58Bytes = 64
+)
+
+type operator struct {
+	symbol     string
+	precedence int
+}
+
+var binaryNatives = map[int16]operator{
+	0:   {"==", precEquality},
+	100: {"+", precAdditive},
+	101: {"-", precAdditive},
+	102: {">", precComparison},
+	103: {">=", precComparison},
+	104: {"*", precMultiplicative},
+	105: {"/", precMultiplicative},
+	106: {"%", precMultiplicative},
+	203: {"+", precAdditive},
+	300: {"+", precAdditive},
+}
+
+var nativeNames = map[int16]string{
+	1:    "_isInstanceOf",
+	2:    "throw",
+	107:  "fraction",
+	108:  "pow",
+	109:  "log",
+	200:  "size",
+	201:  "take",
+	202:  "drop",
+	303:  "take",
+	304:  "drop",
+	305:  "size",
+	400:  "size",
+	410:  "toBytes",
+	411:  "toBytes",
+	412:  "toBytes",
+	420:  "toString",
+	421:  "toString",
+	500:  "sigVerify",
+	501:  "keccak256",
+	502:  "blake2b256",
+	503:  "sha256",
+	504:  "rsaVerify",
+	600:  "toBase58String",
+	601:  "fromBase58String",
+	602:  "toBase64String",
+	603:  "fromBase64String",
+	604:  "toBase16String",
+	605:  "fromBase16String",
+	700:  "checkMerkleProof",
+	1000: "transactionById",
+	1001: "transactionHeightById",
+	1003: "assetBalance",
+	1004: "assetInfo",
+	1005: "blockInfoByHeight",
+	1006: "transferTransactionById",
+	1040: "getInteger",
+	1041: "getBoolean",
+	1042: "getBinary",
+	1043: "getString",
+	1050: "getInteger",
+	1051: "getBoolean",
+	1052: "getBinary",
+	1053: "getString",
+	1060: "addressFromRecipient",
+	1100: "cons",
+	1200: "toUtf8String",
+	1201: "toInt",
+	1202: "toInt",
+	1203: "indexOf",
+	1204: "indexOf",
+	1205: "split",
+	1206: "parseInt",
+}
+
+const (
+	nativeGetElement   = 401
+	nativeCons         = 1100
+	nativeIsInstanceOf = 1
+)
+
+// Decompile returns source code of expression script.
+func Decompile(s *ast.Script) string {
+	p := &printer{}
+	p.printf("{-# STDLIB_VERSION %d #-}\n{-# CONTENT_TYPE EXPRESSION #-}\n", s.Version)
+	p.statements(s.Verifier)
+	p.print("\n")
+	return p.String()
+}
+
+// DecompileContract returns source code of DApp: global declarations, callable functions sorted by name and verifier.
+func DecompileContract(c *ast.ContractScript) string {
+	p := &printer{}
+	p.printf("{-# STDLIB_VERSION %d #-}\n{-# CONTENT_TYPE DAPP #-}\n{-# SCRIPT_TYPE ACCOUNT #-}\n", c.Version)
+	for _, decl := range c.Declarations {
+		p.print("\n")
+		p.declaration(decl)
+		p.print("\n")
+	}
+	for _, name := range c.CallableNames() {
+		f := c.Callables[name]
+		p.printf("\n@Callable(%s)\n", f.ArgumentName)
+		p.function(f.Func, f.ArgTypes)
+		p.print("\n")
+	}
+	if c.HasVerifier() {
+		p.printf("\n@Verifier(%s)\n", c.Verifier.ArgumentName)
+		p.function(c.Verifier.Func, nil)
+		p.print("\n")
+	}
+	return p.String()
+}
+
+// DecompileExpr returns source code of expression.
+func DecompileExpr(e ast.Expr) string {
+	p := &printer{}
+	p.statements(e)
+	return p.String()
+}
+
+type printer struct {
+	strings.Builder
+	indent int
+}
+
+func (p *printer) print(s string) {
+	_, _ = p.WriteString(s)
+}
+
+func (p *printer) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(p, format, args...)
+}
+
+// newline starts new line with current indentation.
+func (p *printer) newline() {
+	p.print("\n")
+	p.print(strings.Repeat(indentation, p.indent))
+}
+
+// statements writes declarations of block one per line followed by its result expression.
+func (p *printer) statements(e ast.Expr) {
+	for {
+		if _, _, ok := matchCases(e); ok {
+			p.expr(e, precLowest)
+			return
+		}
+		decl, body, ok := block(e)
+		if !ok {
+			p.expr(e, precLowest)
+			return
+		}
+		p.declaration(decl)
+		p.newline()
+		e = body
+	}
+}
+
+func (p *printer) declaration(d ast.Declaration) {
+	switch v := d.(type) {
+	case *ast.LetExpr:
+		p.printf("let %s = ", v.Name)
+		p.expr(v.Value, precLowest)
+	case *ast.FuncDeclaration:
+		p.function(v, nil)
+	default:
+		d.Write(p)
+	}
+}
+
+// function writes function declaration, arguments are annotated with types if they are known.
+func (p *printer) function(f *ast.FuncDeclaration, types []ast.ArgType) {
+	args := make([]string, len(f.Args))
+	for i, arg := range f.Args {
+		args[i] = arg
+		if len(types) == len(f.Args) {
+			args[i] += ": " + types[i].String()
+		}
+	}
+	p.printf("func %s(%s) = ", f.Name, strings.Join(args, ", "))
+	p.expr(f.Body, precLowest)
+}
+
+// expr writes expression, enclosing it in parentheses if its precedence is lower than required.
+func (p *printer) expr(e ast.Expr, precedence int) {
+	if precedenceOf(e) < precedence {
+		p.print("(")
+		p.write(e)
+		p.print(")")
+		return
+	}
+	p.write(e)
+}
+
+func (p *printer) write(e ast.Expr) {
+	if subject, cases, ok := matchCases(e); ok {
+		p.match(subject, cases)
+		return
+	}
+	if _, _, ok := block(e); ok {
+		p.print("{")
+		p.indent++
+		p.newline()
+		p.statements(e)
+		p.indent--
+		p.newline()
+		p.print("}")
+		return
+	}
+	switch v := e.(type) {
+	case *ast.FuncCall:
+		p.write(v.Func)
+	case *ast.IfExpr:
+		p.ifExpr(v)
+	case *ast.NativeFunction:
+		p.native(v)
+	case *ast.UserFunction:
+		p.user(v)
+	case *ast.RefExpr:
+		p.print(v.Name)
+	case *ast.LongExpr:
+		p.printf("%d", v.Value)
+	case *ast.BooleanExpr:
+		p.printf("%t", v.Value)
+	case *ast.StringExpr:
+		p.print(quote(v.Value))
+	case *ast.BytesExpr:
+		if len(v.Value) > maxBase58Bytes {
+			p.printf("base64'%s'", base64.StdEncoding.EncodeToString(v.Value))
+			return
+		}
+		p.printf("base58'%s'", base58.Encode(v.Value))
+	case *ast.GetterExpr:
+		p.expr(v.Object, precPrimary)
+		p.printf(".%s", v.Key)
+	default:
+		e.Write(p)
+	}
+}
+
+func (p *printer) ifExpr(e *ast.IfExpr) {
+	if left, right, op, ok := logical(e); ok {
+		p.binary(op, left, right)
+		return
+	}
+	p.print("if (")
+	p.expr(e.Condition, precLowest)
+	p.print(")")
+	p.indent++
+	p.newline()
+	p.print("then ")
+	p.expr(e.True, precLowest)
+	p.newline()
+	p.print("else ")
+	p.expr(e.False, precLowest)
+	p.indent--
+}
+
+func (p *printer) native(e *ast.NativeFunction) {
+	if op, ok := binaryNatives[e.FunctionID]; ok && len(e.Argv) == 2 {
+		p.binary(op, e.Argv[0], e.Argv[1])
+		return
+	}
+	if items, ok := list(e); ok {
+		p.print("[")
+		p.arguments(items)
+		p.print("]")
+		return
+	}
+	if e.FunctionID == nativeGetElement && len(e.Argv) == 2 {
+		p.expr(e.Argv[0], precPrimary)
+		p.print("[")
+		p.expr(e.Argv[1], precLowest)
+		p.print("]")
+		return
+	}
+	name, ok := nativeNames[e.FunctionID]
+	if !ok {
+		name = fmt.Sprintf("FUNCTION_%d", e.FunctionID)
+	}
+	p.call(name, e.Argv)
+}
+
+func (p *printer) user(e *ast.UserFunction) {
+	switch {
+	case e.Name == "!=" && len(e.Argv) == 2:
+		p.binary(operator{"!=", precEquality}, e.Argv[0], e.Argv[1])
+	case (e.Name == "!" || e.Name == "-") && len(e.Argv) == 1:
+		p.print(e.Name)
+		p.expr(e.Argv[0], precPrimary)
+	default:
+		p.call(e.Name, e.Argv)
+	}
+}
+
+// binary writes left associative binary operation.
+func (p *printer) binary(op operator, left, right ast.Expr) {
+	p.expr(left, op.precedence)
+	p.printf(" %s ", op.symbol)
+	p.expr(right, op.precedence+1)
+}
+
+func (p *printer) call(name string, args ast.Exprs) {
+	p.print(name)
+	p.print("(")
+	p.arguments(args)
+	p.print(")")
+}
+
+func (p *printer) arguments(args ast.Exprs) {
+	for i, arg := range args {
+		if i > 0 {
+			p.print(", ")
+		}
+		p.expr(arg, precLowest)
+	}
+}
+
+func (p *printer) match(subject ast.Expr, cases []matchCase) {
+	p.print("match ")
+	p.expr(subject, precOr)
+	p.print(" {")
+	p.indent++
+	for _, c := range cases {
+		p.newline()
+		p.printf("case %s", c.binding)
+		if len(c.types) > 0 {
+			p.printf(": %s", strings.Join(c.types, "|"))
+		}
+		p.print(" =>")
+		p.indent++
+		if _, _, ok := block(c.body); ok {
+			p.newline()
+			p.statements(c.body)
+		} else {
+			p.print(" ")
+			p.expr(c.body, precLowest)
+		}
+		p.indent--
+	}
+	p.indent--
+	p.newline()
+	p.print("}")
+}
+
+func precedenceOf(e ast.Expr) int {
+	if _, _, ok := matchCases(e); ok {
+		return precLowest
+	}
+	switch v := e.(type) {
+	case *ast.FuncCall:
+		return precedenceOf(v.Func)
+	case *ast.IfExpr:
+		if _, _, op, ok := logical(v); ok {
+			return op.precedence
+		}
+		return precLowest
+	case *ast.NativeFunction:
+		if op, ok := binaryNatives[v.FunctionID]; ok && len(v.Argv) == 2 {
+			return op.precedence
+		}
+	case *ast.UserFunction:
+		switch {
+		case v.Name == "!=" && len(v.Argv) == 2:
+			return precEquality
+		case (v.Name == "!" || v.Name == "-") && len(v.Argv) == 1:
+			return precUnary
+		}
+	case *ast.LongExpr:
+		if v.Value < 0 {
+			return precUnary
+		}
+	}
+	return precPrimary
+}
+
+// unwrap returns function of call wrapper.
+func unwrap(e ast.Expr) ast.Expr {
+	if c, ok := e.(*ast.FuncCall); ok {
+		return unwrap(c.Func)
+	}
+	return e
+}
+
+// block returns declaration and body of block expression.
+func block(e ast.Expr) (ast.Declaration, ast.Expr, bool) {
+	switch v := e.(type) {
+	case *ast.Block:
+		return v.Let, v.Body, true
+	case *ast.BlockV2:
+		return v.Decl, v.Body, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// logical recognizes `a && b` compiled to `if (a) then b else false` and `a || b` compiled to `if (a) then true else b`.
+func logical(e *ast.IfExpr) (ast.Expr, ast.Expr, operator, bool) {
+	if b, ok := e.False.(*ast.BooleanExpr); ok && !b.Value {
+		return e.Condition, e.True, operator{"&&", precAnd}, true
+	}
+	if b, ok := e.True.(*ast.BooleanExpr); ok && b.Value {
+		return e.Condition, e.False, operator{"||", precOr}, true
+	}
+	return nil, nil, operator{}, false
+}
+
+// list recognizes list literal `[a, b]` compiled to `cons(a, cons(b, nil))`.
+func list(e *ast.NativeFunction) (ast.Exprs, bool) {
+	var items ast.Exprs
+	var tail ast.Expr = e
+	for {
+		switch v := unwrap(tail).(type) {
+		case *ast.NativeFunction:
+			if v.FunctionID != nativeCons || len(v.Argv) != 2 {
+				return nil, false
+			}
+			items = append(items, v.Argv[0])
+			tail = v.Argv[1]
+		case *ast.RefExpr:
+			return items, v.Name == "nil"
+		default:
+			return nil, false
+		}
+	}
+}
+
+type matchCase struct {
+	// binding is the name of matched value in case body, `_` if case doesn't bind it.
+	binding string
+	// types of matched value, empty for default case.
+	types []string
+	body  ast.Expr
+}
+
+// matchCases recognizes match expression which is compiled to the block declaring `$matchN` value
+// followed by if expressions checking its type with `_isInstanceOf` function.
+// Case binding the matched value starts with declaration of the name bound to `$matchN`.
+func matchCases(e ast.Expr) (ast.Expr, []matchCase, bool) {
+	decl, body, ok := block(e)
+	if !ok {
+		return nil, nil, false
+	}
+	let, ok := decl.(*ast.LetExpr)
+	if !ok || !strings.HasPrefix(let.Name, matchPrefix) {
+		return nil, nil, false
+	}
+	var cases []matchCase
+	for {
+		cond, ok := body.(*ast.IfExpr)
+		if !ok {
+			break
+		}
+		types, ok := typeCheck(cond.Condition, let.Name)
+		if !ok {
+			break
+		}
+		c := matchCase{binding: "_", types: types, body: cond.True}
+		if d, b, ok := block(cond.True); ok {
+			if l, ok := d.(*ast.LetExpr); ok {
+				if ref, ok := l.Value.(*ast.RefExpr); ok && ref.Name == let.Name {
+					c.binding, c.body = l.Name, b
+				}
+			}
+		}
+		cases = append(cases, c)
+		body = cond.False
+	}
+	if len(cases) == 0 {
+		return nil, nil, false
+	}
+	cases = append(cases, matchCase{binding: "_", body: body})
+	return let.Value, cases, true
+}
+
+// typeCheck returns types checked by `_isInstanceOf(name, "T")` calls, union types are checked by `||` of calls.
+func typeCheck(e ast.Expr, name string) ([]string, bool) {
+	switch v := unwrap(e).(type) {
+	case *ast.NativeFunction:
+		if v.FunctionID != nativeIsInstanceOf || len(v.Argv) != 2 {
+			return nil, false
+		}
+		ref, ok := v.Argv[0].(*ast.RefExpr)
+		if !ok || ref.Name != name {
+			return nil, false
+		}
+		t, ok := v.Argv[1].(*ast.StringExpr)
+		if !ok {
+			return nil, false
+		}
+		return []string{t.Value}, true
+	case *ast.IfExpr:
+		if b, ok := v.True.(*ast.BooleanExpr); !ok || !b.Value {
+			return nil, false
+		}
+		left, ok := typeCheck(v.Condition, name)
+		if !ok {
+			return nil, false
+		}
+		right, ok := typeCheck(v.False, name)
+		if !ok {
+			return nil, false
+		}
+		return append(left, right...), true
+	default:
+		return nil, false
+	}
+}
+
+// quote writes string literal with escape sequences supported by RIDE.
+func quote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		default:
+			if r < ' ' {
+				_, _ = fmt.Fprintf(&sb, `\u%04x`, r)
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
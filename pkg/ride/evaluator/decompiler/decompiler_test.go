@@ -0,0 +1,143 @@
+package decompiler
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/parser"
+)
+
+func native(id int16, args ...ast.Expr) ast.Expr {
+	return ast.NewFuncCall(ast.NewNativeFunction(id, len(args), args))
+}
+
+func user(name string, args ...ast.Expr) ast.Expr {
+	return ast.NewFuncCall(ast.NewUserFunction(name, len(args), args))
+}
+
+func ref(name string) ast.Expr {
+	return &ast.RefExpr{Name: name}
+}
+
+func TestDecompileOperators(t *testing.T) {
+	for _, test := range []struct {
+		expr   ast.Expr
+		source string
+	}{
+		{native(104, native(100, ref("a"), ast.NewLong(2)), ast.NewLong(3)), "(a + 2) * 3"},
+		{native(100, ref("a"), native(104, ast.NewLong(2), ast.NewLong(3))), "a + 2 * 3"},
+		{native(101, native(101, ref("a"), ref("b")), ref("c")), "a - b - c"},
+		{native(101, ref("a"), native(101, ref("b"), ref("c"))), "a - (b - c)"},
+		{native(101, ref("a"), ast.NewLong(-5)), "a - -5"},
+		{user("-", ast.NewLong(-5)), "-(-5)"},
+		{user("!", user("!=", ref("a"), ref("b"))), "!(a != b)"},
+		// a > 0 && b || c
+		{ast.NewIf(ast.NewIf(native(102, ref("a"), ast.NewLong(0)), ref("b"), ast.NewBoolean(false)), ast.NewBoolean(true), ref("c")), "a > 0 && b || c"},
+		// a && (b || c)
+		{ast.NewIf(ref("a"), ast.NewIf(ref("b"), ast.NewBoolean(true), ref("c")), ast.NewBoolean(false)), "a && (b || c)"},
+		{native(0, native(300, ast.NewString("a\"b"), ast.NewString("\n")), ast.NewString("")), `"a\"b" + "\n" == ""`},
+		{native(401, native(1205, ref("s"), ast.NewString(",")), ast.NewLong(0)), `split(s, ",")[0]`},
+		{native(1100, ast.NewLong(1), native(1100, ast.NewLong(2), ref("nil"))), "[1, 2]"},
+		{native(1100, ast.NewLong(1), ref("list")), "cons(1, list)"},
+		{ast.NewGetterExpr(user("extract", native(1000, ast.NewBytes([]byte{1, 2, 3}))), "id"), "extract(transactionById(base58'Ldp')).id"},
+		{native(500, ast.NewGetterExpr(ref("tx"), "bodyBytes"), ast.NewGetterExpr(ref("tx"), "proofs"), ref("pk")), "sigVerify(tx.bodyBytes, tx.proofs, pk)"},
+		{native(9999), "FUNCTION_9999()"},
+	} {
+		assert.Equal(t, test.source, DecompileExpr(test.expr))
+	}
+}
+
+func TestDecompileBlocks(t *testing.T) {
+	// let a = { let b = 1; b + 1 }; func f(x) = x * a; if (f(2) > 3) then a else throw("small")
+	e := ast.NewBlockV2(
+		ast.NewLet("a", ast.NewBlockV2(ast.NewLet("b", ast.NewLong(1)), native(100, ref("b"), ast.NewLong(1)))),
+		ast.NewBlockV2(
+			ast.NewFuncDeclaration("f", []string{"x"}, native(104, ref("x"), ref("a"))),
+			ast.NewIf(native(102, user("f", ast.NewLong(2)), ast.NewLong(3)), ref("a"), native(2, ast.NewString("small"))),
+		),
+	)
+	assert.Equal(t, `let a = {
+    let b = 1
+    b + 1
+}
+func f(x) = x * a
+if (f(2) > 3)
+    then a
+    else throw("small")`, DecompileExpr(e))
+}
+
+func TestDecompileMatch(t *testing.T) {
+	isInstanceOf := func(t string) ast.Expr {
+		return native(1, ref("$match0"), ast.NewString(t))
+	}
+	// match tx {
+	//   case t: TransferTransaction | MassTransferTransaction => let fee = t.fee; fee > 0
+	//   case _: DataTransaction => false
+	//   case _ => true
+	// }
+	e := &ast.Block{
+		Let: ast.NewLet("$match0", ref("tx")),
+		Body: ast.NewIf(
+			ast.NewIf(isInstanceOf("TransferTransaction"), ast.NewBoolean(true), isInstanceOf("MassTransferTransaction")),
+			&ast.Block{
+				Let:  ast.NewLet("t", ref("$match0")),
+				Body: &ast.Block{Let: ast.NewLet("fee", ast.NewGetterExpr(ref("t"), "fee")), Body: native(102, ref("fee"), ast.NewLong(0))},
+			},
+			ast.NewIf(isInstanceOf("DataTransaction"), ast.NewBoolean(false), ast.NewBoolean(true)),
+		),
+	}
+	assert.Equal(t, `match tx {
+    case t: TransferTransaction|MassTransferTransaction =>
+        let fee = t.fee
+        fee > 0
+    case _: DataTransaction => false
+    case _ => true
+}`, DecompileExpr(e))
+
+	// value of let which is not bound by match is left as is
+	e = &ast.Block{Let: ast.NewLet("match", ref("tx")), Body: native(1, ref("match"), ast.NewString("Unit"))}
+	assert.Equal(t, "let match = tx\n_isInstanceOf(match, \"Unit\")", DecompileExpr(e))
+}
+
+func TestDecompile(t *testing.T) {
+	// {-# STDLIB_VERSION 3 #-}
+	// if (wavesBalance(tx.sender) >= 100) then getInteger(tx.sender, "key") == 1 else throw("low balance")
+	script, err := base64.StdEncoding.DecodeString("AwMJAABnAAAAAgkBAAAADHdhdmVzQmFsYW5jZQAAAAEIBQAAAAJ0eAAAAAZzZW5kZXIAAAAAAAAAAGQJAAAAAAAAAgkABBoAAAACCAUAAAACdHgAAAAGc2VuZGVyAgAAAANrZXkAAAAAAAAAAAEJAAACAAAAAQIAAAALbG93IGJhbGFuY2Wpc+4L")
+	require.NoError(t, err)
+	s, err := parser.LoadScript(script)
+	require.NoError(t, err)
+	assert.Equal(t, `{-# STDLIB_VERSION 3 #-}
+{-# CONTENT_TYPE EXPRESSION #-}
+if (wavesBalance(tx.sender) >= 100)
+    then getInteger(tx.sender, "key") == 1
+    else throw("low balance")
+`, Decompile(s))
+}
+
+func TestDecompileContract(t *testing.T) {
+	// @Callable(i)
+	// func deposit(amount: Int, note: String) = WriteSet([DataEntry("note", note)])
+	//
+	// @Verifier(tx)
+	// func verify() = this == tx.sender
+	script, err := base64.StdEncoding.DecodeString("AAIDAAAAAAAAAAgIARIECgIBCAAAAAAAAAABAAAAAWkBAAAAB2RlcG9zaXQAAAACAAAABmFtb3VudAAAAARub3RlCQEAAAAIV3JpdGVTZXQAAAABCQAETAAAAAIJAQAAAAlEYXRhRW50cnkAAAACAgAAAARub3RlBQAAAARub3RlBQAAAANuaWwAAAABAAAAAnR4AQAAAAZ2ZXJpZnkAAAAACQAAAAAAAAIFAAAABHRoaXMIBQAAAAJ0eAAAAAZzZW5kZXLKYrfh")
+	require.NoError(t, err)
+	c, err := parser.LoadContract(script)
+	require.NoError(t, err)
+	c.Declarations = append(c.Declarations, ast.NewLet("limit", ast.NewLong(10)))
+	assert.Equal(t, `{-# STDLIB_VERSION 3 #-}
+{-# CONTENT_TYPE DAPP #-}
+{-# SCRIPT_TYPE ACCOUNT #-}
+
+let limit = 10
+
+@Callable(i)
+func deposit(amount: Int, note: String) = WriteSet([DataEntry("note", note)])
+
+@Verifier(tx)
+func verify() = this == tx.sender
+`, DecompileContract(c))
+}
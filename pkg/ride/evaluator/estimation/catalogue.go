@@ -0,0 +1,157 @@
+package estimation
+
+import "github.com/pkg/errors"
+
+// Catalogue holds costs of native and predefined user functions of a standard library version.
+type Catalogue struct {
+	natives map[int16]int64
+	users   map[string]int64
+}
+
+// NewCatalogue returns costs of functions available to scripts of the given version of standard library.
+func NewCatalogue(version int) (*Catalogue, error) {
+	switch version {
+	case 1, 2:
+		return newCatalogueV12(), nil
+	case 3:
+		return newCatalogueV3(), nil
+	default:
+		return nil, errors.Errorf("unsupported standard library version %d", version)
+	}
+}
+
+func newCatalogueV12() *Catalogue {
+	natives := map[int16]int64{
+		0: 1, // ==
+		1: 1, // _isInstanceOf
+		2: 1, // throw
+
+		100: 1, // +
+		101: 1, // -
+		102: 1, // >
+		103: 1, // >=
+		104: 1, // *
+		105: 1, // /
+		106: 1, // %
+		107: 1, // fraction
+
+		200: 1,  // size(ByteVector)
+		201: 1,  // take(ByteVector)
+		202: 1,  // drop(ByteVector)
+		203: 10, // ByteVector + ByteVector
+
+		300: 10, // String + String
+		303: 1,  // take(String)
+		304: 1,  // drop(String)
+		305: 1,  // size(String)
+
+		400: 2, // size(List)
+		401: 2, // getElement
+		410: 1, // toBytes(Int)
+		411: 1, // toBytes(String)
+		412: 1, // toBytes(Boolean)
+		420: 1, // toString(Int)
+		421: 1, // toString(Boolean)
+
+		500: 100, // sigVerify
+		501: 10,  // keccak256
+		502: 10,  // blake2b256
+		503: 10,  // sha256
+
+		600: 10, // toBase58String
+		601: 10, // fromBase58String
+		602: 10, // toBase64String
+		603: 10, // fromBase64String
+
+		1000: 100, // transactionById
+		1001: 100, // transactionHeightById
+		1003: 100, // assetBalance
+
+		1040: 10, // getInteger(List[DataEntry], String)
+		1041: 10, // getBoolean(List[DataEntry], String)
+		1042: 10, // getBinary(List[DataEntry], String)
+		1043: 10, // getString(List[DataEntry], String)
+
+		1050: 100, // getInteger(Address, String)
+		1051: 100, // getBoolean(Address, String)
+		1052: 100, // getBinary(Address, String)
+		1053: 100, // getString(Address, String)
+
+		1060: 100, // addressFromRecipient
+	}
+	users := map[string]int64{
+		"throw":          1,
+		"!=":             26,
+		"!":              11,
+		"-":              9,
+		"isDefined":      35,
+		"extract":        13,
+		"dropRightBytes": 19,
+		"takeRightBytes": 19,
+		"takeRight":      19,
+		"dropRight":      19,
+
+		"getInteger": 30,
+		"getBoolean": 30,
+		"getBinary":  30,
+		"getString":  30,
+
+		"addressFromPublicKey": 82,
+		"addressFromString":    124,
+		"wavesBalance":         109,
+
+		"Address": 1,
+		"Alias":   1,
+	}
+	return &Catalogue{natives: natives, users: users}
+}
+
+func newCatalogueV3() *Catalogue {
+	c := newCatalogueV12()
+
+	c.natives[108] = 100 // pow
+	c.natives[109] = 100 // log
+
+	c.natives[504] = 300 // rsaVerify
+	c.natives[604] = 10  // toBase16String
+	c.natives[605] = 10  // fromBase16String
+	c.natives[700] = 30  // checkMerkleProof
+
+	c.natives[1004] = 100 // assetInfo
+	c.natives[1005] = 100 // blockInfoByHeight
+	c.natives[1006] = 100 // transferTransactionById
+
+	c.natives[1100] = 2 // cons
+
+	c.natives[1200] = 20  // toUtf8String
+	c.natives[1201] = 10  // toInt(ByteVector)
+	c.natives[1202] = 10  // toInt(ByteVector, Int)
+	c.natives[1203] = 20  // indexOf(String, String)
+	c.natives[1204] = 20  // indexOf(String, String, Int)
+	c.natives[1205] = 100 // split
+	c.natives[1206] = 20  // parseInt
+
+	c.users["parseIntValue"] = 20
+	c.users["value"] = 13
+	c.users["addressFromStringValue"] = 124
+
+	c.users["DataEntry"] = 1
+	c.users["WriteSet"] = 1
+	c.users["ScriptTransfer"] = 1
+	c.users["TransferSet"] = 1
+	c.users["ScriptResult"] = 1
+
+	return c
+}
+
+// NativeCost returns cost of native function by its id.
+func (c *Catalogue) NativeCost(id int16) (int64, bool) {
+	cost, ok := c.natives[id]
+	return cost, ok
+}
+
+// UserCost returns cost of predefined user function by its name.
+func (c *Catalogue) UserCost(name string) (int64, bool) {
+	cost, ok := c.users[name]
+	return cost, ok
+}
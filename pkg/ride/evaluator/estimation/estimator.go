@@ -0,0 +1,249 @@
+package estimation
+
+import (
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+)
+
+// Costs of expressions which are not function calls, the same as in Scala estimator.
+const (
+	constCost    = 1
+	getterCost   = 2
+	letCost      = 5
+	refCost      = 2
+	ifCost       = 1
+	funcArgCost  = 5
+	funcDeclCost = 5
+)
+
+var roundingNames = []string{"DOWN", "UP", "HALFUP", "HALFDOWN", "HALFEVEN", "CEILING", "FLOOR"}
+
+// ContractComplexity holds complexities of DApp script.
+type ContractComplexity struct {
+	// Max is the maximal complexity of callable functions and verifier.
+	Max int64
+	// Functions holds complexities of callable functions and verifier by their names.
+	Functions map[string]int64
+}
+
+// symbol is a declared value: its expression and the flag that its cost has already been counted.
+type symbol struct {
+	expr      ast.Expr
+	evaluated bool
+}
+
+type symbols map[string]symbol
+
+func (s symbols) with(name string, sym symbol) symbols {
+	out := make(symbols, len(s)+1)
+	for k, v := range s {
+		out[k] = v
+	}
+	out[name] = sym
+	return out
+}
+
+// functions holds costs of user functions declared in script, they shadow predefined ones.
+type functions map[string]int64
+
+func (f functions) with(name string, cost int64) functions {
+	out := make(functions, len(f)+1)
+	for k, v := range f {
+		out[k] = v
+	}
+	out[name] = cost
+	return out
+}
+
+type estimator struct {
+	catalogue *Catalogue
+}
+
+func (e *estimator) funcCost(f ast.Expr, declared functions) (int64, ast.Exprs, error) {
+	switch f := f.(type) {
+	case *ast.NativeFunction:
+		cost, ok := e.catalogue.NativeCost(f.FunctionID)
+		if !ok {
+			return 0, nil, errors.Errorf("unknown native function %d", f.FunctionID)
+		}
+		return cost, f.Argv, nil
+	case *ast.UserFunction:
+		if cost, ok := declared[f.Name]; ok {
+			return cost, f.Argv, nil
+		}
+		cost, ok := e.catalogue.UserCost(f.Name)
+		if !ok {
+			return 0, nil, errors.Errorf("unknown user function '%s'", f.Name)
+		}
+		return cost, f.Argv, nil
+	default:
+		return 0, nil, errors.Errorf("unexpected function type %T", f)
+	}
+}
+
+func (e *estimator) declare(decl ast.Declaration, body ast.Expr, syms symbols, funcs functions) (int64, symbols, error) {
+	switch d := decl.(type) {
+	case *ast.LetExpr:
+		c, out, err := e.estimate(body, syms.with(d.Name, symbol{expr: d.Value}), funcs)
+		if err != nil {
+			return 0, nil, err
+		}
+		return c + letCost, out, nil
+	case *ast.FuncDeclaration:
+		// arguments are estimated as constants
+		argSyms := syms
+		for _, arg := range d.Args {
+			argSyms = argSyms.with(arg, symbol{expr: ast.NewBoolean(true)})
+		}
+		fc, _, err := e.estimate(d.Body, argSyms, funcs)
+		if err != nil {
+			return 0, nil, errors.Wrapf(err, "function %s", d.Name)
+		}
+		c, out, err := e.estimate(body, syms, funcs.with(d.Name, fc+int64(len(d.Args))*funcArgCost))
+		if err != nil {
+			return 0, nil, err
+		}
+		return c + funcDeclCost, out, nil
+	default:
+		return 0, nil, errors.Errorf("unexpected declaration %T", decl)
+	}
+}
+
+func (e *estimator) estimate(expr ast.Expr, syms symbols, funcs functions) (int64, symbols, error) {
+	switch expr := expr.(type) {
+	case *ast.LongExpr, *ast.BytesExpr, *ast.StringExpr, *ast.BooleanExpr:
+		return constCost, syms, nil
+	case *ast.GetterExpr:
+		c, out, err := e.estimate(expr.Object, syms, funcs)
+		if err != nil {
+			return 0, nil, err
+		}
+		return c + getterCost, out, nil
+	case *ast.Block:
+		return e.declare(expr.Let, expr.Body, syms, funcs)
+	case *ast.BlockV2:
+		return e.declare(expr.Decl, expr.Body, syms, funcs)
+	case *ast.RefExpr:
+		sym, ok := syms[expr.Name]
+		if !ok {
+			return 0, nil, errors.Errorf("undeclared variable '%s'", expr.Name)
+		}
+		if sym.evaluated {
+			return refCost, syms, nil
+		}
+		// the value is counted once, at the first reference
+		c, out, err := e.estimate(sym.expr, syms.with(expr.Name, symbol{expr: sym.expr, evaluated: true}), funcs)
+		if err != nil {
+			return 0, nil, err
+		}
+		return c + refCost, out, nil
+	case *ast.IfExpr:
+		cc, condSyms, err := e.estimate(expr.Condition, syms, funcs)
+		if err != nil {
+			return 0, nil, err
+		}
+		tc, trueSyms, err := e.estimate(expr.True, condSyms, funcs)
+		if err != nil {
+			return 0, nil, err
+		}
+		fc, falseSyms, err := e.estimate(expr.False, condSyms, funcs)
+		if err != nil {
+			return 0, nil, err
+		}
+		if tc > fc {
+			return cc + tc + ifCost, trueSyms, nil
+		}
+		return cc + fc + ifCost, falseSyms, nil
+	case *ast.FuncCall:
+		cost, args, err := e.funcCost(expr.Func, funcs)
+		if err != nil {
+			return 0, nil, err
+		}
+		out := syms
+		for _, arg := range args {
+			var c int64
+			c, out, err = e.estimate(arg, out, funcs)
+			if err != nil {
+				return 0, nil, err
+			}
+			cost += c
+		}
+		return cost, out, nil
+	default:
+		return 0, nil, errors.Errorf("unexpected expression %T", expr)
+	}
+}
+
+func predefinedSymbols(version int, dApp bool) symbols {
+	names := []string{"height", "unit"}
+	if !dApp {
+		names = append(names, "tx")
+	}
+	if version >= 3 {
+		names = append(names, "this", "lastBlock", "nil")
+		names = append(names, roundingNames...)
+	}
+	out := make(symbols, len(names))
+	for _, name := range names {
+		out[name] = symbol{evaluated: true}
+	}
+	return out
+}
+
+// EstimateScript returns complexity of expression script.
+func EstimateScript(script *ast.Script) (int64, error) {
+	catalogue, err := NewCatalogue(script.Version)
+	if err != nil {
+		return 0, err
+	}
+	e := &estimator{catalogue: catalogue}
+	c, _, err := e.estimate(script.Verifier, predefinedSymbols(script.Version, false), functions{})
+	if err != nil {
+		return 0, errors.Wrap(err, "EstimateScript")
+	}
+	return c, nil
+}
+
+// annotatedFunctionExpr builds the expression which is estimated for the annotated function:
+// global declarations, annotation argument and a call of the function with constant arguments.
+func annotatedFunctionExpr(declarations []ast.Declaration, f *ast.AnnotatedFunction) ast.Expr {
+	args := make(ast.Exprs, len(f.Func.Args))
+	for i := range args {
+		args[i] = ast.NewBoolean(true)
+	}
+	call := ast.NewFuncCall(ast.NewUserFunction(f.Func.Name, len(args), args))
+	var expr ast.Expr = ast.NewBlockV2(ast.NewLet(f.ArgumentName, ast.NewBoolean(true)), ast.NewBlockV2(f.Func, call))
+	for i := len(declarations) - 1; i >= 0; i-- {
+		expr = ast.NewBlockV2(declarations[i], expr)
+	}
+	return expr
+}
+
+// EstimateContract returns complexities of callable functions and verifier of DApp.
+func EstimateContract(contract *ast.ContractScript) (*ContractComplexity, error) {
+	catalogue, err := NewCatalogue(contract.Version)
+	if err != nil {
+		return nil, err
+	}
+	e := &estimator{catalogue: catalogue}
+	annotated := make([]*ast.AnnotatedFunction, 0, len(contract.Callables)+1)
+	for _, name := range contract.CallableNames() {
+		annotated = append(annotated, contract.Callables[name])
+	}
+	if contract.Verifier != nil {
+		annotated = append(annotated, contract.Verifier)
+	}
+	out := &ContractComplexity{Functions: make(map[string]int64, len(annotated))}
+	for _, f := range annotated {
+		expr := annotatedFunctionExpr(contract.Declarations, f)
+		c, _, err := e.estimate(expr, predefinedSymbols(contract.Version, true), functions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "EstimateContract: function %s", f.Func.Name)
+		}
+		out.Functions[f.Func.Name] = c
+		if c > out.Max {
+			out.Max = c
+		}
+	}
+	return out, nil
+}
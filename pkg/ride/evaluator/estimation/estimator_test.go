@@ -0,0 +1,100 @@
+package estimation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/parser"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/reader"
+)
+
+func native(id int16, args ...Expr) Expr {
+	return NewFuncCall(NewNativeFunction(id, len(args), args))
+}
+
+func user(name string, args ...Expr) Expr {
+	return NewFuncCall(NewUserFunction(name, len(args), args))
+}
+
+func ref(name string) Expr {
+	return &RefExpr{Name: name}
+}
+
+func TestEstimateScript(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		version    int
+		expr       Expr
+		complexity int64
+	}{
+		{"constant", 1, NewBoolean(true), 1},
+		{"height > 0", 1, native(102, ref("height"), NewLong(0)), 4},
+		{"getter", 1, NewGetterExpr(ref("tx"), "sender"), 4},
+		{"user function", 2, user("isDefined", ref("tx")), 37},
+		{"let is counted once", 1, &Block{Let: NewLet("x", native(100, NewLong(1), NewLong(2))), Body: native(100, ref("x"), ref("x"))}, 13},
+		{"unused let", 1, &Block{Let: NewLet("x", native(500, NewBytes(nil), NewBytes(nil), NewBytes(nil))), Body: NewBoolean(true)}, 6},
+		{"if takes max branch", 1, NewIf(NewBoolean(true), NewLong(1), native(100, NewLong(1), NewLong(2))), 5},
+		{"declared function", 3, NewBlockV2(NewFuncDeclaration("f", []string{"a"}, native(100, ref("a"), NewLong(1))), user("f", NewLong(2))), 16},
+		{"V3 constant", 3, ref("HALFUP"), 2},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := EstimateScript(&Script{Version: test.version, Verifier: test.expr})
+			require.NoError(t, err)
+			assert.Equal(t, test.complexity, c)
+		})
+	}
+}
+
+func TestEstimateScriptErrors(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		version int
+		expr    Expr
+	}{
+		{"undeclared variable", 1, ref("x")},
+		{"V3 variable in V1 script", 1, ref("this")},
+		{"unknown native function", 1, native(9999)},
+		{"function of newer version", 2, native(1100, NewLong(1), ref("unit"))},
+		{"unknown user function", 3, user("f")},
+		{"unsupported version", 4, NewBoolean(true)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := EstimateScript(&Script{Version: test.version, Verifier: test.expr})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEstimateContract(t *testing.T) {
+	// {-# STDLIB_VERSION 3 #-}
+	// {-# CONTENT_TYPE DAPP #-}
+	//
+	// @Callable(i)
+	// func deposit(amount: Int, note: String) = WriteSet([DataEntry("note", note)])
+	//
+	// @Verifier(tx)
+	// func verify() = this == tx.sender
+	r, err := reader.NewReaderFromBase64("AAIDAAAAAAAAAAgIARIECgIBCAAAAAAAAAABAAAAAWkBAAAAB2RlcG9zaXQAAAACAAAABmFtb3VudAAAAARub3RlCQEAAAAIV3JpdGVTZXQAAAABCQAETAAAAAIJAQAAAAlEYXRhRW50cnkAAAACAgAAAARub3RlBQAAAARub3RlBQAAAANuaWwAAAABAAAAAnR4AQAAAAZ2ZXJpZnkAAAAACQAAAAAAAAIFAAAABHRoaXMIBQAAAAJ0eAAAAAZzZW5kZXLKYrfh")
+	require.NoError(t, err)
+	contract, err := parser.BuildContract(r)
+	require.NoError(t, err)
+
+	c, err := EstimateContract(contract)
+	require.NoError(t, err)
+	assert.Equal(t, int64(32), c.Max)
+	assert.Equal(t, map[string]int64{"deposit": 32, "verify": 18}, c.Functions)
+
+	// Global declarations are counted for every function.
+	contract.Declarations = []Declaration{NewFuncDeclaration("g", nil, NewLong(1))}
+	c, err = EstimateContract(contract)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"deposit": 37, "verify": 23}, c.Functions)
+
+	// Transaction is not available in callable functions.
+	contract.Declarations = []Declaration{NewLet("x", ref("tx"))}
+	contract.Callables["deposit"].Func.Body = ref("x")
+	_, err = EstimateContract(contract)
+	assert.Error(t, err)
+}
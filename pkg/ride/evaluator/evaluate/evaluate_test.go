@@ -32,7 +32,7 @@ func newTransferTransaction() *proto.TransferV2 {
 	return tv2
 }
 
-func defaultScope() Scope {
+func defaultScope(version int) Scope {
 	predefObject := make(map[string]Expr)
 	t := newTransferTransaction()
 
@@ -51,7 +51,8 @@ func defaultScope() Scope {
 	}
 
 	am := mockstate.MockAccount{
-		Assets: map[string]uint64{"BXBUNddxTGTQc3G4qHYn5E67SBwMj18zLncUr871iuRD": 5},
+		Assets:      map[string]uint64{"BXBUNddxTGTQc3G4qHYn5E67SBwMj18zLncUr871iuRD": 5},
+		DataEntries: []proto.DataEntry{proto.IntegerDataEntry{Key: "integer", Value: 100500}},
 	}
 
 	s := mockstate.MockStateImpl{
@@ -60,7 +61,12 @@ func defaultScope() Scope {
 		Accounts: map[string]mockstate.Account{addr.String(): &am},
 	}
 
-	return NewScope(proto.MainNetScheme, s, NewFuncScope(), predefObject)
+	funcs, err := NewFuncScope(version)
+	if err != nil {
+		panic(err)
+	}
+
+	return NewScope(proto.MainNetScheme, s, funcs, predefObject)
 }
 
 var longScript = `match tx {
@@ -68,41 +74,81 @@ var longScript = `match tx {
   case _ => false
 }`
 
+// evalScripts are compiled scripts and results of their evaluation with the transaction of default scope.
+var evalScripts = []struct {
+	Name   string
+	Base64 string
+	Result bool
+}{
+	{`let x = 5; 6 > 4`, `AQQAAAABeAAAAAAAAAAABQkAAGYAAAACAAAAAAAAAAAGAAAAAAAAAAAEYSW6XA==`, true},
+	{`let x = 5; 6 > x`, `AQQAAAABeAAAAAAAAAAABQkAAGYAAAACAAAAAAAAAAAGBQAAAAF4Gh24hw==`, true},
+	{`let x = 5; 6 >= x`, `AQQAAAABeAAAAAAAAAAABQkAAGcAAAACAAAAAAAAAAAGBQAAAAF4jlxXHA==`, true},
+	{`true`, `AQa3b8tH`, true},
+	{`false`, `AQfeYll6`, false},
+	{`let x =  throw(); true`, `AQQAAAABeAkBAAAABXRocm93AAAAAAa7bgf4`, true},
+	{`let x =  throw();true || x`, `AQQAAAABeAkBAAAABXRocm93AAAAAAMGBgUAAAABeKRnLds=`, true},
+	{`tx.id == base58''`, `AQkAAAAAAAACCAUAAAACdHgAAAACaWQBAAAAAJBtD70=`, false},
+	{`tx.id == base58'CqjGMbrd5bFmLAv2mUSdphEJSgVWkWa6ZtcMkKmgH2ax'`, `AQkAAAAAAAACCAUAAAACdHgAAAACaWQBAAAAIK/sOVMfQLb6FHT+QbJpYq4m7jlQoC3GPCMpxfHPeT5F5CUKdw==`, true},
+	{`let x = tx.id == base58'a';true`, `AQQAAAABeAkAAAAAAAACCAUAAAACdHgAAAACaWQBAAAAASEGjR0kcA==`, true},
+	{`tx.proofs[0] == base58'5W7hjPpgmmhxevCt4A7y9F8oNJ4V9w2g8jhQgx2qGmBTNsP1p1MpQeKF3cvZULwJ7vQthZfSx2BhL6TWkHSVLzvq'`, `AQkAAAAAAAACCQABkQAAAAIIBQAAAAJ0eAAAAAZwcm9vZnMAAAAAAAAAAAABAAAAQOEtF8V5p+9JHReO90FmBf+yKZW1lLJGBsnkZww94TJ8bNcxWIKfohMXm4BsKKIBUTXLaS6Vcgyw1UTNN5iICQ719Fxf`, true},
+	{longScript, `AQQAAAAHJG1hdGNoMAUAAAACdHgDAwkAAAEAAAACBQAAAAckbWF0Y2gwAgAAABNFeGNoYW5nZVRyYW5zYWN0aW9uBgMJAAABAAAAAgUAAAAHJG1hdGNoMAIAAAAXTWFzc1RyYW5zZmVyVHJhbnNhY3Rpb24GCQAAAQAAAAIFAAAAByRtYXRjaDACAAAAE1RyYW5zZmVyVHJhbnNhY3Rpb24EAAAAAXQFAAAAByRtYXRjaDAGB6Ilvok=`, true},
+	{`match transactionById(tx.id) {case  t: Unit => true case _ => false }`, `AQQAAAAHJG1hdGNoMAkAA+gAAAABCAUAAAACdHgAAAACaWQDCQAAAQAAAAIFAAAAByRtYXRjaDACAAAABFVuaXQEAAAAAXQFAAAAByRtYXRjaDAGB1+iIek=`, true},
+	{`{-# STDLIB_VERSION 2 #-} let x = 5; 6 > x`, `AgQAAAABeAAAAAAAAAAABQkAAGYAAAACAAAAAAAAAAAGBQAAAAF4kZ5THw==`, true},
+	{`{-# STDLIB_VERSION 3 #-} func inc(x: Int) = x + 1; inc(1) == 2`, `AwoBAAAAA2luYwAAAAEAAAABeAkAAGQAAAACBQAAAAF4AAAAAAAAAAABCQAAAAAAAAIJAQAAAANpbmMAAAABAAAAAAAAAAABAAAAAAAAAAACWRRtpQ==`, true},
+	{`{-# STDLIB_VERSION 3 #-} func max(a: Int, b: Int) = if (a > b) then a else b; let m = max(3, 7); m == 7 && max(9, 2) == 9`, `AwoBAAAAA21heAAAAAIAAAABYQAAAAFiAwkAAGYAAAACBQAAAAFhBQAAAAFiBQAAAAFhBQAAAAFiCgAAAAABbQkBAAAAA21heAAAAAIAAAAAAAAAAAMAAAAAAAAAAAcDCQAAAAAAAAIFAAAAAW0AAAAAAAAAAAcJAAAAAAAAAgkBAAAAA21heAAAAAIAAAAAAAAAAAkAAAAAAAAAAAIAAAAAAAAAAAkHxyvN+A==`, true},
+	{`{-# STDLIB_VERSION 3 #-} let l = [1, 2, 3]; size(l) == 3 && l[1] == 2`, `AwoAAAAAAWwJAARMAAAAAgAAAAAAAAAAAQkABEwAAAACAAAAAAAAAAACCQAETAAAAAIAAAAAAAAAAAMFAAAAA25pbAMJAAAAAAAAAgkAAZAAAAABBQAAAAFsAAAAAAAAAAADCQAAAAAAAAIJAAGRAAAAAgUAAAABbAAAAAAAAAAAAQAAAAAAAAAAAgeXtg8F`, true},
+	{`{-# STDLIB_VERSION 3 #-} getInteger(tx.sender, "integer") == 100500`, `AwkAAAAAAAACCQAEGgAAAAIIBQAAAAJ0eAAAAAZzZW5kZXICAAAAB2ludGVnZXIAAAAAAAABiJTuswjf`, true},
+	{`{-# STDLIB_VERSION 3 #-} getInteger(tx.sender, "missing") == unit`, `AwkAAAAAAAACCQAEGgAAAAIIBQAAAAJ0eAAAAAZzZW5kZXICAAAAB21pc3NpbmcFAAAABHVuaXQ4snrS`, true},
+	{`{-# STDLIB_VERSION 3 #-} pow(12, 1, 3456, 3, 2, DOWN) == 187`, `AwkAAAAAAAACCQAAbAAAAAYAAAAAAAAAAAwAAAAAAAAAAAEAAAAAAAAADYAAAAAAAAAAAAMAAAAAAAAAAAIFAAAABERPV04AAAAAAAAAALu+Pw1s`, true},
+	{`{-# STDLIB_VERSION 3 #-} log(16, 0, 2, 0, 0, CEILING) == 4`, `AwkAAAAAAAACCQAAbQAAAAYAAAAAAAAAABAAAAAAAAAAAAAAAAAAAAAAAAIAAAAAAAAAAAAAAAAAAAAAAAAFAAAAB0NFSUxJTkcAAAAAAAAAAARh6Dy6`, true},
+	{`{-# STDLIB_VERSION 3 #-} parseIntValue("42") == 42 && !isDefined(parseInt("x"))`, `AwMJAAAAAAAAAgkBAAAADXBhcnNlSW50VmFsdWUAAAABAgAAAAI0MgAAAAAAAAAAKgkBAAAAASEAAAABCQEAAAAJaXNEZWZpbmVkAAAAAQkABLYAAAABAgAAAAF4B0jffqM=`, true},
+	{`{-# STDLIB_VERSION 3 #-} toUtf8String(toBytes("hello")) == "hello"`, `AwkAAAAAAAACCQAEsAAAAAEJAAGbAAAAAQIAAAAFaGVsbG8CAAAABWhlbGxv3nfzOQ==`, true},
+	{`{-# STDLIB_VERSION 3 #-} toBase16String(fromBase16String("68656c6c6f")) == "68656c6c6f" && indexOf("hello", "l") == 2 && size(split("a,b,c", ",")) == 3 && toInt(toBytes(42)) == 42`, `AwMJAAAAAAAAAgkAAlwAAAABCQACXQAAAAECAAAACjY4NjU2YzZjNmYCAAAACjY4NjU2YzZjNmYDCQAAAAAAAAIJAASzAAAAAgIAAAAFaGVsbG8CAAAAAWwAAAAAAAAAAAIDCQAAAAAAAAIJAAGQAAAAAQkABLUAAAACAgAAAAVhLGIsYwIAAAABLAAAAAAAAAAAAwkAAAAAAAACCQAEsQAAAAEJAAGaAAAAAQAAAAAAAAAAKgAAAAAAAAAAKgcHBxd9DeE=`, true},
+}
+
 func TestEval(t *testing.T) {
+	for _, c := range evalScripts {
 
-	conds := []struct {
-		Name   string
-		Base64 string
-		Result bool
-	}{
-		{`let x = 5; 6 > 4`, `AQQAAAABeAAAAAAAAAAABQkAAGYAAAACAAAAAAAAAAAGAAAAAAAAAAAEYSW6XA==`, true},
-		{`let x = 5; 6 > x`, `AQQAAAABeAAAAAAAAAAABQkAAGYAAAACAAAAAAAAAAAGBQAAAAF4Gh24hw==`, true},
-		{`let x = 5; 6 >= x`, `AQQAAAABeAAAAAAAAAAABQkAAGcAAAACAAAAAAAAAAAGBQAAAAF4jlxXHA==`, true},
-		{`true`, `AQa3b8tH`, true},
-		{`false`, `AQfeYll6`, false},
-		{`let x =  throw(); true`, `AQQAAAABeAkBAAAABXRocm93AAAAAAa7bgf4`, true},
-		{`let x =  throw();true || x`, `AQQAAAABeAkBAAAABXRocm93AAAAAAMGBgUAAAABeKRnLds=`, true},
-		{`tx.id == base58''`, `AQkAAAAAAAACCAUAAAACdHgAAAACaWQBAAAAAJBtD70=`, false},
-		{`tx.id == base58'CqjGMbrd5bFmLAv2mUSdphEJSgVWkWa6ZtcMkKmgH2ax'`, `AQkAAAAAAAACCAUAAAACdHgAAAACaWQBAAAAIK/sOVMfQLb6FHT+QbJpYq4m7jlQoC3GPCMpxfHPeT5F5CUKdw==`, true},
-		{`let x = tx.id == base58'a';true`, `AQQAAAABeAkAAAAAAAACCAUAAAACdHgAAAACaWQBAAAAASEGjR0kcA==`, true},
-		{`tx.proofs[0] == base58'5W7hjPpgmmhxevCt4A7y9F8oNJ4V9w2g8jhQgx2qGmBTNsP1p1MpQeKF3cvZULwJ7vQthZfSx2BhL6TWkHSVLzvq'`, `AQkAAAAAAAACCQABkQAAAAIIBQAAAAJ0eAAAAAZwcm9vZnMAAAAAAAAAAAABAAAAQOEtF8V5p+9JHReO90FmBf+yKZW1lLJGBsnkZww94TJ8bNcxWIKfohMXm4BsKKIBUTXLaS6Vcgyw1UTNN5iICQ719Fxf`, true},
-		{longScript, `AQQAAAAHJG1hdGNoMAUAAAACdHgDAwkAAAEAAAACBQAAAAckbWF0Y2gwAgAAABNFeGNoYW5nZVRyYW5zYWN0aW9uBgMJAAABAAAAAgUAAAAHJG1hdGNoMAIAAAAXTWFzc1RyYW5zZmVyVHJhbnNhY3Rpb24GCQAAAQAAAAIFAAAAByRtYXRjaDACAAAAE1RyYW5zZmVyVHJhbnNhY3Rpb24EAAAAAXQFAAAAByRtYXRjaDAGB6Ilvok=`, true},
-		{`match transactionById(tx.id) {case  t: Unit => true case _ => false }`, `AQQAAAAHJG1hdGNoMAkAA+gAAAABCAUAAAACdHgAAAACaWQDCQAAAQAAAAIFAAAAByRtYXRjaDACAAAABFVuaXQEAAAAAXQFAAAAByRtYXRjaDAGB1+iIek=`, true},
+		reader, err := reader.NewReaderFromBase64(c.Base64)
+		require.NoError(t, err)
+
+		script, err := BuildScript(reader)
+		require.NoError(t, err)
+
+		rs, err := Eval(script.Verifier, defaultScope(script.Version))
+		require.NoError(t, err)
+		assert.Equal(t, c.Result, rs, fmt.Sprintf("script: %s", c.Name))
 	}
 
-	for _, c := range conds {
+}
 
+func TestEvalCompiled(t *testing.T) {
+	for _, c := range evalScripts {
 		reader, err := reader.NewReaderFromBase64(c.Base64)
 		require.NoError(t, err)
 
-		exprs, err := BuildAst(reader)
+		script, err := BuildScript(reader)
+		require.NoError(t, err)
+		compiled, err := script.Compile()
 		require.NoError(t, err)
 
-		rs, err := Eval(exprs, defaultScope())
+		rs, err := Eval(compiled.Verifier, defaultScope(compiled.Version))
 		require.NoError(t, err)
 		assert.Equal(t, c.Result, rs, fmt.Sprintf("script: %s", c.Name))
 	}
+}
+
+func TestEvalFunctionOfNewerVersion(t *testing.T) {
+	// pow(12, 1, 3456, 3, 2, DOWN) == 187 compiled with version 1
+	reader, err := reader.NewReaderFromBase64(`AQkAAAAAAAACCQAAbAAAAAYAAAAAAAAAAAwAAAAAAAAAAAEAAAAAAAAADYAAAAAAAAAAAAMAAAAAAAAAAAIFAAAABERPV04AAAAAAAAAALtSLLfe`)
+	require.NoError(t, err)
+
+	script, err := BuildScript(reader)
+	require.NoError(t, err)
+	assert.Equal(t, 1, script.Version)
 
+	_, err = Eval(script.Verifier, defaultScope(script.Version))
+	assert.Error(t, err)
 }
 
 func BenchmarkEval(b *testing.B) {
@@ -121,7 +167,7 @@ f == e
 
 `
 
-	s := defaultScope()
+	s := defaultScope(1)
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -133,6 +179,37 @@ f == e
 	}
 }
 
+// BenchmarkEvalScripts compares evaluation of the original and compiled scripts.
+func BenchmarkEvalScripts(b *testing.B) {
+	scripts := make([]*Script, len(evalScripts))
+	compiled := make([]*Script, len(evalScripts))
+	for i, c := range evalScripts {
+		r, err := reader.NewReaderFromBase64(c.Base64)
+		require.NoError(b, err)
+		scripts[i], err = BuildScript(r)
+		require.NoError(b, err)
+		compiled[i], err = scripts[i].Compile()
+		require.NoError(b, err)
+	}
+	run := func(scripts []*Script) func(b *testing.B) {
+		return func(b *testing.B) {
+			scopes := make([]Scope, len(scripts))
+			for i, script := range scripts {
+				scopes[i] = defaultScope(script.Version)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j, script := range scripts {
+					_, _ = Eval(script.Verifier, scopes[j].Clone())
+				}
+			}
+		}
+	}
+	b.Run("tree", run(scripts))
+	b.Run("compiled", run(compiled))
+}
+
 func TestFunctions(t *testing.T) {
 	conds := []struct {
 		FuncCode int
@@ -196,7 +273,7 @@ func TestFunctions(t *testing.T) {
 		exprs, err := BuildAst(reader)
 		require.NoError(t, err)
 
-		rs, err := Eval(exprs, defaultScope())
+		rs, err := Eval(exprs, defaultScope(1))
 		assert.NoError(t, err)
 		assert.Equal(t, c.Result, rs, fmt.Sprintf("func name: %s, code: %d, script: %s", c.FuncName, c.FuncCode, c.Code))
 	}
@@ -231,7 +308,10 @@ func TestDataFunctions(t *testing.T) {
 	predefObject := make(map[string]Expr)
 	predefObject["tx"] = NewObject(vars)
 
-	scope := NewScope(proto.MainNetScheme, mockstate.MockStateImpl{}, NewFuncScope(), predefObject)
+	funcs, err := NewFuncScope(1)
+	require.NoError(t, err)
+
+	scope := NewScope(proto.MainNetScheme, mockstate.MockStateImpl{}, funcs, predefObject)
 
 	conds := []struct {
 		FuncCode int
@@ -0,0 +1,185 @@
+package parser
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	. "github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+	. "github.com/wavesplatform/gowaves/pkg/ride/evaluator/reader"
+)
+
+// DApps are supported since version 3 of standard library.
+const stdLibVersion3 = 3
+
+// Protobuf wire types used by script metadata.
+const (
+	wireVarint          = 0
+	wireFixed64         = 1
+	wireLengthDelimited = 2
+	wireFixed32         = 5
+)
+
+// IsContract tells if script bytes are DApp script, expression scripts start with the version of standard library
+// or with header of the same format as DApps.
+func IsContract(script []byte) bool {
+	return len(script) > 1 && script[0] == 0 && ContentType(script[1]) == ContentTypeDApp
+}
+
+// BuildContract reads DApp script: header, script metadata, global declarations, callable functions and verifier.
+func BuildContract(r *BytesReader) (*ContractScript, error) {
+	if r.Len() < 3 {
+		return nil, ErrUnexpectedEOF
+	}
+	// DApp header is zero byte followed by content type and version of standard library
+	if b := r.Next(); b != 0 {
+		return nil, errors.Errorf("BuildContract: invalid format, expected 0, found %d", b)
+	}
+	if contentType := ContentType(r.Next()); contentType != ContentTypeDApp {
+		return nil, errors.Errorf("BuildContract: unexpected content type %d", contentType)
+	}
+	version := r.Next()
+	if version != stdLibVersion3 {
+		return nil, errors.Errorf("BuildContract: unsupported version %d", version)
+	}
+
+	// version of metadata format is followed by metadata bytes
+	r.ReadInt()
+	argTypes, err := readMeta(r.ReadBytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "BuildContract: metadata")
+	}
+
+	declc := r.ReadInt()
+	declarations := make([]Declaration, declc)
+	for i := int32(0); i < declc; i++ {
+		decl, err := readDeclaration(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "BuildContract: declaration")
+		}
+		declarations[i] = decl
+	}
+
+	callablec := r.ReadInt()
+	callables := make(map[string]*AnnotatedFunction, callablec)
+	for i := int32(0); i < callablec; i++ {
+		f, err := readAnnotatedFunction(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "BuildContract: callable function")
+		}
+		// signatures in metadata follow the order of callable functions
+		if int(i) < len(argTypes) {
+			if len(argTypes[i]) != len(f.Func.Args) {
+				return nil, errors.Errorf("BuildContract: metadata of function %s has %d arguments, expected %d", f.Func.Name, len(argTypes[i]), len(f.Func.Args))
+			}
+			f.ArgTypes = argTypes[i]
+		}
+		callables[f.Func.Name] = f
+	}
+
+	var verifier *AnnotatedFunction
+	// verifier is optional, its presence is marked by 1
+	if r.Len()-r.Pos() >= 4 && r.ReadInt() != 0 {
+		verifier, err = readAnnotatedFunction(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "BuildContract: verifier function")
+		}
+	}
+
+	return &ContractScript{
+		Version:      int(version),
+		Declarations: declarations,
+		Callables:    callables,
+		Verifier:     verifier,
+	}, nil
+}
+
+func readAnnotatedFunction(r *BytesReader) (*AnnotatedFunction, error) {
+	argName := r.ReadString()
+	decl, err := readDeclaration(r)
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := decl.(*FuncDeclaration)
+	if !ok {
+		return nil, errors.Errorf("expected function declaration, found %T", decl)
+	}
+
+	return &AnnotatedFunction{
+		ArgumentName: argName,
+		Func:         f,
+	}, nil
+}
+
+// readMeta decodes argument types of callable functions from protobuf message
+// `DAppMeta { int32 version = 1; repeated CallableFuncSignature funcs = 2; }`,
+// where `CallableFuncSignature { bytes types = 1; }` holds a type per argument.
+func readMeta(data []byte) ([][]ArgType, error) {
+	var out [][]ArgType
+	err := readProtoFields(data, func(field int, value []byte) error {
+		if field != 2 {
+			return nil
+		}
+		var types []ArgType
+		err := readProtoFields(value, func(field int, value []byte) error {
+			if field != 1 {
+				return nil
+			}
+			types = make([]ArgType, len(value))
+			for i, t := range value {
+				types[i] = ArgType(t)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		out = append(out, types)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// readProtoFields calls f for every length-delimited field of protobuf message, other fields are skipped.
+func readProtoFields(data []byte, f func(field int, value []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("invalid field key")
+		}
+		data = data[n:]
+		field, wireType := int(key>>3), key&7
+		switch wireType {
+		case wireVarint:
+			_, n = binary.Uvarint(data)
+			if n <= 0 {
+				return errors.Errorf("invalid varint of field %d", field)
+			}
+			data = data[n:]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if wireType == wireFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return errors.Errorf("invalid fixed size value of field %d", field)
+			}
+			data = data[size:]
+		case wireLengthDelimited:
+			l, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < l {
+				return errors.Errorf("invalid length of field %d", field)
+			}
+			if err := f(field, data[n:n+int(l)]); err != nil {
+				return err
+			}
+			data = data[n+int(l):]
+		default:
+			return errors.Errorf("unsupported wire type %d of field %d", wireType, field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+	. "github.com/wavesplatform/gowaves/pkg/ride/evaluator/reader"
+)
+
+const (
+	// {-# STDLIB_VERSION 3 #-}
+	// {-# CONTENT_TYPE DAPP #-}
+	//
+	// @Callable(i)
+	// func deposit(amount: Int, note: String) = WriteSet([DataEntry("note", note)])
+	//
+	// @Verifier(tx)
+	// func verify() = this == tx.sender
+	contractWithVerifier = "AAIDAAAAAAAAAAgIARIECgIBCAAAAAAAAAABAAAAAWkBAAAAB2RlcG9zaXQAAAACAAAABmFtb3VudAAAAARub3RlCQEAAAAIV3JpdGVTZXQAAAABCQAETAAAAAIJAQAAAAlEYXRhRW50cnkAAAACAgAAAARub3RlBQAAAARub3RlBQAAAANuaWwAAAABAAAAAnR4AQAAAAZ2ZXJpZnkAAAAACQAAAAAAAAIFAAAABHRoaXMIBQAAAAJ0eAAAAAZzZW5kZXLKYrfh"
+	// The same DApp without verifier function.
+	contractWithoutVerifier = "AAIDAAAAAAAAAAgIARIECgIBCAAAAAAAAAABAAAAAWkBAAAAB2RlcG9zaXQAAAACAAAABmFtb3VudAAAAARub3RlCQEAAAAIV3JpdGVTZXQAAAABCQAETAAAAAIJAQAAAAlEYXRhRW50cnkAAAACAgAAAARub3RlBQAAAARub3RlBQAAAANuaWwAAAAAjPEmGQ=="
+)
+
+func TestBuildContract(t *testing.T) {
+	r, err := NewReaderFromBase64(contractWithVerifier)
+	require.NoError(t, err)
+	contract, err := BuildContract(r)
+	require.NoError(t, err)
+	assert.Equal(t, 3, contract.Version)
+	assert.Equal(t, []string{"deposit"}, contract.CallableNames())
+	deposit := contract.Callables["deposit"]
+	assert.Equal(t, "i", deposit.ArgumentName)
+	assert.Equal(t, []string{"amount", "note"}, deposit.Func.Args)
+	assert.Equal(t, []ArgType{ArgInt, ArgString}, deposit.ArgTypes)
+	require.True(t, contract.HasVerifier())
+	assert.Equal(t, "tx", contract.Verifier.ArgumentName)
+	assert.Equal(t, "verify", contract.Verifier.Func.Name)
+
+	r, err = NewReaderFromBase64(contractWithoutVerifier)
+	require.NoError(t, err)
+	contract, err = BuildContract(r)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deposit"}, contract.CallableNames())
+	assert.False(t, contract.HasVerifier())
+
+	// expression script is not a DApp
+	r, err = NewReaderFromBase64("AQa3b8tH")
+	require.NoError(t, err)
+	_, err = BuildContract(r)
+	assert.Error(t, err)
+}
+
+func TestLoadContract(t *testing.T) {
+	script, err := base64.StdEncoding.DecodeString(contractWithVerifier)
+	require.NoError(t, err)
+	contract, err := LoadContract(script)
+	require.NoError(t, err)
+	assert.Equal(t, 3, contract.Version)
+	assert.True(t, contract.HasVerifier())
+
+	script[len(script)-1]++
+	_, err = LoadContract(script)
+	assert.IsType(t, &ChecksumError{}, err)
+
+	script, err = base64.StdEncoding.DecodeString("AQa3b8tH")
+	require.NoError(t, err)
+	_, err = LoadContract(script)
+	assert.Equal(t, &ContentTypeError{ContentType: ContentTypeExpression}, err)
+}
+
+func TestIsContract(t *testing.T) {
+	script, err := base64.StdEncoding.DecodeString(contractWithVerifier)
+	require.NoError(t, err)
+	assert.True(t, IsContract(script))
+	script, err = base64.StdEncoding.DecodeString("AQa3b8tH")
+	require.NoError(t, err)
+	assert.False(t, IsContract(script))
+	assert.False(t, IsContract(nil))
+	assert.False(t, IsContract(withChecksum(0, byte(ContentTypeExpression), 3, E_TRUE)))
+}
+
+func TestReadMeta(t *testing.T) {
+	// version 1, functions (Int|String, ByteVector) and ()
+	types, err := readMeta([]byte{0x08, 0x01, 0x12, 0x04, 0x0a, 0x02, 0x09, 0x02, 0x12, 0x00})
+	require.NoError(t, err)
+	assert.Equal(t, [][]ArgType{{ArgInt | ArgString, ArgBytes}, nil}, types)
+
+	types, err = readMeta(nil)
+	require.NoError(t, err)
+	assert.Empty(t, types)
+
+	// length of field exceeds the data
+	_, err = readMeta([]byte{0x12, 0x05, 0x0a})
+	assert.Error(t, err)
+}
@@ -6,17 +6,74 @@ import (
 	. "github.com/wavesplatform/gowaves/pkg/ride/evaluator/reader"
 )
 
-func BuildAst(r *BytesReader) (Expr, error) {
+// BuildScript reads expression script of standard library versions 1 to 3.
+func BuildScript(r *BytesReader) (*Script, error) {
 	b, err := r.ReadByte()
 	if err != nil {
 		return nil, errors.Errorf("ReadByte(): %v\n", err)
 	}
-	// first byte always should be E_BYTES
-	if b != E_BYTES {
-		return nil, errors.Errorf("BuildAst: invalid format, expected 1, found %d", b)
+	// first byte is the version of standard library
+	if b < MinStdLibVersion || b > MaxStdLibVersion {
+		return nil, errors.Errorf("BuildScript: unsupported version %d", b)
+	}
+
+	verifier, err := Walk(r)
+	if err != nil {
+		return nil, err
 	}
 
-	return Walk(r)
+	return &Script{
+		Version:  int(b),
+		Verifier: verifier,
+	}, nil
+}
+
+// LoadScript validates checksum and header of script bytes and reads expression script from them.
+func LoadScript(script []byte) (s *Script, err error) {
+	h, err := ReadHeader(script)
+	if err != nil {
+		return nil, err
+	}
+	if h.ContentType != ContentTypeExpression {
+		return nil, &ContentTypeError{ContentType: h.ContentType}
+	}
+	defer recoverTruncated(&err)
+	verifier, err := Walk(NewBytesReader(script[h.Length : len(script)-ChecksumLength]))
+	if err != nil {
+		return nil, err
+	}
+	return &Script{
+		Version:  h.Version,
+		Verifier: verifier,
+	}, nil
+}
+
+// LoadContract validates checksum and header of script bytes and reads DApp from them.
+func LoadContract(script []byte) (c *ContractScript, err error) {
+	h, err := ReadHeader(script)
+	if err != nil {
+		return nil, err
+	}
+	if h.ContentType != ContentTypeDApp {
+		return nil, &ContentTypeError{ContentType: h.ContentType}
+	}
+	defer recoverTruncated(&err)
+	return BuildContract(NewBytesReader(script[:len(script)-ChecksumLength]))
+}
+
+// recoverTruncated turns panic of reader, which reads beyond the end of malformed script, into error.
+func recoverTruncated(err *error) {
+	if r := recover(); r != nil {
+		*err = errors.Wrapf(ErrUnexpectedEOF, "%v", r)
+	}
+}
+
+func BuildAst(r *BytesReader) (Expr, error) {
+	script, err := BuildScript(r)
+	if err != nil {
+		return nil, err
+	}
+	return script.Verifier, nil
 }
 
 func Walk(iter *BytesReader) (Expr, error) {
@@ -39,6 +96,8 @@ func Walk(iter *BytesReader) (Expr, error) {
 		return readIf(iter)
 	case E_BLOCK:
 		return readBlock(iter)
+	case E_BLOCKV2:
+		return readBlockV2(iter)
 	case E_REF:
 		return &RefExpr{
 			Name: iter.ReadString(),
@@ -74,6 +133,55 @@ func readBlock(r *BytesReader) (*Block, error) {
 	}, nil
 }
 
+func readBlockV2(r *BytesReader) (*BlockV2, error) {
+	decl, err := readDeclaration(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := Walk(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBlockV2(decl, body), nil
+}
+
+func readDeclaration(r *BytesReader) (Declaration, error) {
+	if r.Eof() {
+		return nil, ErrUnexpectedEOF
+	}
+	switch decType := r.Next(); decType {
+	case DEC_LET:
+		name := r.ReadString()
+		value, err := Walk(r)
+		if err != nil {
+			return nil, err
+		}
+		return NewLet(name, value), nil
+	case DEC_FUNC:
+		return readFuncDeclaration(r)
+	default:
+		return nil, errors.Errorf("invalid declaration type %d", decType)
+	}
+}
+
+func readFuncDeclaration(r *BytesReader) (*FuncDeclaration, error) {
+	name := r.ReadString()
+	argc := r.ReadInt()
+	args := make([]string, argc)
+	for i := int32(0); i < argc; i++ {
+		args[i] = r.ReadString()
+	}
+
+	body, err := Walk(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFuncDeclaration(name, args, body), nil
+}
+
 func readFuncCAll(iter *BytesReader) (*FuncCall, error) {
 	nativeOrUser, err := iter.ReadByte()
 	if err != nil {
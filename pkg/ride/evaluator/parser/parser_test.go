@@ -0,0 +1,39 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	. "github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+	. "github.com/wavesplatform/gowaves/pkg/ride/evaluator/reader"
+)
+
+func withChecksum(body ...byte) []byte {
+	hash, err := crypto.SecureHash(body)
+	if err != nil {
+		panic(err)
+	}
+	return append(body, hash[:ChecksumLength]...)
+}
+
+func TestLoadScript(t *testing.T) {
+	s, err := LoadScript(withChecksum(2, E_TRUE))
+	require.NoError(t, err)
+	assert.Equal(t, &Script{Version: 2, Verifier: NewBoolean(true)}, s)
+
+	s, err = LoadScript(withChecksum(0, byte(ContentTypeExpression), 3, E_FALSE))
+	require.NoError(t, err)
+	assert.Equal(t, &Script{Version: 3, Verifier: NewBoolean(false)}, s)
+
+	_, err = LoadScript(append([]byte{2, E_TRUE}, 0, 0, 0, 0))
+	assert.IsType(t, &ChecksumError{}, err)
+	_, err = LoadScript(withChecksum(0, byte(ContentTypeDApp), 3))
+	assert.Equal(t, &ContentTypeError{ContentType: ContentTypeDApp}, err)
+	// long value is truncated
+	_, err = LoadScript(withChecksum(3, E_LONG, 0))
+	require.Error(t, err)
+	assert.Equal(t, ErrUnexpectedEOF, errors.Cause(err))
+}
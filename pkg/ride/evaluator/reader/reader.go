@@ -17,6 +17,10 @@ const E_TRUE byte = 6
 const E_FALSE byte = 7
 const E_GETTER byte = 8
 const E_FUNCALL byte = 9
+const E_BLOCKV2 byte = 10
+
+const DEC_LET byte = 0
+const DEC_FUNC byte = 1
 
 const FH_NATIVE byte = 0
 const FH_USER byte = 1
@@ -0,0 +1,119 @@
+package reader
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+// ChecksumLength is the size of checksum which ends script bytes.
+const ChecksumLength = 4
+
+// Versions of standard library supported by scripts.
+const (
+	MinStdLibVersion = 1
+	MaxStdLibVersion = 3
+)
+
+type ContentType byte
+
+const (
+	ContentTypeExpression ContentType = 1
+	ContentTypeDApp       ContentType = 2
+)
+
+func (t ContentType) String() string {
+	switch t {
+	case ContentTypeExpression:
+		return "Expression"
+	case ContentTypeDApp:
+		return "DApp"
+	default:
+		return fmt.Sprintf("Unknown(%d)", byte(t))
+	}
+}
+
+// ChecksumError is returned if checksum of script doesn't match its bytes.
+type ChecksumError struct {
+	Expected []byte
+	Actual   []byte
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("invalid script checksum %x, expected %x", e.Actual, e.Expected)
+}
+
+// VersionError is returned for unsupported version of standard library.
+type VersionError struct {
+	Version byte
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("unsupported version of standard library %d", e.Version)
+}
+
+// ContentTypeError is returned for unknown content type or if script is not of the expected one.
+type ContentTypeError struct {
+	ContentType ContentType
+}
+
+func (e *ContentTypeError) Error() string {
+	return fmt.Sprintf("unexpected script content type %s", e.ContentType)
+}
+
+// ScriptHeader describes script bytes.
+//
+// Expression scripts of standard library versions 1 to 3 start with the version byte.
+// Newer format starts with zero byte followed by content type and version,
+// it is used by DApps and may be used by expressions.
+type ScriptHeader struct {
+	ContentType ContentType
+	Version     int
+	// Length of header in bytes, the body of script follows it.
+	Length int
+}
+
+// ReadHeader validates checksum of script and decodes its header.
+func ReadHeader(script []byte) (*ScriptHeader, error) {
+	if len(script) < 1+ChecksumLength {
+		return nil, ErrUnexpectedEOF
+	}
+	if err := validateChecksum(script); err != nil {
+		return nil, err
+	}
+	h := &ScriptHeader{ContentType: ContentTypeExpression, Length: 1}
+	version := script[0]
+	if version == 0 {
+		if len(script) < 3+ChecksumLength {
+			return nil, ErrUnexpectedEOF
+		}
+		h.ContentType = ContentType(script[1])
+		if h.ContentType != ContentTypeExpression && h.ContentType != ContentTypeDApp {
+			return nil, &ContentTypeError{ContentType: h.ContentType}
+		}
+		version = script[2]
+		h.Length = 3
+	}
+	if version < MinStdLibVersion || version > MaxStdLibVersion {
+		return nil, &VersionError{Version: version}
+	}
+	h.Version = int(version)
+	return h, nil
+}
+
+// validateChecksum checks that script ends with first bytes of secure hash of the rest of it.
+func validateChecksum(script []byte) error {
+	body := script[:len(script)-ChecksumLength]
+	hash, err := crypto.SecureHash(body)
+	if err != nil {
+		return errors.Wrap(err, "failed to calculate script checksum")
+	}
+	expected := hash[:ChecksumLength]
+	actual := script[len(body):]
+	if !bytes.Equal(expected, actual) {
+		return &ChecksumError{Expected: expected, Actual: actual}
+	}
+	return nil
+}
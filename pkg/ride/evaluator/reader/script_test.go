@@ -0,0 +1,55 @@
+package reader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+func withChecksum(body ...byte) []byte {
+	hash, err := crypto.SecureHash(body)
+	if err != nil {
+		panic(err)
+	}
+	return append(body, hash[:ChecksumLength]...)
+}
+
+func TestReadHeader(t *testing.T) {
+	for _, test := range []struct {
+		script []byte
+		header ScriptHeader
+	}{
+		// {-# STDLIB_VERSION 1 #-} true
+		{decode("AQa3b8tH"), ScriptHeader{ContentType: ContentTypeExpression, Version: 1, Length: 1}},
+		{withChecksum(3, E_TRUE), ScriptHeader{ContentType: ContentTypeExpression, Version: 3, Length: 1}},
+		{withChecksum(0, 1, 3, E_TRUE), ScriptHeader{ContentType: ContentTypeExpression, Version: 3, Length: 3}},
+		{withChecksum(0, 2, 3), ScriptHeader{ContentType: ContentTypeDApp, Version: 3, Length: 3}},
+	} {
+		h, err := ReadHeader(test.script)
+		require.NoError(t, err)
+		assert.Equal(t, test.header, *h)
+	}
+}
+
+func TestReadHeaderErrors(t *testing.T) {
+	_, err := ReadHeader(nil)
+	assert.Equal(t, ErrUnexpectedEOF, err)
+	_, err = ReadHeader(withChecksum(0, 2))
+	assert.Equal(t, ErrUnexpectedEOF, err)
+
+	script := decode("AQa3b8tH")
+	script[len(script)-1]++
+	_, err = ReadHeader(script)
+	require.IsType(t, &ChecksumError{}, err)
+	assert.Equal(t, []byte{0xb7, 0x6f, 0xcb, 0x47}, err.(*ChecksumError).Expected)
+
+	_, err = ReadHeader(withChecksum(4, E_TRUE))
+	assert.Equal(t, &VersionError{Version: 4}, err)
+	_, err = ReadHeader(withChecksum(0, 2, 0))
+	assert.Equal(t, &VersionError{Version: 0}, err)
+	_, err = ReadHeader(withChecksum(0, 3, 3))
+	assert.Equal(t, &ContentTypeError{ContentType: 3}, err)
+	assert.EqualError(t, err, "unexpected script content type Unknown(3)")
+}
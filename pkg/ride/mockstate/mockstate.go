@@ -3,6 +3,7 @@ package mockstate
 import (
 	"github.com/mr-tron/base58/base58"
 	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 )
 
@@ -10,20 +11,49 @@ var ErrNotFound = errors.New("Not found")
 
 type Account interface {
 	Data() []proto.DataEntry
+	// DataEntry returns the entry with given key or ErrNotFound.
+	DataEntry(key string) (proto.DataEntry, error)
 	AssetBalance(*proto.OptionalAsset) uint64
 	Address() proto.Address
 }
 
+// AssetInfo is the asset description available to scripts.
+type AssetInfo struct {
+	ID              crypto.Digest
+	Quantity        int64
+	Decimals        int64
+	Issuer          proto.Address
+	IssuerPublicKey crypto.PublicKey
+	Reissuable      bool
+	Scripted        bool
+	Sponsored       bool
+}
+
+// BlockInfo is the block header description available to scripts.
+type BlockInfo struct {
+	Timestamp           uint64
+	Height              uint64
+	BaseTarget          uint64
+	GenerationSignature crypto.Digest
+	Generator           proto.Address
+	GeneratorPublicKey  crypto.PublicKey
+}
+
 type MockState interface {
 	TransactionByID([]byte) (proto.Transaction, error)
 	TransactionHeightByID([]byte) (uint64, error)
-	Account(proto.Recipient) Account
+	// Account returns the account of the recipient, it fails if the recipient is an unknown alias.
+	Account(proto.Recipient) (Account, error)
+	AssetInfo(crypto.Digest) (*AssetInfo, error)
+	BlockInfoByHeight(uint64) (*BlockInfo, error)
 }
 
 type MockStateImpl struct {
 	TransactionsByID       map[string]proto.Transaction
 	TransactionsHeightByID map[string]uint64
-	Accounts               map[string]Account // recipient to account
+	Accounts               map[string]Account    // recipient to account
+	Assets                 map[string]*AssetInfo // base58 encoded ID to asset
+	Blocks                 map[uint64]*BlockInfo // height to block
 }
 
 func (a MockStateImpl) TransactionByID(b []byte) (proto.Transaction, error) {
@@ -42,8 +72,31 @@ func (a MockStateImpl) TransactionHeightByID(b []byte) (uint64, error) {
 	return h, nil
 }
 
-func (a MockStateImpl) Account(r proto.Recipient) Account {
-	return a.Accounts[r.String()]
+func (a MockStateImpl) AssetInfo(id crypto.Digest) (*AssetInfo, error) {
+	info, ok := a.Assets[id.String()]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return info, nil
+}
+
+func (a MockStateImpl) BlockInfoByHeight(height uint64) (*BlockInfo, error) {
+	info, ok := a.Blocks[height]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return info, nil
+}
+
+func (a MockStateImpl) Account(r proto.Recipient) (Account, error) {
+	if acc, ok := a.Accounts[r.String()]; ok {
+		return acc, nil
+	}
+	if r.Address == nil {
+		return nil, ErrNotFound
+	}
+	// Unknown account has neither balances nor data.
+	return &MockAccount{AddressField: *r.Address}, nil
 }
 
 type MockAccount struct {
@@ -55,6 +108,16 @@ type MockAccount struct {
 func (a *MockAccount) Data() []proto.DataEntry {
 	return a.DataEntries
 }
+
+func (a *MockAccount) DataEntry(key string) (proto.DataEntry, error) {
+	for _, e := range a.DataEntries {
+		if e.GetKey() == key {
+			return e, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
 func (a *MockAccount) AssetBalance(p *proto.OptionalAsset) uint64 {
 	return a.Assets[p.String()]
 }
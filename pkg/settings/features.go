@@ -27,14 +27,14 @@ var FeaturesInfo = map[Feature]FeatureInfo{
 	SmallerMinimalGeneratingBalance: {true, "Minimum Generating Balance of 1000 WAVES"},
 	NG:                              {true, "NG Protocol"},
 	MassTransfer:                    {true, "Mass Transfer Transaction"},
-	SmartAccounts:                   {false, "Smart Accounts"},
-	DataTransaction:                 {false, "Data Transaction"},
+	SmartAccounts:                   {true, "Smart Accounts"},
+	DataTransaction:                 {true, "Data Transaction"},
 	BurnAnyTokens:                   {false, "Burn Any Tokens"},
-	FeeSponsorship:                  {false, "Fee Sponsorship"},
+	FeeSponsorship:                  {true, "Fee Sponsorship"},
 	FairPoS:                         {true, "Fair PoS"},
-	SmartAssets:                     {false, "Smart Assets"},
+	SmartAssets:                     {true, "Smart Assets"},
 	SmartAccountTrading:             {false, "Smart Account Trading"},
-	Ride4DApps:                      {false, "RIDE 4 DAPPS"},
+	Ride4DApps:                      {true, "RIDE 4 DAPPS"},
 	OrderV3:                         {false, "Order Version 3"},
-	ReduceNFTFee:                    {false, "Reduce NFT fee"},
+	ReduceNFTFee:                    {true, "Reduce NFT fee"},
 }
@@ -0,0 +1,211 @@
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+type dataEntryRecord struct {
+	entry    proto.DataEntry
+	blockNum uint32
+}
+
+func (r *dataEntryRecord) marshalBinary() ([]byte, error) {
+	entryBytes, err := r.entry.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, len(entryBytes)+4)
+	copy(res, entryBytes)
+	binary.BigEndian.PutUint32(res[len(entryBytes):], r.blockNum)
+	return res, nil
+}
+
+func (r *dataEntryRecord) unmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("invalid data size")
+	}
+	entryBytes := data[:len(data)-4]
+	entry, err := unmarshalDataEntry(entryBytes)
+	if err != nil {
+		return err
+	}
+	r.entry = entry
+	r.blockNum = binary.BigEndian.Uint32(data[len(data)-4:])
+	return nil
+}
+
+// unmarshalDataEntry decodes data entry of any value type from its binary representation.
+func unmarshalDataEntry(data []byte) (proto.DataEntry, error) {
+	if len(data) < 2 {
+		return nil, errors.New("not enough data to read data entry key length")
+	}
+	keyLen := int(binary.BigEndian.Uint16(data[:2]))
+	if len(data) < 2+keyLen+1 {
+		return nil, errors.New("not enough data to read data entry value type")
+	}
+	switch proto.DataValueType(data[2+keyLen]) {
+	case proto.DataInteger:
+		var e proto.IntegerDataEntry
+		if err := e.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case proto.DataBoolean:
+		var e proto.BooleanDataEntry
+		if err := e.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case proto.DataBinary:
+		var e proto.BinaryDataEntry
+		if err := e.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case proto.DataString:
+		var e proto.StringDataEntry
+		if err := e.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return e, nil
+	default:
+		return nil, errors.Errorf("unsupported data value type %d", data[2+keyLen])
+	}
+}
+
+type accountsDataStorage struct {
+	db      keyvalue.IterableKeyVal
+	dbBatch keyvalue.Batch
+	stateDB *stateDB
+	hs      *historyStorage
+}
+
+func newAccountsDataStorage(db keyvalue.IterableKeyVal, dbBatch keyvalue.Batch, stateDB *stateDB, hs *historyStorage) (*accountsDataStorage, error) {
+	return &accountsDataStorage{db, dbBatch, stateDB, hs}, nil
+}
+
+func (s *accountsDataStorage) appendEntry(addr proto.Address, entry proto.DataEntry, blockID crypto.Signature) error {
+	key := accountsDataStorKey{addr: addr, entryKey: entry.GetKey()}
+	blockNum, err := s.stateDB.blockIdToNum(blockID)
+	if err != nil {
+		return err
+	}
+	r := &dataEntryRecord{entry, blockNum}
+	recordBytes, err := r.marshalBinary()
+	if err != nil {
+		return errors.Errorf("failed to marshal record: %v\n", err)
+	}
+	return s.hs.set(dataEntry, key.bytes(), recordBytes)
+}
+
+// Data entry from DB or local storage.
+func (s *accountsDataStorage) newestEntry(addr proto.Address, entryKey string, filter bool) (proto.DataEntry, error) {
+	key := accountsDataStorKey{addr: addr, entryKey: entryKey}
+	return s.newestEntryByKey(key.bytes(), filter)
+}
+
+func (s *accountsDataStorage) newestEntryByKey(key []byte, filter bool) (proto.DataEntry, error) {
+	recordBytes, err := s.hs.getFresh(dataEntry, key, filter)
+	if err != nil {
+		return nil, err
+	}
+	var record dataEntryRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return nil, errors.Errorf("failed to unmarshal record: %v\n", err)
+	}
+	return record.entry, nil
+}
+
+// Stable data entry from DB.
+func (s *accountsDataStorage) retrieveEntry(addr proto.Address, entryKey string, filter bool) (proto.DataEntry, error) {
+	key := accountsDataStorKey{addr: addr, entryKey: entryKey}
+	return s.entryByKey(key.bytes(), filter)
+}
+
+func (s *accountsDataStorage) entryByKey(key []byte, filter bool) (proto.DataEntry, error) {
+	recordBytes, err := s.hs.get(dataEntry, key, filter)
+	if err != nil {
+		return nil, err
+	}
+	var record dataEntryRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return nil, errors.Errorf("failed to unmarshal record: %v\n", err)
+	}
+	return record.entry, nil
+}
+
+// Data entries of given address from DB and local storage.
+func (s *accountsDataStorage) newestEntries(addr proto.Address, filter bool) ([]proto.DataEntry, error) {
+	prefix := accountsDataStorAddrPrefix(addr)
+	keys := make(map[string]struct{})
+	for _, entry := range s.hs.stor.getEntries() {
+		if entry.entityType == dataEntry && bytes.HasPrefix(entry.key, prefix) {
+			keys[string(entry.key)] = empty
+		}
+	}
+	iter, err := s.db.NewKeyIterator(prefix)
+	if err != nil {
+		return nil, errors.Errorf("failed to create key iterator for data entries: %v\n", err)
+	}
+	for iter.Next() {
+		keys[string(iter.Key())] = empty
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return nil, errors.Errorf("failed to iterate data entries: %v\n", err)
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	entries := make([]proto.DataEntry, 0, len(sortedKeys))
+	for _, key := range sortedKeys {
+		entry, err := s.newestEntryByKey([]byte(key), filter)
+		if err == errEmptyHist {
+			// All the records were removed by rollback.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Stable data entries of given address from DB.
+func (s *accountsDataStorage) retrieveEntries(addr proto.Address, filter bool) ([]proto.DataEntry, error) {
+	iter, err := s.db.NewKeyIterator(accountsDataStorAddrPrefix(addr))
+	if err != nil {
+		return nil, errors.Errorf("failed to create key iterator for data entries: %v\n", err)
+	}
+	defer func() {
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			log.Fatalf("Iterator error: %v", err)
+		}
+	}()
+
+	var entries []proto.DataEntry
+	for iter.Next() {
+		entry, err := s.entryByKey(keyvalue.SafeKey(iter), filter)
+		if err == errEmptyHist {
+			// All the records were removed by rollback.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,87 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/util"
+)
+
+type accountsDataStorageTestObjects struct {
+	stor             *storageObjects
+	accountsDataStor *accountsDataStorage
+}
+
+func createAccountsDataStorage() (*accountsDataStorageTestObjects, []string, error) {
+	stor, path, err := createStorageObjects()
+	if err != nil {
+		return nil, path, err
+	}
+	accountsDataStor, err := newAccountsDataStorage(stor.db, stor.dbBatch, stor.stateDB, stor.hs)
+	if err != nil {
+		return nil, path, err
+	}
+	return &accountsDataStorageTestObjects{stor, accountsDataStor}, path, nil
+}
+
+func TestAppendEntry(t *testing.T) {
+	to, path, err := createAccountsDataStorage()
+	assert.NoError(t, err, "createAccountsDataStorage() failed")
+
+	defer func() {
+		err = to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	addr := testGlobal.senderInfo.addr
+	entries := []proto.DataEntry{
+		proto.IntegerDataEntry{Key: "int", Value: 100500},
+		proto.BooleanDataEntry{Key: "bool", Value: true},
+		proto.BinaryDataEntry{Key: "bin", Value: []byte{0xca, 0xfe}},
+		proto.StringDataEntry{Key: "str", Value: "string"},
+	}
+	to.stor.addBlock(t, blockID0)
+	for _, entry := range entries {
+		err = to.accountsDataStor.appendEntry(addr, entry, blockID0)
+		assert.NoError(t, err, "appendEntry() failed")
+		newest, err := to.accountsDataStor.newestEntry(addr, entry.GetKey(), true)
+		assert.NoError(t, err, "newestEntry() failed")
+		assert.Equal(t, entry, newest)
+	}
+	newestAll, err := to.accountsDataStor.newestEntries(addr, true)
+	assert.NoError(t, err, "newestEntries() failed")
+	assert.ElementsMatch(t, entries, newestAll)
+	to.stor.flush(t)
+	for _, entry := range entries {
+		stable, err := to.accountsDataStor.retrieveEntry(addr, entry.GetKey(), true)
+		assert.NoError(t, err, "retrieveEntry() failed")
+		assert.Equal(t, entry, stable)
+	}
+	all, err := to.accountsDataStor.retrieveEntries(addr, true)
+	assert.NoError(t, err, "retrieveEntries() failed")
+	assert.ElementsMatch(t, entries, all)
+	other, err := to.accountsDataStor.retrieveEntries(testGlobal.recipientInfo.addr, true)
+	assert.NoError(t, err, "retrieveEntries() failed")
+	assert.Empty(t, other)
+
+	// Rewrite entry with value of different size in the next block, and then roll it back.
+	to.stor.addBlock(t, blockID1)
+	rewritten := proto.StringDataEntry{Key: "str", Value: "much longer string"}
+	err = to.accountsDataStor.appendEntry(addr, rewritten, blockID1)
+	assert.NoError(t, err, "appendEntry() failed")
+	newestAll, err = to.accountsDataStor.newestEntries(addr, true)
+	assert.NoError(t, err, "newestEntries() failed")
+	assert.ElementsMatch(t, append(entries[:3:3], rewritten), newestAll)
+	to.stor.flush(t)
+	stable, err := to.accountsDataStor.retrieveEntry(addr, rewritten.Key, true)
+	assert.NoError(t, err, "retrieveEntry() failed")
+	assert.Equal(t, rewritten, stable)
+	err = to.stor.stateDB.rollbackBlock(blockID1)
+	assert.NoError(t, err, "rollbackBlock() failed")
+	stable, err = to.accountsDataStor.retrieveEntry(addr, rewritten.Key, true)
+	assert.NoError(t, err, "retrieveEntry() failed")
+	assert.Equal(t, entries[3], stable)
+}
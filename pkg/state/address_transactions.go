@@ -0,0 +1,195 @@
+package state
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+var errTxNotInvolveAddress = errors.New("transaction doesn't involve address")
+
+// addressTransactionRecord is transaction involving address and unique number of block which includes it.
+type addressTransactionRecord struct {
+	txID     []byte
+	blockNum uint32
+}
+
+// addressTransactions is the index of transactions by addresses they involve: senders, recipients of transfers,
+// payments and leases, parties of exchanges and dApps of invocations.
+// Records of rolled back blocks are not removed, they are skipped on reading since their blocks are not valid.
+type addressTransactions struct {
+	db      keyvalue.IterableKeyVal
+	dbBatch keyvalue.Batch
+	stateDB *stateDB
+	stor    *blockchainEntitiesStorage
+	scheme  byte
+}
+
+func newAddressTransactions(db keyvalue.IterableKeyVal, dbBatch keyvalue.Batch, stateDB *stateDB, stor *blockchainEntitiesStorage, scheme byte) *addressTransactions {
+	return &addressTransactions{db, dbBatch, stateDB, stor, scheme}
+}
+
+// involvedAddresses returns unique addresses involved in transaction, aliases are resolved to addresses.
+func (at *addressTransactions) involvedAddresses(tx proto.Transaction, filter bool) ([]proto.Address, error) {
+	var addresses []proto.Address
+	add := func(addr proto.Address) {
+		for _, a := range addresses {
+			if a == addr {
+				return
+			}
+		}
+		addresses = append(addresses, addr)
+	}
+	addPK := func(pk crypto.PublicKey) error {
+		addr, err := proto.NewAddressFromPublicKey(at.scheme, pk)
+		if err != nil {
+			return err
+		}
+		add(addr)
+		return nil
+	}
+	addRecipient := func(rcp proto.Recipient) error {
+		addr, err := recipientToAddress(rcp, at.stor.aliases, filter)
+		if err != nil {
+			return err
+		}
+		add(*addr)
+		return nil
+	}
+	if senderPK, ok := txSenderPK(tx); ok {
+		if err := addPK(senderPK); err != nil {
+			return nil, err
+		}
+	}
+	switch t := tx.(type) {
+	case *proto.Genesis:
+		add(t.Recipient)
+	case *proto.Payment:
+		add(t.Recipient)
+	case *proto.TransferV1:
+		if err := addRecipient(t.Recipient); err != nil {
+			return nil, err
+		}
+	case *proto.TransferV2:
+		if err := addRecipient(t.Recipient); err != nil {
+			return nil, err
+		}
+	case *proto.MassTransferV1:
+		for _, entry := range t.Transfers {
+			if err := addRecipient(entry.Recipient); err != nil {
+				return nil, err
+			}
+		}
+	case proto.Exchange:
+		for _, order := range []func() (proto.OrderBody, error){t.GetBuyOrder, t.GetSellOrder} {
+			body, err := order()
+			if err != nil {
+				return nil, err
+			}
+			if err := addPK(body.SenderPK); err != nil {
+				return nil, err
+			}
+		}
+	case *proto.LeaseV1:
+		if err := addRecipient(t.Recipient); err != nil {
+			return nil, err
+		}
+	case *proto.LeaseV2:
+		if err := addRecipient(t.Recipient); err != nil {
+			return nil, err
+		}
+	case *proto.LeaseCancelV1:
+		if err := at.addLeaseRecipient(t.LeaseID, add, filter); err != nil {
+			return nil, err
+		}
+	case *proto.LeaseCancelV2:
+		if err := at.addLeaseRecipient(t.LeaseID, add, filter); err != nil {
+			return nil, err
+		}
+	case *proto.InvokeScriptV1:
+		if err := addRecipient(t.ScriptRecipient); err != nil {
+			return nil, err
+		}
+	}
+	return addresses, nil
+}
+
+func (at *addressTransactions) addLeaseRecipient(leaseID crypto.Digest, add func(proto.Address), filter bool) error {
+	l, err := at.stor.leases.newestLeasingInfo(leaseID, filter)
+	if err != nil {
+		return errors.Wrap(err, "failed to get leasing info")
+	}
+	add(l.recipient)
+	return nil
+}
+
+// saveTransaction adds transaction to the index of all the addresses it involves.
+// txNum is position of transaction in block, it orders transactions of the same block.
+func (at *addressTransactions) saveTransaction(tx proto.Transaction, blockID crypto.Signature, txNum uint32, filter bool) error {
+	txID, err := tx.GetID()
+	if err != nil {
+		return err
+	}
+	addresses, err := at.involvedAddresses(tx, filter)
+	if err != nil {
+		return err
+	}
+	blockNum, err := at.stateDB.blockIdToNum(blockID)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addresses {
+		key := addressTransactionKey{address: addr, blockNum: blockNum, txNum: txNum}
+		at.dbBatch.Put(key.bytes(), txID)
+	}
+	return nil
+}
+
+// transactions returns at most limit transactions of address from the newest to the oldest.
+// If after is not nil, transactions start from the one following it, afterBlockNum is number of block including it.
+func (at *addressTransactions) transactions(addr proto.Address, after []byte, afterBlockNum uint32, limit int) ([]addressTransactionRecord, error) {
+	iter, err := at.db.NewKeyIterator(addressTransactionsPrefix(addr))
+	if err != nil {
+		return nil, errors.Errorf("failed to create key iterator for address transactions: %v\n", err)
+	}
+	defer iter.Release()
+	if after != nil {
+		key := addressTransactionKey{address: addr, blockNum: afterBlockNum}
+		blockPrefix := key.blockPrefix()
+		found := false
+		for ok := iter.Seek(blockPrefix); ok && bytes.HasPrefix(iter.Key(), blockPrefix); ok = iter.Next() {
+			if bytes.Equal(iter.Value(), after) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errTxNotInvolveAddress
+		}
+	}
+	var records []addressTransactionRecord
+	for len(records) < limit && iter.Next() {
+		var key addressTransactionKey
+		if err := key.unmarshal(iter.Key()); err != nil {
+			return nil, errors.Errorf("failed to unmarshal key: %v\n", err)
+		}
+		valid, err := at.stateDB.isValidBlock(key.blockNum)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			// Block was rolled back.
+			continue
+		}
+		txID := make([]byte, len(iter.Value()))
+		copy(txID, iter.Value())
+		records = append(records, addressTransactionRecord{txID: txID, blockNum: key.blockNum})
+	}
+	if err := iter.Error(); err != nil {
+		return nil, errors.Errorf("failed to iterate address transactions: %v\n", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,122 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/util"
+)
+
+type addressTransactionsTestObjects struct {
+	stor                *storageObjects
+	addressTransactions *addressTransactions
+}
+
+func createAddressTransactions() (*addressTransactionsTestObjects, []string, error) {
+	stor, path, err := createStorageObjects()
+	if err != nil {
+		return nil, path, err
+	}
+	entities, err := newBlockchainEntitiesStorage(stor.hs, stor.stateDB, settings.MainNetSettings)
+	if err != nil {
+		return nil, path, err
+	}
+	addressTransactions := newAddressTransactions(stor.db, stor.dbBatch, stor.stateDB, entities, proto.MainNetScheme)
+	return &addressTransactionsTestObjects{stor, addressTransactions}, path, nil
+}
+
+func txIDs(t *testing.T, txs ...proto.Transaction) [][]byte {
+	ids := make([][]byte, len(txs))
+	for i, tx := range txs {
+		id, err := tx.GetID()
+		assert.NoError(t, err, "GetID() failed")
+		ids[i] = id
+	}
+	return ids
+}
+
+func recordsIDs(records []addressTransactionRecord) [][]byte {
+	ids := make([][]byte, len(records))
+	for i, r := range records {
+		ids[i] = r.txID
+	}
+	return ids
+}
+
+func TestSaveAddressTransactions(t *testing.T) {
+	to, path, err := createAddressTransactions()
+	assert.NoError(t, err, "createAddressTransactions() failed")
+
+	defer func() {
+		err = to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	seed, err := base58.Decode("3TUPTbbpiM5UmZDhMmzdsKKNgMvyHwZQncKWfJrxk3bc")
+	assert.NoError(t, err, "base58.Decode() failed")
+	sk, _ := crypto.GenerateKeyPair(seed)
+	transfer := createTransferV1(t)
+	err = transfer.Sign(sk)
+	assert.NoError(t, err, "Sign() failed")
+	lease := createLeaseV1(t)
+	exchange := createExchangeV1(t)
+	// Orders are not signed, so ID of exchange is set directly.
+	exchangeID := crypto.MustDigestFromBase58(assetStr)
+	exchange.ID = &exchangeID
+	to.stor.addBlock(t, blockID0)
+	for i, tx := range []proto.Transaction{transfer, lease} {
+		err = to.addressTransactions.saveTransaction(tx, blockID0, uint32(i), true)
+		assert.NoError(t, err, "saveTransaction() failed")
+	}
+	to.stor.flush(t)
+	to.stor.addBlock(t, blockID1)
+	err = to.addressTransactions.saveTransaction(exchange, blockID1, 0, true)
+	assert.NoError(t, err, "saveTransaction() failed")
+	to.stor.flush(t)
+	senderAddr, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, testGlobal.senderInfo.pk)
+	assert.NoError(t, err, "NewAddressFromPublicKey() failed")
+	matcherAddr, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, testGlobal.matcherInfo.pk)
+	assert.NoError(t, err, "NewAddressFromPublicKey() failed")
+
+	// Transactions are returned from the newest to the oldest.
+	records, err := to.addressTransactions.transactions(senderAddr, nil, 0, 10)
+	assert.NoError(t, err, "transactions() failed")
+	assert.Equal(t, txIDs(t, exchange, lease, transfer), recordsIDs(records))
+	blockNum0, err := to.stor.stateDB.blockIdToNum(blockID0)
+	assert.NoError(t, err, "blockIdToNum() failed")
+	blockNum1, err := to.stor.stateDB.blockIdToNum(blockID1)
+	assert.NoError(t, err, "blockIdToNum() failed")
+	assert.Equal(t, []uint32{blockNum1, blockNum0, blockNum0}, []uint32{records[0].blockNum, records[1].blockNum, records[2].blockNum})
+
+	// Limit and pagination.
+	records, err = to.addressTransactions.transactions(senderAddr, nil, 0, 2)
+	assert.NoError(t, err, "transactions() failed")
+	assert.Equal(t, txIDs(t, exchange, lease), recordsIDs(records))
+	records, err = to.addressTransactions.transactions(senderAddr, records[1].txID, records[1].blockNum, 2)
+	assert.NoError(t, err, "transactions() failed")
+	assert.Equal(t, txIDs(t, transfer), recordsIDs(records))
+
+	// Matcher is involved in exchange only.
+	records, err = to.addressTransactions.transactions(matcherAddr, nil, 0, 10)
+	assert.NoError(t, err, "transactions() failed")
+	assert.Equal(t, txIDs(t, exchange), recordsIDs(records))
+	transferID := txIDs(t, transfer)[0]
+	_, err = to.addressTransactions.transactions(matcherAddr, transferID, blockNum0, 10)
+	assert.Equal(t, errTxNotInvolveAddress, err)
+
+	// Transactions of rolled back block are skipped.
+	err = to.stor.stateDB.rollbackBlock(blockID1)
+	assert.NoError(t, err, "rollbackBlock() failed")
+	records, err = to.addressTransactions.transactions(senderAddr, nil, 0, 10)
+	assert.NoError(t, err, "transactions() failed")
+	assert.Equal(t, txIDs(t, lease, transfer), recordsIDs(records))
+	records, err = to.addressTransactions.transactions(matcherAddr, nil, 0, 10)
+	assert.NoError(t, err, "transactions() failed")
+	assert.Empty(t, records)
+}
@@ -78,6 +78,7 @@ type State interface {
 	ValidateSingleTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64) error
 	// ValidateNextTx() validates transaction against state, taking into account all the previous changes from transactions
 	// that were added using ValidateNextTx() until you call ResetValidationList().
+	// Transactions with fee less than minimal fee are rejected.
 	// Does not change state.
 	// Returns TxValidationError or nil.
 	ValidateNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64) error
@@ -94,9 +95,116 @@ type State interface {
 	IsApproved(featureID int16) (bool, error)
 	ApprovalHeight(featureID int16) (uint64, error)
 
+	// Accounts data storage.
+	RetrieveEntry(addr proto.Address, key string) (proto.DataEntry, error)
+	RetrieveEntries(addr proto.Address) ([]proto.DataEntry, error)
+
+	// Account scripts.
+	// AccountScript returns empty script for accounts without script.
+	AccountScript(addr proto.Address) (proto.Script, error)
+	HasVerifier(addr proto.Address) (bool, error)
+	ScriptInfo(addr proto.Address) (*ScriptInfo, error)
+
+	// Assets.
+	// AddressAssets returns all non-zero asset balances of address, including NFTs, ordered by asset IDs.
+	AddressAssets(addr proto.Address) ([]*AssetBalance, error)
+	// AddressNFTs returns at most limit non-fungible tokens owned by address ordered by their IDs,
+	// starting from the one following token with ID after, or from the first one if after is nil.
+	AddressNFTs(addr proto.Address, after []byte, limit int) ([]*AssetInfo, error)
+
+	// AssetDistribution returns at most limit holders of asset with their balances at the given height ordered by addresses,
+	// starting from the one following address after, or from the first one if after is nil.
+	// Only heights available for rollback are supported unless state is created with StoreFullHistory parameter.
+	AssetDistribution(assetID crypto.Digest, height uint64, after *proto.Address, limit int) ([]*AssetHolder, error)
+
+	// Asset scripts.
+	// AssetScript returns empty script for assets without script.
+	AssetScript(assetID crypto.Digest) (proto.Script, error)
+
+	// Transactions.
+	// TransactionByID returns transaction included into stored block, NotFoundError if there is no such transaction.
+	TransactionByID(id []byte) (proto.Transaction, error)
+	// TransactionHeightByID returns height of block which includes transaction.
+	TransactionHeightByID(id []byte) (uint64, error)
+	// TransactionInfoByID returns transaction together with ID and height of block which includes it.
+	TransactionInfoByID(id []byte) (*TransactionInfo, error)
+	// IsTransactionConfirmed tells if transaction is included into stored block.
+	IsTransactionConfirmed(id []byte) (bool, error)
+
+	// AddressTransactions returns at most limit transactions involving address from the newest to the oldest,
+	// starting from the one following transaction with ID after, or from the newest one if after is nil.
+	// It fails if state is created without StoreAddressTransactions parameter.
+	AddressTransactions(addr proto.Address, after []byte, limit int) ([]*TransactionInfo, error)
+
+	// Leases.
+	// LeaseInfo returns lease by ID of transaction which created it, NotFoundError if there is no such lease.
+	LeaseInfo(leaseID crypto.Digest) (*LeaseInfo, error)
+	// ActiveLeases returns active leases sent or received by address.
+	ActiveLeases(addr proto.Address) ([]*LeaseInfo, error)
+
+	// Orders volumes.
+	// OrderVolume returns zero volume for orders which have not been filled yet.
+	OrderVolume(orderID []byte) (*OrderVolume, error)
+
 	Close() error
 }
 
+// ScriptInfo describes account's script, same as scriptInfo of Scala node's REST API.
+type ScriptInfo struct {
+	Script     proto.Script
+	Complexity uint64
+	// ExtraFee is added to the fee of transactions sent from scripted account.
+	ExtraFee uint64
+}
+
+// AssetInfo describes asset.
+// Non-fungible tokens are assets with quantity of 1, no decimals and not reissuable.
+type AssetInfo struct {
+	ID          crypto.Digest
+	Issuer      crypto.PublicKey
+	Name        string
+	Description string
+	Decimals    int8
+	Quantity    uint64
+	Reissuable  bool
+}
+
+// AssetBalance is balance of address in asset.
+type AssetBalance struct {
+	Asset   AssetInfo
+	Balance uint64
+}
+
+// AssetHolder is address and its balance in asset.
+type AssetHolder struct {
+	Address proto.Address
+	Balance uint64
+}
+
+// LeaseInfo describes lease of Waves.
+type LeaseInfo struct {
+	ID        crypto.Digest
+	Sender    proto.Address
+	Recipient proto.Address
+	Amount    uint64
+	// Height is height of block which includes lease transaction.
+	Height   uint64
+	IsActive bool
+}
+
+// TransactionInfo is transaction with ID and height of block which includes it.
+type TransactionInfo struct {
+	Transaction proto.Transaction
+	BlockID     crypto.Signature
+	Height      uint64
+}
+
+// OrderVolume is amount and matcher fee of order which have been filled by exchanges.
+type OrderVolume struct {
+	FilledAmount uint64
+	FilledFee    uint64
+}
+
 // NewState() creates State.
 // dataDir is path to directory to store all data, it's also possible to provide folder with existing data,
 // and state will try to sync and use it in this case.
@@ -139,8 +247,21 @@ type ValidationParams struct {
 type StateParams struct {
 	StorageParams
 	ValidationParams
+	// StoreAddressTransactions enables index of transactions by addresses they involve,
+	// it is off by default to save space and time of import for nodes which don't need history of addresses.
+	// The parameter is saved in DB on creation, state with existing DB fails to open if it differs.
+	StoreAddressTransactions bool
+	// StoreFullHistory keeps all the records of blockchain entities instead of those which are needed for rollback,
+	// it allows to get balances at any height at cost of much bigger database.
+	// History of entity is stored under single key and rewritten on each change, so time of import grows
+	// quadratically with number of changes of frequently updated entities, like balances of exchanges.
+	// The parameter is saved in DB on creation, state with existing DB fails to open if it differs.
+	StoreFullHistory bool
 }
 
 func DefaultStateParams() StateParams {
-	return StateParams{DefaultStorageParams(), ValidationParams{runtime.NumCPU() * 2}}
+	return StateParams{
+		StorageParams:    DefaultStorageParams(),
+		ValidationParams: ValidationParams{runtime.NumCPU() * 2},
+	}
 }
@@ -14,6 +14,8 @@ const (
 	// maxQuantityLen is maximum length of quantity (it's represented as big.Int) bytes in asset history records.
 	maxQuantityLen  = 16
 	assetRecordSize = maxQuantityLen + 1 + 4
+
+	sponsorshipRecordSize = 8 + 4
 )
 
 type assetRecord struct {
@@ -30,6 +32,11 @@ func (ai *assetInfo) equal(ai1 *assetInfo) bool {
 	return ai.assetChangeableInfo.equal(&ai1.assetChangeableInfo) && (ai.assetConstInfo == ai1.assetConstInfo)
 }
 
+// isNFT tells if asset is non-fungible token: single indivisible and not reissuable unit.
+func (ai *assetInfo) isNFT() bool {
+	return ai.quantity.Cmp(big.NewInt(1)) == 0 && ai.decimals == 0 && !ai.reissuable
+}
+
 // assetConstInfo is part of asset info which is constant.
 type assetConstInfo struct {
 	issuer      crypto.PublicKey
@@ -121,6 +128,28 @@ func (r *assetHistoryRecord) unmarshalBinary(data []byte) error {
 	return nil
 }
 
+// sponsorshipRecord stores minimal sponsored fee of asset (assetCost), zero means that sponsorship is cancelled.
+type sponsorshipRecord struct {
+	assetCost uint64
+	blockNum  uint32
+}
+
+func (r *sponsorshipRecord) marshalBinary() ([]byte, error) {
+	res := make([]byte, sponsorshipRecordSize)
+	binary.BigEndian.PutUint64(res[:8], r.assetCost)
+	binary.BigEndian.PutUint32(res[8:], r.blockNum)
+	return res, nil
+}
+
+func (r *sponsorshipRecord) unmarshalBinary(data []byte) error {
+	if len(data) != sponsorshipRecordSize {
+		return errors.New("invalid data size")
+	}
+	r.assetCost = binary.BigEndian.Uint64(data[:8])
+	r.blockNum = binary.BigEndian.Uint32(data[8:])
+	return nil
+}
+
 type assets struct {
 	db      keyvalue.KeyValue
 	dbBatch keyvalue.Batch
@@ -214,7 +243,7 @@ func (a *assets) constInfo(assetID crypto.Digest) (*assetConstInfo, error) {
 	constKey := assetConstKey{assetID: assetID}
 	constInfoBytes, err := a.db.Get(constKey.bytes())
 	if err != nil {
-		return nil, errors.Errorf("failed to retrieve const info for given asset: %v\n", err)
+		return nil, errors.Wrap(err, "failed to retrieve const info for given asset")
 	}
 	var constInfo assetConstInfo
 	if err := constInfo.unmarshalBinary(constInfoBytes); err != nil {
@@ -277,6 +306,81 @@ func (a *assets) assetInfo(assetID crypto.Digest, filter bool) (*assetInfo, erro
 	return &assetInfo{assetConstInfo: *constInfo, assetChangeableInfo: record.assetChangeableInfo}, nil
 }
 
+func (a *assets) sponsorAsset(assetID crypto.Digest, assetCost uint64, blockID crypto.Signature) error {
+	key := sponsorshipKey{assetID: assetID}
+	blockNum, err := a.stateDB.blockIdToNum(blockID)
+	if err != nil {
+		return err
+	}
+	r := &sponsorshipRecord{assetCost, blockNum}
+	recordBytes, err := r.marshalBinary()
+	if err != nil {
+		return errors.Errorf("failed to marshal record: %v\n", err)
+	}
+	return a.hs.set(sponsorship, key.bytes(), recordBytes)
+}
+
+// Newest minimal sponsored fee of asset, zero for assets which are not sponsored.
+func (a *assets) newestAssetCost(assetID crypto.Digest, filter bool) (uint64, error) {
+	key := sponsorshipKey{assetID: assetID}
+	recordBytes, err := a.hs.getFresh(sponsorship, key.bytes(), filter)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var record sponsorshipRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return 0, errors.Errorf("failed to unmarshal record: %v\n", err)
+	}
+	return record.assetCost, nil
+}
+
+func (a *assets) newestIsSponsored(assetID crypto.Digest, filter bool) (bool, error) {
+	assetCost, err := a.newestAssetCost(assetID, filter)
+	if err != nil {
+		return false, err
+	}
+	return assetCost > 0, nil
+}
+
+// Stable minimal sponsored fee of asset from DB.
+func (a *assets) assetCost(assetID crypto.Digest, filter bool) (uint64, error) {
+	key := sponsorshipKey{assetID: assetID}
+	recordBytes, err := a.hs.get(sponsorship, key.bytes(), filter)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var record sponsorshipRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return 0, errors.Errorf("failed to unmarshal record: %v\n", err)
+	}
+	return record.assetCost, nil
+}
+
+// newestSponsoredAssetToWaves converts amount of sponsored asset to Waves.
+func (a *assets) newestSponsoredAssetToWaves(assetID crypto.Digest, assetAmount uint64, filter bool) (uint64, error) {
+	assetCost, err := a.newestAssetCost(assetID, filter)
+	if err != nil {
+		return 0, err
+	}
+	if assetCost == 0 {
+		return 0, errors.New("asset is not sponsored")
+	}
+	var wavesAmount big.Int
+	wavesAmount.SetUint64(assetAmount)
+	wavesAmount.Mul(&wavesAmount, big.NewInt(feeUnit))
+	wavesAmount.Div(&wavesAmount, new(big.Int).SetUint64(assetCost))
+	if !wavesAmount.IsInt64() {
+		return 0, errors.New("waves amount overflows int64")
+	}
+	return wavesAmount.Uint64(), nil
+}
+
 func (a *assets) reset() {
 	a.freshConstInfo = make(map[crypto.Digest]assetConstInfo)
 }
@@ -113,3 +113,57 @@ func TestBurnAsset(t *testing.T) {
 		t.Errorf("Assets after burn differ.")
 	}
 }
+
+func TestSponsorAsset(t *testing.T) {
+	to, path, err := createAssets()
+	assert.NoError(t, err, "createAssets() failed")
+
+	defer func() {
+		err = to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	to.stor.addBlock(t, blockID0)
+	assetID, err := crypto.NewDigestFromBytes(bytes.Repeat([]byte{0xff}, crypto.DigestSize))
+	assert.NoError(t, err, "failed to create digest from bytes")
+	isSponsored, err := to.assets.newestIsSponsored(assetID, true)
+	assert.NoError(t, err, "newestIsSponsored() failed")
+	assert.Equal(t, false, isSponsored)
+	assetCost := uint64(1000)
+	err = to.assets.sponsorAsset(assetID, assetCost, blockID0)
+	assert.NoError(t, err, "sponsorAsset() failed")
+	isSponsored, err = to.assets.newestIsSponsored(assetID, true)
+	assert.NoError(t, err, "newestIsSponsored() failed")
+	assert.Equal(t, true, isSponsored)
+	wavesAmount, err := to.assets.newestSponsoredAssetToWaves(assetID, 2000, true)
+	assert.NoError(t, err, "newestSponsoredAssetToWaves() failed")
+	assert.Equal(t, uint64(2000*feeUnit/assetCost), wavesAmount)
+	to.stor.flush(t)
+	cost, err := to.assets.assetCost(assetID, true)
+	assert.NoError(t, err, "assetCost() failed")
+	assert.Equal(t, assetCost, cost)
+
+	// Cancel sponsorship.
+	to.stor.addBlock(t, blockID1)
+	err = to.assets.sponsorAsset(assetID, 0, blockID1)
+	assert.NoError(t, err, "sponsorAsset() failed")
+	to.stor.flush(t)
+	cost, err = to.assets.assetCost(assetID, true)
+	assert.NoError(t, err, "assetCost() failed")
+	assert.Equal(t, uint64(0), cost)
+	_, err = to.assets.newestSponsoredAssetToWaves(assetID, 2000, true)
+	assert.Error(t, err, "newestSponsoredAssetToWaves() did not fail with asset which is not sponsored")
+}
+
+func TestIsNFT(t *testing.T) {
+	asset := defaultAssetInfo(false)
+	assert.False(t, asset.isNFT())
+	asset.quantity = *big.NewInt(1)
+	assert.False(t, asset.isNFT())
+	asset.decimals = 0
+	assert.True(t, asset.isNFT())
+	asset.reissuable = true
+	assert.False(t, asset.isNFT())
+}
@@ -91,12 +91,13 @@ func (r *assetBalanceRecord) unmarshalBinary(data []byte) error {
 
 type balances struct {
 	db      keyvalue.IterableKeyVal
+	dbBatch keyvalue.Batch
 	stateDB *stateDB
 	hs      *historyStorage
 }
 
-func newBalances(db keyvalue.IterableKeyVal, stateDB *stateDB, hs *historyStorage) (*balances, error) {
-	return &balances{db, stateDB, hs}, nil
+func newBalances(db keyvalue.IterableKeyVal, dbBatch keyvalue.Batch, stateDB *stateDB, hs *historyStorage) (*balances, error) {
+	return &balances{db, dbBatch, stateDB, hs}, nil
 }
 
 func (s *balances) cancelAllLeases() error {
@@ -249,6 +250,138 @@ func (s *balances) assetBalance(addr proto.Address, asset []byte, filter bool) (
 	return record.balance, nil
 }
 
+// iterateAssetBalances calls f for non-zero asset balances of address in order of asset IDs,
+// starting from the asset following after if it is not nil. Iteration stops when f returns false.
+func (s *balances) iterateAssetBalances(addr proto.Address, after []byte, filter bool, f func(assetID crypto.Digest, balance uint64) (bool, error)) error {
+	iter, err := s.db.NewKeyIterator(addressAssetBalancesPrefix(addr))
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+
+	ok := iter.Next()
+	if after != nil {
+		key := assetBalanceKey{address: addr, asset: after}
+		afterKey := key.bytes()
+		ok = iter.Seek(afterKey)
+		if ok && bytes.Equal(iter.Key(), afterKey) {
+			ok = iter.Next()
+		}
+	}
+	for ; ok; ok = iter.Next() {
+		// Address is known, so only ID of asset is taken from the key.
+		assetID, err := crypto.NewDigestFromBytes(iter.Key()[1+proto.AddressSize:])
+		if err != nil {
+			return err
+		}
+		balance, err := s.assetBalance(addr, assetID.Bytes(), filter)
+		if err != nil {
+			return err
+		}
+		if balance == 0 {
+			continue
+		}
+		next, err := f(assetID, balance)
+		if err != nil {
+			return err
+		}
+		if !next {
+			break
+		}
+	}
+	return iter.Error()
+}
+
+// assetBalanceAtHeight returns balance of address in asset at the given height.
+// Only heights which are not cut from history are supported.
+func (s *balances) assetBalanceAtHeight(addr proto.Address, asset []byte, height uint64, filter bool) (uint64, error) {
+	key := assetBalanceKey{address: addr, asset: asset}
+	recordBytes, err := s.hs.recordAtHeight(assetBalance, key.bytes(), height, filter)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist {
+		// Address didn't have balance at this height.
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var record assetBalanceRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return 0, err
+	}
+	return record.balance, nil
+}
+
+// assetHoldersBatchSize is the number of asset holders which are flushed at once while index is built.
+const assetHoldersBatchSize = 100000
+
+// buildAssetHolders adds holders of assets to index from keys of asset balances.
+func buildAssetHolders(db keyvalue.IterableKeyVal) error {
+	batch, err := db.NewBatch()
+	if err != nil {
+		return err
+	}
+	iter, err := db.NewKeyIterator([]byte{assetBalanceKeyPrefix})
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+
+	n := 0
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != assetBalanceKeySize {
+			return errors.New("invalid asset balance key size")
+		}
+		var holderKey assetHolderKey
+		copy(holderKey.address[:], key[1:1+proto.AddressSize])
+		copy(holderKey.assetID[:], key[1+proto.AddressSize:])
+		batch.Put(holderKey.bytes(), nil)
+		n++
+		if n == assetHoldersBatchSize {
+			if err := db.Flush(batch); err != nil {
+				return err
+			}
+			n = 0
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return db.Flush(batch)
+}
+
+// iterateAssetHolders calls f for addresses which have ever had balance in asset in order of addresses,
+// starting from the address following after if it is not nil. Iteration stops when f returns false.
+func (s *balances) iterateAssetHolders(assetID crypto.Digest, after *proto.Address, f func(addr proto.Address) (bool, error)) error {
+	iter, err := s.db.NewKeyIterator(assetHoldersPrefix(assetID))
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+
+	ok := iter.Next()
+	if after != nil {
+		key := assetHolderKey{assetID: assetID, address: *after}
+		afterKey := key.bytes()
+		ok = iter.Seek(afterKey)
+		if ok && bytes.Equal(iter.Key(), afterKey) {
+			ok = iter.Next()
+		}
+	}
+	for ; ok; ok = iter.Next() {
+		var addr proto.Address
+		copy(addr[:], iter.Key()[1+crypto.DigestSize:])
+		next, err := f(addr)
+		if err != nil {
+			return err
+		}
+		if !next {
+			break
+		}
+	}
+	return iter.Error()
+}
+
 func (s *balances) wavesRecord(key []byte, filter bool) (*wavesBalanceRecord, error) {
 	recordBytes, err := s.hs.get(wavesBalance, key, filter)
 	if err == keyvalue.ErrNotFound || err == errEmptyHist {
@@ -289,6 +422,12 @@ func (s *balances) setAssetBalance(addr proto.Address, asset []byte, balance uin
 	if err != nil {
 		return err
 	}
+	assetID, err := crypto.NewDigestFromBytes(asset)
+	if err != nil {
+		return err
+	}
+	holderKey := assetHolderKey{assetID: assetID, address: addr}
+	s.dbBatch.Put(holderKey.bytes(), nil)
 	return s.hs.set(assetBalance, key.bytes(), recordBytes)
 }
 
@@ -28,7 +28,7 @@ func createBalances() (*balancesTestObjects, []string, error) {
 	if err != nil {
 		return nil, path, err
 	}
-	balances, err := newBalances(stor.db, stor.stateDB, stor.hs)
+	balances, err := newBalances(stor.db, stor.dbBatch, stor.stateDB, stor.hs)
 	if err != nil {
 		return nil, path, err
 	}
@@ -290,3 +290,175 @@ func TestBalances(t *testing.T) {
 		}
 	}
 }
+
+func TestIterateAssetBalances(t *testing.T) {
+	to, path, err := createBalances()
+	assert.NoError(t, err, "createBalances() failed")
+
+	defer func() {
+		err = to.stor.stateDB.close()
+		assert.NoError(t, err, "failed to close DB")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	to.stor.addBlock(t, blockID0)
+	addr := genAddr(1)
+	for _, tc := range []struct {
+		addr    proto.Address
+		assetID []byte
+		balance uint64
+	}{
+		{addr, genAsset(3), 300},
+		{addr, genAsset(1), 100},
+		{addr, genAsset(2), 0},
+		{addr, genAsset(4), 400},
+		{genAddr(2), genAsset(5), 500},
+	} {
+		err = to.balances.setAssetBalance(tc.addr, tc.assetID, tc.balance, blockID0)
+		assert.NoError(t, err, "setAssetBalance() failed")
+	}
+	to.stor.flush(t)
+
+	type assetBalance struct {
+		assetID []byte
+		balance uint64
+	}
+	collect := func(after []byte, limit int) []assetBalance {
+		var res []assetBalance
+		err := to.balances.iterateAssetBalances(addr, after, true, func(assetID crypto.Digest, balance uint64) (bool, error) {
+			res = append(res, assetBalance{assetID.Bytes(), balance})
+			return len(res) < limit, nil
+		})
+		assert.NoError(t, err, "iterateAssetBalances() failed")
+		return res
+	}
+	// Zero balances and balances of other addresses are skipped.
+	all := []assetBalance{{genAsset(1), 100}, {genAsset(3), 300}, {genAsset(4), 400}}
+	assert.Equal(t, all, collect(nil, 10))
+	assert.Equal(t, all[:2], collect(nil, 2))
+	assert.Equal(t, all[1:], collect(genAsset(1), 10))
+	// Iteration starts after asset even if address doesn't have it.
+	assert.Equal(t, all[1:], collect(genAsset(2), 10))
+	assert.Empty(t, collect(genAsset(4), 10))
+}
+
+func TestAssetBalanceAtHeight(t *testing.T) {
+	to, path, err := createBalances()
+	assert.NoError(t, err, "createBalances() failed")
+
+	defer func() {
+		err = to.stor.stateDB.close()
+		assert.NoError(t, err, "failed to close DB")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	// Blocks at heights 1, 2 and 3.
+	ids := genRandBlockIds(t, 3)
+	for _, id := range ids {
+		to.stor.addBlock(t, id)
+	}
+	asset := genAsset(1)
+	assetID, err := crypto.NewDigestFromBytes(asset)
+	assert.NoError(t, err, "NewDigestFromBytes() failed")
+	for _, tc := range []struct {
+		addr    proto.Address
+		balance uint64
+		blockID crypto.Signature
+	}{
+		{genAddr(2), 100, ids[0]},
+		{genAddr(2), 50, ids[2]},
+		{genAddr(1), 30, ids[1]},
+		{genAddr(3), 10, ids[1]},
+		{genAddr(3), 0, ids[2]},
+	} {
+		err = to.balances.setAssetBalance(tc.addr, asset, tc.balance, tc.blockID)
+		assert.NoError(t, err, "setAssetBalance() failed")
+	}
+	err = to.balances.setAssetBalance(genAddr(4), genAsset(2), 100, ids[0])
+	assert.NoError(t, err, "setAssetBalance() failed")
+	to.stor.flush(t)
+
+	for _, tc := range []struct {
+		addr    proto.Address
+		height  uint64
+		balance uint64
+	}{
+		{genAddr(1), 1, 0},
+		{genAddr(1), 2, 30},
+		{genAddr(1), 3, 30},
+		{genAddr(2), 1, 100},
+		{genAddr(2), 2, 100},
+		{genAddr(2), 3, 50},
+		{genAddr(3), 2, 10},
+		{genAddr(3), 3, 0},
+		{genAddr(4), 3, 0},
+	} {
+		balance, err := to.balances.assetBalanceAtHeight(tc.addr, asset, tc.height, true)
+		assert.NoError(t, err, "assetBalanceAtHeight() failed")
+		assert.Equal(t, tc.balance, balance, "address %d at height %d", tc.addr[0], tc.height)
+	}
+
+	collect := func(after *proto.Address, limit int) []proto.Address {
+		var res []proto.Address
+		err := to.balances.iterateAssetHolders(assetID, after, func(addr proto.Address) (bool, error) {
+			res = append(res, addr)
+			return len(res) < limit, nil
+		})
+		assert.NoError(t, err, "iterateAssetHolders() failed")
+		return res
+	}
+	// Holders are listed once even if their balances changed several times.
+	holders := []proto.Address{genAddr(1), genAddr(2), genAddr(3)}
+	assert.Equal(t, holders, collect(nil, 10))
+	assert.Equal(t, holders[:2], collect(nil, 2))
+	assert.Equal(t, holders[1:], collect(&holders[0], 10))
+	assert.Empty(t, collect(&holders[2], 10))
+}
+
+func TestBuildAssetHolders(t *testing.T) {
+	to, path, err := createBalances()
+	assert.NoError(t, err, "createBalances() failed")
+
+	defer func() {
+		err = to.stor.stateDB.close()
+		assert.NoError(t, err, "failed to close DB")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	to.stor.addBlock(t, blockID0)
+	asset := genAsset(1)
+	assetID, err := crypto.NewDigestFromBytes(asset)
+	assert.NoError(t, err, "NewDigestFromBytes() failed")
+	holders := []proto.Address{genAddr(1), genAddr(2), genAddr(3)}
+	for _, addr := range holders {
+		err = to.balances.setAssetBalance(addr, asset, 10, blockID0)
+		assert.NoError(t, err, "setAssetBalance() failed")
+	}
+	err = to.balances.setAssetBalance(genAddr(4), genAsset(2), 10, blockID0)
+	assert.NoError(t, err, "setAssetBalance() failed")
+	to.stor.flush(t)
+
+	collect := func() []proto.Address {
+		var res []proto.Address
+		err := to.balances.iterateAssetHolders(assetID, nil, func(addr proto.Address) (bool, error) {
+			res = append(res, addr)
+			return true, nil
+		})
+		assert.NoError(t, err, "iterateAssetHolders() failed")
+		return res
+	}
+	// Remove index as if balances were stored before it existed.
+	for _, addr := range holders {
+		key := assetHolderKey{assetID: assetID, address: addr}
+		err = to.stor.db.Delete(key.bytes())
+		assert.NoError(t, err, "Delete() failed")
+	}
+	assert.Empty(t, collect())
+
+	err = buildAssetHolders(to.stor.db)
+	assert.NoError(t, err, "buildAssetHolders() failed")
+	assert.Equal(t, holders, collect())
+}
@@ -9,11 +9,6 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/settings"
 )
 
-type blockDiff struct {
-	minerDiff txDiff
-	txDiffs   []txDiff
-}
-
 type blockDiffer struct {
 	stor     *blockchainEntitiesStorage
 	settings *settings.BlockchainSettings
@@ -111,53 +106,74 @@ func (d *blockDiffer) createPrevBlockMinerFeeDiff(prevBlockID crypto.Signature,
 	return diff, nil
 }
 
-func (d *blockDiffer) createTransactionsDiffs(transactions []proto.Transaction, block *proto.BlockHeader, initialisation bool) ([]txDiff, error) {
-	d.curDistr = newFeeDistribution()
-	diffs := make([]txDiff, len(transactions))
-	for i, tx := range transactions {
-		differInfo := &differInfo{initialisation, block.GenPublicKey, block.Timestamp}
-		diff, err := d.handler.createDiffTx(tx, differInfo)
-		if err != nil {
-			return nil, err
-		}
-		diffs[i] = diff
-		d.appendBlockInfoToTxDiff(diffs[i], block)
-		ngActivated, err := d.stor.features.isActivated(int16(settings.NG))
+// appendTxFeeDistribution adds fees of single transaction to current block fee distribution.
+// Fees in sponsored assets are converted to Waves, so miner gets them in Waves.
+func (d *blockDiffer) appendTxFeeDistribution(txDistr *feeDistribution, ngActivated, initialisation bool, height uint64) error {
+	d.curDistr.totalWavesFees += txDistr.totalWavesFees
+	d.curDistr.currentWavesBlockFees += txDistr.currentWavesBlockFees
+	for asset, totalFee := range txDistr.totalFees {
+		wavesFee, sponsored, err := feeInWaves(d.stor, totalFee, proto.OptionalAsset{Present: true, ID: asset}, height, !initialisation)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		if err := d.handler.minerFeeTx(tx, &d.curDistr, ngActivated); err != nil {
-			return nil, err
+		if sponsored {
+			d.curDistr.totalWavesFees += wavesFee
+			d.curDistr.currentWavesBlockFees += calculateCurrentBlockTxFee(wavesFee, ngActivated)
+			continue
 		}
+		d.curDistr.totalFees[asset] += totalFee
+		d.curDistr.currentBlockFees[asset] += txDistr.currentBlockFees[asset]
 	}
-	// Save fee distribution to DB.
-	if err := d.stor.blocksInfo.saveFeeDistribution(block.BlockSignature, &d.curDistr); err != nil {
-		return nil, err
-	}
-	// Update fee distribution.
-	d.prevDistr = d.curDistr
-	d.prevBlockID = block.BlockSignature
-	return diffs, nil
+	return nil
 }
 
-func (d *blockDiffer) createBlockDiff(blockTxs []proto.Transaction, block *proto.BlockHeader, initialisation, hasParent bool) (blockDiff, error) {
-	var diff blockDiff
-	if hasParent {
-		minerDiff, err := d.createPrevBlockMinerFeeDiff(block.Parent, block.GenPublicKey)
-		if err != nil {
-			return blockDiff{}, err
-		}
-		diff.minerDiff = minerDiff
-		d.appendBlockInfoToTxDiff(diff.minerDiff, block)
+// createTransactionDiff creates diff of block's transaction and adds its fees to the block fee distribution.
+func (d *blockDiffer) createTransactionDiff(tx proto.Transaction, block *proto.BlockHeader, height uint64, initialisation bool) (txDiff, error) {
+	differInfo := &differInfo{initialisation, block.GenPublicKey, block.Timestamp, height}
+	diff, err := d.handler.createDiffTx(tx, differInfo)
+	if err != nil {
+		return txDiff{}, err
 	}
-	txDiffs, err := d.createTransactionsDiffs(blockTxs, block, initialisation)
+	d.appendBlockInfoToTxDiff(diff, block)
+	ngActivated, err := d.stor.features.isActivated(int16(settings.NG))
 	if err != nil {
-		return blockDiff{}, err
+		return txDiff{}, err
+	}
+	txDistr := newFeeDistribution()
+	if err := d.handler.minerFeeTx(tx, &txDistr, ngActivated); err != nil {
+		return txDiff{}, err
+	}
+	if err := d.appendTxFeeDistribution(&txDistr, ngActivated, initialisation, height); err != nil {
+		return txDiff{}, err
 	}
-	diff.txDiffs = txDiffs
 	return diff, nil
 }
 
+// createMinerDiff starts new block: it creates diff of miner's fees from the previous block
+// and resets the fee distribution of current block.
+func (d *blockDiffer) createMinerDiff(block *proto.BlockHeader, hasParent bool) (txDiff, error) {
+	d.curDistr = newFeeDistribution()
+	if !hasParent {
+		return txDiff{}, nil
+	}
+	minerDiff, err := d.createPrevBlockMinerFeeDiff(block.Parent, block.GenPublicKey)
+	if err != nil {
+		return txDiff{}, err
+	}
+	d.appendBlockInfoToTxDiff(minerDiff, block)
+	return minerDiff, nil
+}
+
+// finishBlock saves fee distribution of block after all its transactions have been added.
+func (d *blockDiffer) finishBlock(block *proto.BlockHeader) error {
+	if err := d.stor.blocksInfo.saveFeeDistribution(block.BlockSignature, &d.curDistr); err != nil {
+		return err
+	}
+	d.prevDistr = d.curDistr
+	d.prevBlockID = block.BlockSignature
+	return nil
+}
+
 func (d *blockDiffer) reset() {
 	d.curDistr = newFeeDistribution()
 	d.prevDistr = newFeeDistribution()
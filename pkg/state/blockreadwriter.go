@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"os"
 	"path"
+	"sort"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -34,6 +35,7 @@ type blockReadWriter struct {
 	txBounds     []byte
 	headerBounds []byte
 	heightBuf    []byte
+	txHeightBuf  []byte
 
 	// offsetEnd is common for headers and the blockchain, since the limit for any offset length is 8 bytes.
 	offsetEnd                 uint64
@@ -121,6 +123,7 @@ func newBlockReadWriter(
 		headerBounds:      make([]byte, headerOffsetLen*2),
 		blockBounds:       make([]byte, offsetLen*2),
 		heightBuf:         make([]byte, 8),
+		txHeightBuf:       make([]byte, 8),
 		offsetEnd:         uint64(1<<uint(8*offsetLen) - 1),
 		blockchainLen:     blockchainSize,
 		headersLen:        headersSize,
@@ -197,6 +200,9 @@ func (rw *blockReadWriter) writeTransaction(txID []byte, tx []byte) error {
 	binary.LittleEndian.PutUint64(rw.txBounds[rw.offsetLen:], rw.blockchainLen)
 	key := txOffsetKey{txID: txID}
 	rw.dbBatch.Put(key.bytes(), rw.txBounds)
+	binary.LittleEndian.PutUint64(rw.txHeightBuf, rw.height)
+	heightKey := txHeightKey{txID: txID}
+	rw.dbBatch.Put(heightKey.bytes(), rw.txHeightBuf)
 	return nil
 }
 
@@ -286,6 +292,73 @@ func (rw *blockReadWriter) readTransaction(txID []byte) ([]byte, error) {
 	return txBytes, nil
 }
 
+// transactionHeight returns height of block which includes transaction.
+func (rw *blockReadWriter) transactionHeight(txID []byte) (uint64, error) {
+	rw.mtx.RLock()
+	defer rw.mtx.RUnlock()
+	key := txHeightKey{txID: txID}
+	heightBytes, err := rw.db.Get(key.bytes())
+	if err == keyvalue.ErrNotFound {
+		// Transaction could be written before heights of transactions were stored.
+		return rw.heightByTxOffset(txID)
+	} else if err != nil {
+		return 0, err
+	}
+	if len(heightBytes) != 8 {
+		return 0, errors.New("invalid data size")
+	}
+	return binary.LittleEndian.Uint64(heightBytes) + 1, nil
+}
+
+// heightByTxOffset finds block which includes transaction using binary search by offsets,
+// since offsets of blocks in blockchain file grow with their heights.
+func (rw *blockReadWriter) heightByTxOffset(txID []byte) (uint64, error) {
+	key := txOffsetKey{txID: txID}
+	txBounds, err := rw.db.Get(key.bytes())
+	if err != nil {
+		return 0, err
+	}
+	txStart := binary.LittleEndian.Uint64(txBounds[:rw.offsetLen])
+	height, err := rw.getHeight()
+	if err != nil {
+		return 0, err
+	}
+	// Heights of blockReadWriter start from 0.
+	var searchErr error
+	h := sort.Search(int(height), func(h int) bool {
+		if searchErr != nil {
+			return true
+		}
+		blockEnd, err := rw.blockEndByHeight(uint64(h))
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return txStart < blockEnd
+	})
+	if searchErr != nil {
+		return 0, searchErr
+	}
+	if h == int(height) {
+		return 0, keyvalue.ErrNotFound
+	}
+	return uint64(h) + 1, nil
+}
+
+func (rw *blockReadWriter) blockEndByHeight(height uint64) (uint64, error) {
+	idBytes := make([]byte, crypto.SignatureSize)
+	if _, err := rw.blockHeight2ID.ReadAt(idBytes, int64(height*crypto.SignatureSize)); err != nil {
+		return 0, err
+	}
+	var key blockOffsetKey
+	copy(key.blockID[:], idBytes)
+	blockInfo, err := rw.db.Get(key.bytes())
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(blockInfo[rw.offsetLen : rw.offsetLen*2]), nil
+}
+
 func (rw *blockReadWriter) readBlockHeader(blockID crypto.Signature) ([]byte, error) {
 	rw.mtx.RLock()
 	defer rw.mtx.RUnlock()
@@ -380,6 +453,10 @@ func (rw *blockReadWriter) cleanIDs(oldHeight, newBlockchainLen uint64) error {
 		if err := rw.db.Delete(key.bytes()); err != nil {
 			return err
 		}
+		heightKey := txHeightKey{txID: txID}
+		if err := rw.db.Delete(heightKey.bytes()); err != nil {
+			return err
+		}
 	}
 	return nil
 }
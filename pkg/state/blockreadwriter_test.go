@@ -217,7 +217,7 @@ func writeBlocks(ctx context.Context, rw *blockReadWriter, blocks []proto.Block,
 				close(readTasks)
 				return err
 			}
-			task = &readTask{taskType: readTx, txID: txID, correctResult: transaction[:n+4]}
+			task = &readTask{taskType: readTx, txID: txID, height: uint64(height), correctResult: transaction[:n+4]}
 			tasksBuf = append(tasksBuf, task)
 			transaction = transaction[4+n:]
 		}
@@ -276,6 +276,13 @@ func testReader(rw *blockReadWriter, readTasks <-chan *readTask) error {
 			if bytes.Compare(task.correctResult, tx) != 0 {
 				return errors.New("Transaction bytes are not equal.")
 			}
+			height, err := rw.transactionHeight(task.txID)
+			if err != nil {
+				return err
+			}
+			if height != task.height {
+				return errors.Errorf("Transaction height %d is not equal to %d.", height, task.height)
+			}
 		case getIDByHeight:
 			id, err := rw.blockIDByHeight(task.height)
 			if err != nil {
@@ -436,3 +443,62 @@ func TestSimultaneousReadDelete(t *testing.T) {
 		t.Fatalf("Failed to remove blocks: %v", err)
 	}
 }
+
+func TestTransactionHeightWithoutHeightKey(t *testing.T) {
+	rw, path, err := createBlockReadWriter(8, 8)
+	if err != nil {
+		t.Fatalf("createBlockReadWriter: %v", err)
+	}
+
+	defer func() {
+		if err := rw.close(); err != nil {
+			t.Fatalf("Failed to close blockReadWriter: %v", err)
+		}
+		if err := rw.db.Close(); err != nil {
+			t.Fatalf("Failed to close DB: %v", err)
+		}
+		if err := util.CleanTemporaryDirs(path); err != nil {
+			t.Fatalf("Failed to clean test data dirs: %v", err)
+		}
+	}()
+
+	blocks, err := readRealBlocks(t, blocksPath(t), blocksNumber)
+	if err != nil {
+		t.Fatalf("Can not read blocks from blockchain file: %v", err)
+	}
+	txHeights := make(map[string]uint64)
+	for i, block := range blocks {
+		writeBlock(t, rw, &block)
+		transaction := block.Transactions
+		for j := 0; j < block.TransactionCount; j++ {
+			n := int(binary.BigEndian.Uint32(transaction[0:4]))
+			tx, err := proto.BytesToTransaction(transaction[4 : n+4])
+			if err != nil {
+				t.Fatalf("Can not unmarshal tx: %v", err)
+			}
+			txID, err := tx.GetID()
+			if err != nil {
+				t.Fatalf("tx.GetID(): %v\n", err)
+			}
+			txHeights[string(txID)] = uint64(i + 1)
+			transaction = transaction[4+n:]
+		}
+	}
+	for txID, height := range txHeights {
+		// Remove height of transaction as if it was written before heights were stored.
+		key := txHeightKey{txID: []byte(txID)}
+		if err := rw.db.Delete(key.bytes()); err != nil {
+			t.Fatalf("Delete(): %v", err)
+		}
+		res, err := rw.transactionHeight([]byte(txID))
+		if err != nil {
+			t.Fatalf("transactionHeight(): %v", err)
+		}
+		if res != height {
+			t.Errorf("Transaction height %d is not equal to %d.", res, height)
+		}
+	}
+	if _, err := rw.transactionHeight(make([]byte, crypto.DigestSize)); err != keyvalue.ErrNotFound {
+		t.Errorf("transactionHeight() did not fail with unknown transaction: %v", err)
+	}
+}
@@ -193,7 +193,7 @@ func createStorageObjects() (*storageObjects, []string, error) {
 	if err != nil {
 		return nil, res, err
 	}
-	hs, err := newHistoryStorage(db, dbBatch, rw, stateDB)
+	hs, err := newHistoryStorage(db, dbBatch, rw, stateDB, false)
 	if err != nil {
 		return nil, res, err
 	}
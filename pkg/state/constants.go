@@ -23,3 +23,18 @@ const (
 	// DefaultHeaderOffsetLen is the amount of bytes needed to store offset of headers in headers file.
 	DefaultHeaderOffsetLen = 8
 )
+
+const (
+	// Fee unit, used to convert fees in sponsored assets to Waves.
+	feeUnit = 100000
+	// Extra fee for transactions sent from scripted accounts.
+	scriptExtraFee = 400000
+
+	// Maximum number of data entries written by DApp callable function.
+	maxWriteSetSize = 100
+	// Maximum number of transfers made by DApp callable function.
+	maxTransferSetSize = 10
+
+	// Number of compiled scripts kept by script caller.
+	scriptCacheSize = 1000
+)
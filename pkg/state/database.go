@@ -226,6 +226,74 @@ func (s *stateDB) getHeight() (uint64, error) {
 	return binary.LittleEndian.Uint64(dbHeightBytes), nil
 }
 
+// storedParams are parameters of state which define what is stored in DB,
+// they are saved on creation of DB and can't be changed afterwards.
+type storedParams struct {
+	addressTransactions bool
+	fullHistory         bool
+}
+
+func (p *storedParams) marshalBinary() []byte {
+	var flags byte
+	if p.addressTransactions {
+		flags |= 1
+	}
+	if p.fullHistory {
+		flags |= 2
+	}
+	return []byte{flags}
+}
+
+func (p *storedParams) unmarshalBinary(data []byte) error {
+	if len(data) != 1 {
+		return errors.New("invalid data size")
+	}
+	p.addressTransactions = data[0]&1 != 0
+	p.fullHistory = data[0]&2 != 0
+	return nil
+}
+
+func (p *storedParams) check(params *storedParams) error {
+	if p.addressTransactions != params.addressTransactions {
+		return errors.Errorf("DB is created with StoreAddressTransactions=%v, it can't be changed without reimport", p.addressTransactions)
+	}
+	if p.fullHistory != params.fullHistory {
+		return errors.Errorf("DB is created with StoreFullHistory=%v, it can't be changed without reimport", p.fullHistory)
+	}
+	return nil
+}
+
+// checkParams compares parameters with those which existing DB was created with.
+// DBs created before parameters were saved are treated as created without any optional data.
+// It returns false if parameters are not saved yet, they should be saved by saveParams then.
+func (s *stateDB) checkParams(params *storedParams) (bool, error) {
+	paramsBytes, err := s.db.Get([]byte{stateParamsKeyPrefix})
+	if err != nil && err != keyvalue.ErrNotFound {
+		return false, err
+	}
+	if err == nil {
+		var stored storedParams
+		if err := stored.unmarshalBinary(paramsBytes); err != nil {
+			return false, err
+		}
+		return true, stored.check(params)
+	}
+	height, err := s.getHeight()
+	if err != nil {
+		return false, err
+	}
+	if height != 0 {
+		if err := (&storedParams{}).check(params); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+func (s *stateDB) saveParams(params *storedParams) error {
+	return s.db.Put([]byte{stateParamsKeyPrefix}, params.marshalBinary())
+}
+
 func (s *stateDB) calculateNewRollbackMinHeight(newHeight uint64) (uint64, error) {
 	prevRollbackMinHeight, err := s.getRollbackMinHeight()
 	if err != nil {
@@ -135,24 +135,6 @@ func (s *diffStorage) saveTxDiff(diff txDiff) error {
 	}
 	return nil
 }
-func (s *diffStorage) saveTransactionsDiffs(diffs []txDiff) error {
-	for _, diff := range diffs {
-		if err := s.saveTxDiff(diff); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (s *diffStorage) saveBlockDiff(diff blockDiff) error {
-	if err := s.saveTxDiff(diff.minerDiff); err != nil {
-		return err
-	}
-	if err := s.saveTransactionsDiffs(diff.txDiffs); err != nil {
-		return err
-	}
-	return nil
-}
 
 func (s *diffStorage) changesByKeys(keys []string) ([]balanceChanges, error) {
 	changes := make([]balanceChanges, len(keys))
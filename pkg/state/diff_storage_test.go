@@ -7,28 +7,33 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 )
 
-func createBlockDiff(blockID crypto.Signature) blockDiff {
-	return blockDiff{
-		minerDiff: txDiff{testGlobal.minerInfo.wavesKey: balanceDiff{minBalance: 60, balance: 60, blockID: blockID}},
-		txDiffs: []txDiff{
-			{
-				testGlobal.minerInfo.wavesKey:     balanceDiff{minBalance: 20, balance: 20, blockID: blockID},
-				testGlobal.recipientInfo.wavesKey: balanceDiff{minBalance: -50, balance: -50, leaseOut: 200, blockID: blockID},
-			},
-			{
-				testGlobal.minerInfo.wavesKey:     balanceDiff{minBalance: 20, balance: 20, blockID: blockID},
-				testGlobal.recipientInfo.wavesKey: balanceDiff{minBalance: 500, balance: 500, blockID: blockID},
-				testGlobal.senderInfo.wavesKey:    balanceDiff{minBalance: -550, balance: -550, blockID: blockID},
-			},
+// createBlockDiffs returns miner diff and diffs of transactions of the block.
+func createBlockDiffs(blockID crypto.Signature) []txDiff {
+	return []txDiff{
+		{testGlobal.minerInfo.wavesKey: balanceDiff{minBalance: 60, balance: 60, blockID: blockID}},
+		{
+			testGlobal.minerInfo.wavesKey:     balanceDiff{minBalance: 20, balance: 20, blockID: blockID},
+			testGlobal.recipientInfo.wavesKey: balanceDiff{minBalance: -50, balance: -50, leaseOut: 200, blockID: blockID},
 		},
+		{
+			testGlobal.minerInfo.wavesKey:     balanceDiff{minBalance: 20, balance: 20, blockID: blockID},
+			testGlobal.recipientInfo.wavesKey: balanceDiff{minBalance: 500, balance: 500, blockID: blockID},
+			testGlobal.senderInfo.wavesKey:    balanceDiff{minBalance: -550, balance: -550, blockID: blockID},
+		},
+	}
+}
+
+func saveBlockDiffs(t *testing.T, diffStor *diffStorage, blockID crypto.Signature) {
+	for _, diff := range createBlockDiffs(blockID) {
+		err := diffStor.saveTxDiff(diff)
+		assert.NoError(t, err, "saveTxDiff() failed")
 	}
 }
 
-func TestSaveBlockDiff(t *testing.T) {
+func TestSaveTxDiff(t *testing.T) {
 	diffStor, err := newDiffStorage()
 	assert.NoError(t, err, "newDiffStorage() failed")
-	err = diffStor.saveBlockDiff(createBlockDiff(blockID0))
-	assert.NoError(t, err, "saveBlockDiff() failed")
+	saveBlockDiffs(t, diffStor, blockID0)
 	minerTotalDiff := balanceDiff{minBalance: 60, balance: 100, blockID: blockID0}
 	minerChange := balanceChanges{
 		[]byte(testGlobal.minerInfo.wavesKey),
@@ -47,8 +52,7 @@ func TestSaveBlockDiff(t *testing.T) {
 	correctAllChanges := []balanceChanges{minerChange, recipientChange, senderChange}
 	assert.Equal(t, correctAllChanges, diffStor.allChanges())
 	// Add another block diff to inspect how diffs are appended.
-	err = diffStor.saveBlockDiff(createBlockDiff(blockID1))
-	assert.NoError(t, err, "saveBlockDiff() failed")
+	saveBlockDiffs(t, diffStor, blockID1)
 	minerTotalDiff1 := balanceDiff{minBalance: 60, balance: 200, blockID: blockID1}
 	minerChange = balanceChanges{
 		[]byte(testGlobal.minerInfo.wavesKey),
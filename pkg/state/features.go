@@ -212,6 +212,22 @@ func (f *features) activationHeight(featureID int16) (uint64, error) {
 	return record.activationHeight, nil
 }
 
+// isActivatedForWindow checks that at given height the feature has been active for at least one activation window.
+func (f *features) isActivatedForWindow(featureID int16, height uint64) (bool, error) {
+	activated, err := f.isActivated(featureID)
+	if err != nil {
+		return false, err
+	}
+	if !activated {
+		return false, nil
+	}
+	activationHeight, err := f.activationHeight(featureID)
+	if err != nil {
+		return false, err
+	}
+	return height >= activationHeight+f.settings.ActivationWindowSize(activationHeight), nil
+}
+
 func (f *features) activationBlock(featureID int16) (crypto.Signature, error) {
 	record, err := f.activatedFeaturesRecord(featureID)
 	if err != nil {
@@ -0,0 +1,208 @@
+package state
+
+import (
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+const (
+	// Fee of NFT issue is divided by this value after ReduceNFTFee feature activation.
+	nftFeeDivider = 1000
+	// Size of Data transaction in bytes which costs one fee unit.
+	dataTxFeeChunkSize = 1024
+)
+
+// feeConstants are base minimal fees of transactions in fee units.
+var feeConstants = map[proto.TransactionTypeVersion]uint64{
+	{Type: proto.GenesisTransaction, Version: 1}:        0,
+	{Type: proto.PaymentTransaction, Version: 1}:        1,
+	{Type: proto.TransferTransaction, Version: 1}:       1,
+	{Type: proto.TransferTransaction, Version: 2}:       1,
+	{Type: proto.IssueTransaction, Version: 1}:          1000,
+	{Type: proto.IssueTransaction, Version: 2}:          1000,
+	{Type: proto.ReissueTransaction, Version: 1}:        1000,
+	{Type: proto.ReissueTransaction, Version: 2}:        1000,
+	{Type: proto.BurnTransaction, Version: 1}:           1,
+	{Type: proto.BurnTransaction, Version: 2}:           1,
+	{Type: proto.ExchangeTransaction, Version: 1}:       3,
+	{Type: proto.ExchangeTransaction, Version: 2}:       3,
+	{Type: proto.LeaseTransaction, Version: 1}:          1,
+	{Type: proto.LeaseTransaction, Version: 2}:          1,
+	{Type: proto.LeaseCancelTransaction, Version: 1}:    1,
+	{Type: proto.LeaseCancelTransaction, Version: 2}:    1,
+	{Type: proto.CreateAliasTransaction, Version: 1}:    1,
+	{Type: proto.CreateAliasTransaction, Version: 2}:    1,
+	{Type: proto.MassTransferTransaction, Version: 1}:   1,
+	{Type: proto.DataTransaction, Version: 1}:           1,
+	{Type: proto.SetScriptTransaction, Version: 1}:      10,
+	{Type: proto.SponsorshipTransaction, Version: 1}:    1000,
+	{Type: proto.SetAssetScriptTransaction, Version: 1}: 1000,
+	{Type: proto.InvokeScriptTransaction, Version: 1}:   5,
+}
+
+func isNFT(issue *proto.Issue) bool {
+	return issue.Quantity == 1 && issue.Decimals == 0 && !issue.Reissuable
+}
+
+func issueMinFee(stor *blockchainEntitiesStorage, issue *proto.Issue, baseFee uint64) (uint64, error) {
+	if !isNFT(issue) {
+		return baseFee, nil
+	}
+	nftFeeReduced, err := stor.features.isActivated(int16(settings.ReduceNFTFee))
+	if err != nil {
+		return 0, err
+	}
+	if !nftFeeReduced {
+		return baseFee, nil
+	}
+	return baseFee / nftFeeDivider, nil
+}
+
+// minFeeInUnits returns minimal fee of transaction in fee units, extra fees for scripts are not included.
+func minFeeInUnits(stor *blockchainEntitiesStorage, tx proto.Transaction) (uint64, error) {
+	fee, ok := feeConstants[tx.GetTypeVersion()]
+	if !ok {
+		return 0, errors.Errorf("unknown transaction type %v", tx.GetTypeVersion())
+	}
+	switch tx := tx.(type) {
+	case *proto.IssueV1:
+		return issueMinFee(stor, &tx.Issue, fee)
+	case *proto.IssueV2:
+		return issueMinFee(stor, &tx.Issue, fee)
+	case *proto.MassTransferV1:
+		fee += uint64((len(tx.Transfers) + 1) / 2)
+	case *proto.DataV1:
+		txBytes, err := tx.MarshalBinary()
+		if err != nil {
+			return 0, err
+		}
+		fee = uint64((len(txBytes)-1)/dataTxFeeChunkSize + 1)
+	}
+	return fee, nil
+}
+
+// txAssets returns assets which scripts are run for transaction.
+func txAssets(tx proto.Transaction) ([]proto.OptionalAsset, error) {
+	switch tx := tx.(type) {
+	case *proto.TransferV1:
+		return []proto.OptionalAsset{tx.AmountAsset}, nil
+	case *proto.TransferV2:
+		return []proto.OptionalAsset{tx.AmountAsset}, nil
+	case *proto.ReissueV1:
+		return []proto.OptionalAsset{{Present: true, ID: tx.AssetID}}, nil
+	case *proto.ReissueV2:
+		return []proto.OptionalAsset{{Present: true, ID: tx.AssetID}}, nil
+	case *proto.BurnV1:
+		return []proto.OptionalAsset{{Present: true, ID: tx.AssetID}}, nil
+	case *proto.BurnV2:
+		return []proto.OptionalAsset{{Present: true, ID: tx.AssetID}}, nil
+	case proto.Exchange:
+		order, err := tx.GetSellOrder()
+		if err != nil {
+			return nil, err
+		}
+		return []proto.OptionalAsset{order.AssetPair.AmountAsset, order.AssetPair.PriceAsset}, nil
+	case *proto.MassTransferV1:
+		return []proto.OptionalAsset{tx.Asset}, nil
+	case *proto.SetAssetScriptV1:
+		return []proto.OptionalAsset{{Present: true, ID: tx.AssetID}}, nil
+	case *proto.InvokeScriptV1:
+		assets := make([]proto.OptionalAsset, len(tx.Payments))
+		for i, payment := range tx.Payments {
+			assets[i] = payment.Asset
+		}
+		return assets, nil
+	default:
+		return nil, nil
+	}
+}
+
+// scriptsRunCount returns number of scripts run for transaction: account script of sender and scripts of smart assets.
+func scriptsRunCount(stor *blockchainEntitiesStorage, scheme byte, tx proto.Transaction, filter bool) (uint64, error) {
+	var count uint64
+	if senderPK, ok := txSenderPK(tx); ok {
+		senderAddr, err := proto.NewAddressFromPublicKey(scheme, senderPK)
+		if err != nil {
+			return 0, err
+		}
+		hasVerifier, err := stor.scriptsStorage.newestAccountHasVerifier(senderAddr, filter)
+		if err != nil {
+			return 0, err
+		}
+		if hasVerifier {
+			count++
+		}
+	}
+	assets, err := txAssets(tx)
+	if err != nil {
+		return 0, err
+	}
+	for _, asset := range assets {
+		if !asset.Present {
+			continue
+		}
+		isSmart, err := stor.scriptsStorage.newestIsSmartAsset(asset.ID, filter)
+		if err != nil {
+			return 0, err
+		}
+		if isSmart {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func txFeeAsset(tx proto.Transaction) proto.OptionalAsset {
+	switch tx := tx.(type) {
+	case *proto.TransferV1:
+		return tx.FeeAsset
+	case *proto.TransferV2:
+		return tx.FeeAsset
+	case *proto.InvokeScriptV1:
+		return tx.FeeAsset
+	default:
+		return proto.OptionalAsset{Present: false}
+	}
+}
+
+// minFeeInWaves returns minimal fee of transaction in Waves including extra fees for scripts.
+func minFeeInWaves(stor *blockchainEntitiesStorage, scheme byte, tx proto.Transaction, filter bool) (uint64, error) {
+	feeUnits, err := minFeeInUnits(stor, tx)
+	if err != nil {
+		return 0, err
+	}
+	scriptsRun, err := scriptsRunCount(stor, scheme, tx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return feeUnits*feeUnit + scriptsRun*scriptExtraFee, nil
+}
+
+// checkMinFee checks that transaction fee (or its Waves equivalent for sponsored assets) is not less than minimal fee.
+// Minimal fees are checked since sponsorship has been activated for one activation window, like in Scala implementation.
+func checkMinFee(stor *blockchainEntitiesStorage, scheme byte, tx proto.Transaction, height uint64, filter bool) error {
+	sponsorshipActivated, err := stor.features.isActivatedForWindow(int16(settings.FeeSponsorship), height)
+	if err != nil {
+		return err
+	}
+	if !sponsorshipActivated {
+		return nil
+	}
+	minFee, err := minFeeInWaves(stor, scheme, tx, filter)
+	if err != nil {
+		return err
+	}
+	feeAsset := txFeeAsset(tx)
+	fee, isSponsored, err := feeInWaves(stor, tx.GetFee(), feeAsset, height, filter)
+	if err != nil {
+		return err
+	}
+	if feeAsset.Present && !isSponsored {
+		return errors.New("fee asset is not sponsored")
+	}
+	if fee < minFee {
+		return errors.Errorf("fee %d (in Waves) is less than minimal fee %d", fee, minFee)
+	}
+	return nil
+}
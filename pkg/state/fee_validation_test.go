@@ -0,0 +1,134 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/util"
+)
+
+func TestMinFeeInUnits(t *testing.T) {
+	to, path := createCheckerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	fee, err := minFeeInUnits(to.entities, createTransferV2(t))
+	assert.NoError(t, err, "minFeeInUnits() failed")
+	assert.Equal(t, uint64(1), fee)
+
+	fee, err = minFeeInUnits(to.entities, createMassTransferV1(t, generateMassTransferEntries(t, 3)))
+	assert.NoError(t, err, "minFeeInUnits() failed")
+	assert.Equal(t, uint64(3), fee)
+
+	sk, _ := crypto.GenerateKeyPair([]byte("fee validation test seed"))
+	data := createDataV1(t, 1)
+	err = data.Sign(sk)
+	assert.NoError(t, err, "Sign() failed")
+	fee, err = minFeeInUnits(to.entities, data)
+	assert.NoError(t, err, "minFeeInUnits() failed")
+	assert.Equal(t, uint64(1), fee)
+	// Data transaction is charged per started kilobyte.
+	data = createDataV1(t, 150)
+	err = data.Sign(sk)
+	assert.NoError(t, err, "Sign() failed")
+	fee, err = minFeeInUnits(to.entities, data)
+	assert.NoError(t, err, "minFeeInUnits() failed")
+	assert.Equal(t, uint64(3), fee)
+
+	nft := proto.NewUnsignedIssueV2('W', testGlobal.senderInfo.pk, "name", "description", 1, 0, false, nil, defaultTimestamp, defaultFee)
+	fee, err = minFeeInUnits(to.entities, nft)
+	assert.NoError(t, err, "minFeeInUnits() failed")
+	assert.Equal(t, uint64(1000), fee)
+	activateFeature(t, to.entities, to.stor, int16(settings.ReduceNFTFee))
+	fee, err = minFeeInUnits(to.entities, nft)
+	assert.NoError(t, err, "minFeeInUnits() failed")
+	assert.Equal(t, uint64(1), fee)
+	fee, err = minFeeInUnits(to.entities, createIssueV2(t))
+	assert.NoError(t, err, "minFeeInUnits() failed")
+	assert.Equal(t, uint64(1000), fee)
+}
+
+func TestCheckMinFee(t *testing.T) {
+	to, path := createCheckerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	scheme := settings.MainNetSettings.AddressSchemeCharacter
+	height := uint64(100500)
+	tx := createLeaseV2(t)
+	tx.Fee = 1
+	err := checkMinFee(to.entities, scheme, tx, height, true)
+	assert.NoError(t, err, "checkMinFee() failed before sponsorship activation")
+
+	activateFeature(t, to.entities, to.stor, int16(settings.FeeSponsorship))
+	err = checkMinFee(to.entities, scheme, tx, height, true)
+	assert.Error(t, err, "checkMinFee() did not fail with too small fee")
+	tx.Fee = feeUnit
+	err = checkMinFee(to.entities, scheme, tx, height, true)
+	assert.NoError(t, err, "checkMinFee() failed with minimal fee")
+
+	// Scripted account pays extra fee.
+	to.stor.addBlock(t, blockID0)
+	err = to.entities.scriptsStorage.setAccountScript(testGlobal.senderInfo.addr, &scriptInfo{script: proto.Script{1}, hasVerifier: true}, blockID0)
+	assert.NoError(t, err, "setAccountScript() failed")
+	to.stor.flush(t)
+	err = checkMinFee(to.entities, scheme, tx, height, true)
+	assert.Error(t, err, "checkMinFee() did not fail without extra fee for scripted account")
+	tx.Fee = feeUnit + scriptExtraFee
+	err = checkMinFee(to.entities, scheme, tx, height, true)
+	assert.NoError(t, err, "checkMinFee() failed with extra fee for scripted account")
+
+	// SetAssetScript of smart asset pays extra fee for the asset script as well as for the account script.
+	setAssetScript := createSetAssetScriptV1(t)
+	to.stor.addBlock(t, blockID1)
+	err = to.entities.scriptsStorage.setAssetScript(setAssetScript.AssetID, &scriptInfo{script: setAssetScript.Script}, blockID1)
+	assert.NoError(t, err, "setAssetScript() failed")
+	to.stor.flush(t)
+	setAssetScript.Fee = 1000*feeUnit + scriptExtraFee
+	err = checkMinFee(to.entities, scheme, setAssetScript, height, true)
+	assert.Error(t, err, "checkMinFee() did not fail without extra fee for smart asset")
+	setAssetScript.Fee = 1000*feeUnit + 2*scriptExtraFee
+	err = checkMinFee(to.entities, scheme, setAssetScript, height, true)
+	assert.NoError(t, err, "checkMinFee() failed with minimal fee for SetAssetScript")
+}
+
+func TestCheckMinFeeSponsored(t *testing.T) {
+	to, path := createCheckerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	to.stor.addBlock(t, blockID0)
+	err := to.entities.assets.issueAsset(testGlobal.asset0.asset.ID, defaultAssetInfo(true), blockID0)
+	assert.NoError(t, err, "issueAsset() failed")
+	assetCost := uint64(10)
+	err = to.entities.assets.sponsorAsset(testGlobal.asset0.asset.ID, assetCost, blockID0)
+	assert.NoError(t, err, "sponsorAsset() failed")
+	to.stor.flush(t)
+	activateFeature(t, to.entities, to.stor, int16(settings.FeeSponsorship))
+
+	scheme := settings.MainNetSettings.AddressSchemeCharacter
+	height := uint64(100500)
+	tx := createTransferV2(t)
+	tx.Fee = assetCost - 1
+	err = checkMinFee(to.entities, scheme, tx, height, true)
+	assert.Error(t, err, "checkMinFee() did not fail with too small sponsored fee")
+	tx.Fee = assetCost
+	err = checkMinFee(to.entities, scheme, tx, height, true)
+	assert.NoError(t, err, "checkMinFee() failed with minimal sponsored fee")
+
+	tx.FeeAsset = *testGlobal.asset1.asset
+	err = checkMinFee(to.entities, scheme, tx, height, true)
+	assert.Error(t, err, "checkMinFee() did not fail with fee in asset which is not sponsored")
+}
@@ -7,34 +7,99 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	// variableRecordSize is used for entities whose records do not have fixed size.
+	// Every such record is stored in history with length prefix.
+	variableRecordSize = 0
+	recordLenSize      = 4
+)
+
 type historyFormatter struct {
 	recordSize int
 	idSize     int
-	db         *stateDB
-	rw         *blockReadWriter
+	// fullHistory disables cutting of records which are too old for rollback.
+	fullHistory bool
+	db          *stateDB
+	rw          *blockReadWriter
 }
 
-func newHistoryFormatter(recordSize, idSize int, db *stateDB, rw *blockReadWriter) (*historyFormatter, error) {
-	if recordSize <= 0 || idSize <= 0 {
+func newHistoryFormatter(recordSize, idSize int, fullHistory bool, db *stateDB, rw *blockReadWriter) (*historyFormatter, error) {
+	if recordSize < 0 || idSize <= 0 {
 		return nil, errors.New("invalid record or id size")
 	}
-	if recordSize < idSize {
+	if recordSize != variableRecordSize && recordSize < idSize {
 		return nil, errors.New("recordSize is < idSize")
 	}
-	return &historyFormatter{recordSize: recordSize, idSize: idSize, db: db, rw: rw}, nil
+	return &historyFormatter{recordSize: recordSize, idSize: idSize, fullHistory: fullHistory, db: db, rw: rw}, nil
+}
+
+func (hfmt *historyFormatter) isVariable() bool {
+	return hfmt.recordSize == variableRecordSize
 }
 
 func (hfmt *historyFormatter) getID(record []byte) ([]byte, error) {
+	if hfmt.isVariable() {
+		if len(record) < hfmt.idSize {
+			return nil, errors.New("invalid record size")
+		}
+		return record[len(record)-hfmt.idSize:], nil
+	}
 	if len(record) < hfmt.recordSize {
 		return nil, errors.New("invalid record size")
 	}
 	return record[hfmt.recordSize-hfmt.idSize:], nil
 }
 
+// encode returns record in the form it is stored in history.
+func (hfmt *historyFormatter) encode(record []byte) []byte {
+	if !hfmt.isVariable() {
+		return record
+	}
+	res := make([]byte, recordLenSize+len(record))
+	binary.BigEndian.PutUint32(res[:recordLenSize], uint32(len(record)))
+	copy(res[recordLenSize:], record)
+	return res
+}
+
+// encodedLen returns the number of history bytes occupied by record.
+func (hfmt *historyFormatter) encodedLen(record []byte) int {
+	if !hfmt.isVariable() {
+		return hfmt.recordSize
+	}
+	return recordLenSize + len(record)
+}
+
+// split divides history into separate records.
+func (hfmt *historyFormatter) split(history []byte) ([][]byte, error) {
+	var records [][]byte
+	if !hfmt.isVariable() {
+		if len(history)%hfmt.recordSize != 0 {
+			return nil, errors.Errorf("invalid history size %d for record size %d", len(history), hfmt.recordSize)
+		}
+		for i := hfmt.recordSize; i <= len(history); i += hfmt.recordSize {
+			records = append(records, history[i-hfmt.recordSize:i])
+		}
+		return records, nil
+	}
+	for pos := 0; pos < len(history); {
+		if len(history)-pos < recordLenSize {
+			return nil, errors.New("invalid history: not enough bytes for record length")
+		}
+		size := int(binary.BigEndian.Uint32(history[pos : pos+recordLenSize]))
+		pos += recordLenSize
+		if size < hfmt.idSize || len(history)-pos < size {
+			return nil, errors.Errorf("invalid history: bad record size %d", size)
+		}
+		records = append(records, history[pos:pos+size])
+		pos += size
+	}
+	return records, nil
+}
+
 func (hfmt *historyFormatter) addRecord(history []byte, record []byte) ([]byte, error) {
-	if len(history) < hfmt.recordSize {
+	if len(history) == 0 || (!hfmt.isVariable() && len(history) < hfmt.recordSize) {
 		// History is empty, new record is the first one.
-		return record, nil
+		return hfmt.encode(record), nil
 	}
 	lastRecord, err := hfmt.getLatest(history)
 	if err != nil {
@@ -50,15 +115,28 @@ func (hfmt *historyFormatter) addRecord(history []byte, record []byte) ([]byte,
 	}
 	if bytes.Equal(lastID, curID) {
 		// If the last ID is the same, rewrite the last record.
-		copy(history[len(history)-hfmt.recordSize:], record)
-	} else {
-		// Append new record to the end.
-		history = append(history, record...)
+		if !hfmt.isVariable() {
+			copy(history[len(history)-hfmt.recordSize:], record)
+			return history, nil
+		}
+		history = history[:len(history)-hfmt.encodedLen(lastRecord)]
 	}
+	// Append new record to the end.
+	history = append(history, hfmt.encode(record)...)
 	return history, nil
 }
 
 func (hfmt *historyFormatter) getLatest(history []byte) ([]byte, error) {
+	if hfmt.isVariable() {
+		records, err := hfmt.split(history)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, errors.New("empty history")
+		}
+		return records[len(records)-1], nil
+	}
 	if len(history) < hfmt.recordSize {
 		return nil, errors.Errorf("invalid history size %d, min is %d\n", len(history), hfmt.recordSize)
 	}
@@ -66,8 +144,12 @@ func (hfmt *historyFormatter) getLatest(history []byte) ([]byte, error) {
 }
 
 func (hfmt *historyFormatter) filter(history []byte) ([]byte, error) {
-	for i := len(history); i >= hfmt.recordSize; i -= hfmt.recordSize {
-		record := history[i-hfmt.recordSize : i]
+	records, err := hfmt.split(history)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
 		blockNumBytes, err := hfmt.getID(record)
 		if err != nil {
 			return nil, err
@@ -82,17 +164,23 @@ func (hfmt *historyFormatter) filter(history []byte) ([]byte, error) {
 			break
 		}
 		// Erase invalid record.
-		history = history[:i-hfmt.recordSize]
+		history = history[:len(history)-hfmt.encodedLen(record)]
 	}
 	return history, nil
 }
 
 func (hfmt *historyFormatter) cut(history []byte) ([]byte, error) {
+	if hfmt.fullHistory {
+		return history, nil
+	}
+	records, err := hfmt.split(history)
+	if err != nil {
+		return nil, err
+	}
 	currentHeight := hfmt.rw.recentHeight()
 	firstNeeded := 0
-	for i := hfmt.recordSize; i <= len(history); i += hfmt.recordSize {
-		recordStart := i - hfmt.recordSize
-		record := history[recordStart:i]
+	recordStart := 0
+	for _, record := range records {
 		blockNumBytes, err := hfmt.getID(record)
 		if err != nil {
 			return nil, err
@@ -109,6 +197,7 @@ func (hfmt *historyFormatter) cut(history []byte) ([]byte, error) {
 		if (blockHeight == 0) || (currentHeight-blockHeight > uint64(rollbackMaxBlocks)) {
 			// 1 record BEFORE minHeight is needed.
 			firstNeeded = recordStart
+			recordStart += hfmt.encodedLen(record)
 			continue
 		}
 		break
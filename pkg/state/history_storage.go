@@ -20,6 +20,11 @@ const (
 	featureVote
 	approvedFeature
 	activatedFeature
+	dataEntry
+	accountScript
+	assetScript
+	sponsorship
+	ordersVolume
 
 	idSize = 4
 )
@@ -33,6 +38,11 @@ var recordSizes = map[blockchainEntity]int{
 	featureVote:      votesFeaturesRecordSize,
 	approvedFeature:  approvedFeaturesRecordSize,
 	activatedFeature: activatedFeaturesRecordSize,
+	dataEntry:        variableRecordSize,
+	accountScript:    variableRecordSize,
+	assetScript:      variableRecordSize,
+	sponsorship:      sponsorshipRecordSize,
+	ordersVolume:     orderVolumeRecordSize,
 }
 
 type historyStorage struct {
@@ -49,6 +59,7 @@ func newHistoryStorage(
 	dbBatch keyvalue.Batch,
 	rw *blockReadWriter,
 	stateDB *stateDB,
+	fullHistory bool,
 ) (*historyStorage, error) {
 	stor, err := newLocalStorage()
 	if err != nil {
@@ -56,7 +67,7 @@ func newHistoryStorage(
 	}
 	formatters := make(map[blockchainEntity]historyFormatter)
 	for entity, size := range recordSizes {
-		fmt, err := newHistoryFormatter(size, idSize, stateDB, rw)
+		fmt, err := newHistoryFormatter(size, idSize, fullHistory, stateDB, rw)
 		if err != nil {
 			return nil, err
 		}
@@ -167,14 +178,14 @@ func (hs *historyStorage) recordsInHeightRange(entityType blockchainEntity, key
 	if err != nil {
 		return nil, err
 	}
-	recordSize, ok := recordSizes[entityType]
-	if !ok {
-		return nil, errors.Errorf("unknown entity type %v\n", entityType)
+	historyRecords, err := fmt.split(history)
+	if err != nil {
+		return nil, err
 	}
 	foundAtLeastOne := false
 	var records [][]byte
-	for i := len(history); i >= recordSize; i -= recordSize {
-		recordBytes := history[i-recordSize : i]
+	for i := len(historyRecords) - 1; i >= 0; i-- {
+		recordBytes := historyRecords[i]
 		idBytes, err := fmt.getID(recordBytes)
 		if err != nil {
 			return nil, err
@@ -200,6 +211,46 @@ func (hs *historyStorage) recordsInHeightRange(entityType blockchainEntity, key
 	return records, nil
 }
 
+// recordAtHeight returns the last record of stored history which was added not later than at the given height,
+// errEmptyHist is returned if there is no such record.
+func (hs *historyStorage) recordAtHeight(entityType blockchainEntity, key []byte, height uint64, filter bool) ([]byte, error) {
+	history, err := hs.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	fmt, ok := hs.formatters[entityType]
+	if !ok {
+		return nil, errors.Errorf("unknown entity type %v\n", entityType)
+	}
+	history, err = fmt.normalize(history, filter)
+	if err != nil {
+		return nil, err
+	}
+	records, err := fmt.split(history)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		idBytes, err := fmt.getID(records[i])
+		if err != nil {
+			return nil, err
+		}
+		blockNum := binary.BigEndian.Uint32(idBytes)
+		blockID, err := hs.stateDB.blockNumToId(blockNum)
+		if err != nil {
+			return nil, err
+		}
+		recordHeight, err := hs.rw.heightByBlockID(blockID)
+		if err != nil {
+			return nil, err
+		}
+		if recordHeight <= height {
+			return records[i], nil
+		}
+	}
+	return nil, errEmptyHist
+}
+
 func (hs *historyStorage) reset() {
 	hs.stor.reset()
 }
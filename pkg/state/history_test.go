@@ -26,7 +26,7 @@ func createHistory(recordSize int) (*historyTestObjects, []string, error) {
 	if err != nil {
 		return nil, path, err
 	}
-	fmt, err := newHistoryFormatter(recordSize, idSize, stor.stateDB, stor.rw)
+	fmt, err := newHistoryFormatter(recordSize, idSize, false, stor.stateDB, stor.rw)
 	if err != nil {
 		return nil, path, err
 	}
@@ -125,3 +125,71 @@ func TestNormalize(t *testing.T) {
 		t.Errorf("History formatter did not cut old blocks.")
 	}
 }
+
+func TestAddVariableSizeRecord(t *testing.T) {
+	to, path, err := createHistory(variableRecordSize)
+	assert.NoError(t, err, "createHistory() failed")
+
+	defer func() {
+		err = to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	var history []byte
+	id := make([]byte, idSize)
+	_, err = rand.Read(id)
+	assert.NoError(t, err, "rand.Read() failed")
+	// Test record rewrite with record of different size.
+	firstRecord := append([]byte{0}, id...)
+	history, err = to.fmt.addRecord(history, firstRecord)
+	assert.NoError(t, err, "addRecord() failed")
+	secondRecord := append([]byte{1, 2, 3}, id...)
+	history, err = to.fmt.addRecord(history, secondRecord)
+	assert.NoError(t, err, "addRecord() failed")
+	records, err := to.fmt.split(history)
+	assert.NoError(t, err, "split() failed")
+	assert.Equal(t, [][]byte{secondRecord}, records, "History formatter did not rewrite record with same ID.")
+	// Test record append.
+	_, err = rand.Read(id)
+	assert.NoError(t, err, "rand.Read() failed")
+	thirdRecord := append([]byte{4, 5}, id...)
+	history, err = to.fmt.addRecord(history, thirdRecord)
+	assert.NoError(t, err, "addRecord() failed")
+	records, err = to.fmt.split(history)
+	assert.NoError(t, err, "split() failed")
+	assert.Equal(t, [][]byte{secondRecord, thirdRecord}, records, "History formatter did not append record with new ID.")
+	latest, err := to.fmt.getLatest(history)
+	assert.NoError(t, err, "getLatest() failed")
+	assert.Equal(t, thirdRecord, latest)
+}
+
+func TestNormalizeFullHistory(t *testing.T) {
+	stor, path, err := createStorageObjects()
+	assert.NoError(t, err, "createStorageObjects() failed")
+
+	defer func() {
+		err = stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	fmt, err := newHistoryFormatter(idSize, idSize, true, stor.stateDB, stor.rw)
+	assert.NoError(t, err, "newHistoryFormatter() failed")
+	var history []byte
+	for _, blockID := range genRandBlockIds(t, rollbackMaxBlocks+10) {
+		stor.addBlock(t, blockID)
+		blockNum, err := stor.stateDB.blockIdToNum(blockID)
+		assert.NoError(t, err, "blockIdToNum() failed")
+		blockNumBytes := make([]byte, idSize)
+		binary.BigEndian.PutUint32(blockNumBytes, blockNum)
+		history, err = fmt.addRecord(history, blockNumBytes)
+		assert.NoError(t, err, "addRecord() failed")
+	}
+	stor.flush(t)
+	normalized, err := fmt.normalize(history, true)
+	assert.NoError(t, err, "normalize() failed")
+	assert.Equal(t, history, normalized, "History formatter cut full history.")
+}
@@ -0,0 +1,37 @@
+package state
+
+import (
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+)
+
+// invokeResults keeps results of InvokeScript transactions between checking and applying them.
+// DApp callable functions are run once by checker, then performer writes data entries
+// and differ creates balance changes using the same result.
+type invokeResults struct {
+	results map[string]*ast.ScriptResult
+}
+
+func newInvokeResults() *invokeResults {
+	return &invokeResults{results: make(map[string]*ast.ScriptResult)}
+}
+
+func (ir *invokeResults) saveResult(txID []byte, res *ast.ScriptResult) {
+	ir.results[string(txID)] = res
+}
+
+func (ir *invokeResults) result(txID []byte) (*ast.ScriptResult, error) {
+	res, ok := ir.results[string(txID)]
+	if !ok {
+		return nil, errors.New("no invocation result for transaction")
+	}
+	return res, nil
+}
+
+func (ir *invokeResults) removeResult(txID []byte) {
+	delete(ir.results, string(txID))
+}
+
+func (ir *invokeResults) reset() {
+	ir.results = make(map[string]*ast.ScriptResult)
+}
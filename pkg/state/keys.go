@@ -9,6 +9,14 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/proto"
 )
 
+// New key sizes are kept out of the block with prefixes below,
+// since any constant added there before the prefixes shifts them and breaks existing DBs.
+const (
+	addressTransactionKeySize = 1 + proto.AddressSize + 4 + 4
+	assetHolderKeySize        = 1 + crypto.DigestSize + proto.AddressSize
+	addressLeaseKeySize       = 1 + proto.AddressSize + crypto.DigestSize
+)
+
 const (
 	// Key sizes.
 	wavesBalanceKeySize = 1 + proto.AddressSize
@@ -67,6 +75,34 @@ const (
 
 	// Blocks information (fees for now).
 	blocksInfoKeyPrefix
+
+	// Accounts data storage.
+	accountsDataStorKeyPrefix
+
+	// Scripts.
+	accountScriptKeyPrefix
+	assetScriptKeyPrefix
+
+	// Sponsored assets.
+	sponsorshipKeyPrefix
+
+	// Filled volumes of orders.
+	ordersVolumeKeyPrefix
+
+	// Transactions by addresses they involve.
+	addressTransactionKeyPrefix
+
+	// Addresses which have ever had balances in assets.
+	assetHolderKeyPrefix
+
+	// Leases sent or received by addresses.
+	addressLeaseKeyPrefix
+
+	// Transaction ID --> height of block which includes it.
+	txHeightKeyPrefix
+
+	// Parameters which state was created with.
+	stateParamsKeyPrefix
 )
 
 type wavesBalanceKey struct {
@@ -96,6 +132,14 @@ type assetBalanceKey struct {
 	asset   []byte
 }
 
+// addressAssetBalancesPrefix is the prefix of keys of all the asset balances of address.
+func addressAssetBalancesPrefix(addr proto.Address) []byte {
+	buf := make([]byte, 1+proto.AddressSize)
+	buf[0] = assetBalanceKeyPrefix
+	copy(buf[1:], addr[:])
+	return buf
+}
+
 func (k *assetBalanceKey) bytes() []byte {
 	buf := make([]byte, assetBalanceKeySize)
 	buf[0] = assetBalanceKeyPrefix
@@ -172,6 +216,17 @@ func (k *txOffsetKey) bytes() []byte {
 	return buf
 }
 
+type txHeightKey struct {
+	txID []byte
+}
+
+func (k *txHeightKey) bytes() []byte {
+	buf := make([]byte, 1+crypto.DigestSize)
+	buf[0] = txHeightKeyPrefix
+	copy(buf[1:], k.txID)
+	return buf
+}
+
 type scoreKey struct {
 	height uint64
 }
@@ -327,3 +382,151 @@ func (k *blocksInfoKey) bytes() []byte {
 	copy(buf[1:], k.blockID[:])
 	return buf
 }
+
+type accountsDataStorKey struct {
+	addr     proto.Address
+	entryKey string
+}
+
+func (k *accountsDataStorKey) bytes() []byte {
+	buf := make([]byte, 1+proto.AddressSize+len(k.entryKey))
+	buf[0] = accountsDataStorKeyPrefix
+	copy(buf[1:], k.addr[:])
+	copy(buf[1+proto.AddressSize:], k.entryKey)
+	return buf
+}
+
+// accountsDataStorAddrPrefix is the common prefix of all data entries keys of given address.
+func accountsDataStorAddrPrefix(addr proto.Address) []byte {
+	buf := make([]byte, 1+proto.AddressSize)
+	buf[0] = accountsDataStorKeyPrefix
+	copy(buf[1:], addr[:])
+	return buf
+}
+
+type accountScriptKey struct {
+	addr proto.Address
+}
+
+func (k *accountScriptKey) bytes() []byte {
+	buf := make([]byte, 1+proto.AddressSize)
+	buf[0] = accountScriptKeyPrefix
+	copy(buf[1:], k.addr[:])
+	return buf
+}
+
+type assetScriptKey struct {
+	assetID crypto.Digest
+}
+
+func (k *assetScriptKey) bytes() []byte {
+	buf := make([]byte, 1+crypto.DigestSize)
+	buf[0] = assetScriptKeyPrefix
+	copy(buf[1:], k.assetID[:])
+	return buf
+}
+
+type sponsorshipKey struct {
+	assetID crypto.Digest
+}
+
+func (k *sponsorshipKey) bytes() []byte {
+	buf := make([]byte, 1+crypto.DigestSize)
+	buf[0] = sponsorshipKeyPrefix
+	copy(buf[1:], k.assetID[:])
+	return buf
+}
+
+type ordersVolumeKey struct {
+	orderID []byte
+}
+
+func (k *ordersVolumeKey) bytes() []byte {
+	buf := make([]byte, 1+len(k.orderID))
+	buf[0] = ordersVolumeKeyPrefix
+	copy(buf[1:], k.orderID)
+	return buf
+}
+
+// addressTransactionKey orders transactions of address from the newest to the oldest,
+// so numbers of blocks and positions of transactions in blocks are inverted.
+type addressTransactionKey struct {
+	address  proto.Address
+	blockNum uint32
+	txNum    uint32
+}
+
+func addressTransactionsPrefix(addr proto.Address) []byte {
+	buf := make([]byte, 1+proto.AddressSize)
+	buf[0] = addressTransactionKeyPrefix
+	copy(buf[1:], addr[:])
+	return buf
+}
+
+// blockPrefix is the prefix of keys of address transactions in the block.
+func (k *addressTransactionKey) blockPrefix() []byte {
+	buf := make([]byte, 1+proto.AddressSize+4)
+	copy(buf, addressTransactionsPrefix(k.address))
+	binary.BigEndian.PutUint32(buf[1+proto.AddressSize:], ^k.blockNum)
+	return buf
+}
+
+func (k *addressTransactionKey) bytes() []byte {
+	buf := make([]byte, addressTransactionKeySize)
+	copy(buf, k.blockPrefix())
+	binary.BigEndian.PutUint32(buf[1+proto.AddressSize+4:], ^k.txNum)
+	return buf
+}
+
+func (k *addressTransactionKey) unmarshal(data []byte) error {
+	if len(data) != addressTransactionKeySize {
+		return errors.New("invalid data size")
+	}
+	var err error
+	if k.address, err = proto.NewAddressFromBytes(data[1 : 1+proto.AddressSize]); err != nil {
+		return err
+	}
+	k.blockNum = ^binary.BigEndian.Uint32(data[1+proto.AddressSize:])
+	k.txNum = ^binary.BigEndian.Uint32(data[1+proto.AddressSize+4:])
+	return nil
+}
+
+// assetHolderKey marks address which has ever had balance in asset, holders of asset are ordered by addresses.
+type assetHolderKey struct {
+	assetID crypto.Digest
+	address proto.Address
+}
+
+func assetHoldersPrefix(assetID crypto.Digest) []byte {
+	buf := make([]byte, 1+crypto.DigestSize)
+	buf[0] = assetHolderKeyPrefix
+	copy(buf[1:], assetID[:])
+	return buf
+}
+
+func (k *assetHolderKey) bytes() []byte {
+	buf := make([]byte, assetHolderKeySize)
+	copy(buf, assetHoldersPrefix(k.assetID))
+	copy(buf[1+crypto.DigestSize:], k.address[:])
+	return buf
+}
+
+// addressLeaseKey marks lease which address has sent or received.
+type addressLeaseKey struct {
+	address proto.Address
+	leaseID crypto.Digest
+}
+
+func addressLeasesPrefix(addr proto.Address) []byte {
+	buf := make([]byte, 1+proto.AddressSize)
+	buf[0] = addressLeaseKeyPrefix
+	copy(buf[1:], addr[:])
+	return buf
+}
+
+func (k *addressLeaseKey) bytes() []byte {
+	buf := make([]byte, addressLeaseKeySize)
+	copy(buf, addressLeasesPrefix(k.address))
+	copy(buf[1+proto.AddressSize:], k.leaseID[:])
+	return buf
+}
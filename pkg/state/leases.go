@@ -12,6 +12,8 @@ import (
 
 const (
 	leasingRecordSize = 1 + 8 + proto.AddressSize*2 + 4
+	// leasesBatchSize is the number of leases which are flushed at once while index of address leases is built.
+	leasesBatchSize = 100000
 )
 
 type leasing struct {
@@ -54,12 +56,13 @@ func (l *leasingRecord) unmarshalBinary(data []byte) error {
 
 type leases struct {
 	db      keyvalue.IterableKeyVal
+	dbBatch keyvalue.Batch
 	stateDB *stateDB
 	hs      *historyStorage
 }
 
-func newLeases(db keyvalue.IterableKeyVal, stateDB *stateDB, hs *historyStorage) (*leases, error) {
-	return &leases{db, stateDB, hs}, nil
+func newLeases(db keyvalue.IterableKeyVal, dbBatch keyvalue.Batch, stateDB *stateDB, hs *historyStorage) (*leases, error) {
+	return &leases{db, dbBatch, stateDB, hs}, nil
 }
 
 func (l *leases) cancelLeases(bySenders map[proto.Address]struct{}) error {
@@ -100,6 +103,9 @@ func (l *leases) cancelLeases(bySenders map[proto.Address]struct{}) error {
 			if err := l.hs.set(lease, key, leaseBytes); err != nil {
 				return errors.Errorf("failed to save lease to storage: %v\n", err)
 			}
+			var id crypto.Digest
+			copy(id[:], key[1:])
+			l.putAddressLeases(l.dbBatch, id, &leaseRecord.leasing, leaseRecord.blockNum)
 		}
 	}
 	return nil
@@ -177,9 +183,100 @@ func (l *leases) addLeasing(id crypto.Digest, leasing *leasing, blockID crypto.S
 	if err := l.hs.set(lease, key.bytes(), recordBytes); err != nil {
 		return err
 	}
+	l.putAddressLeases(l.dbBatch, id, leasing, blockNum)
 	return nil
 }
 
+// putAddressLeases adds lease to index of leases of its sender and recipient.
+// Cancelled lease is marked with number of block which cancelled it, so it can be skipped without reading its record.
+func (l *leases) putAddressLeases(batch keyvalue.Batch, id crypto.Digest, leasing *leasing, blockNum uint32) {
+	var val []byte
+	if !leasing.isActive {
+		val = make([]byte, 4)
+		binary.BigEndian.PutUint32(val, blockNum)
+	}
+	for _, addr := range []proto.Address{leasing.sender, leasing.recipient} {
+		key := addressLeaseKey{address: addr, leaseID: id}
+		batch.Put(key.bytes(), val)
+	}
+}
+
+// buildAddressLeases adds all the stored leases to index of leases of addresses.
+func (l *leases) buildAddressLeases() error {
+	batch, err := l.db.NewBatch()
+	if err != nil {
+		return err
+	}
+	iter, err := l.db.NewKeyIterator([]byte{leaseKeyPrefix})
+	if err != nil {
+		return errors.Errorf("failed to create key iterator for leases: %v\n", err)
+	}
+	defer iter.Release()
+
+	n := 0
+	for iter.Next() {
+		key := iter.Key()
+		recordBytes, err := l.hs.get(lease, key, true)
+		if err == errEmptyHist {
+			// All the records were removed by rollback.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var record leasingRecord
+		if err := record.unmarshalBinary(recordBytes); err != nil {
+			return errors.Errorf("failed to unmarshal lease: %v\n", err)
+		}
+		var id crypto.Digest
+		copy(id[:], key[1:])
+		l.putAddressLeases(batch, id, &record.leasing, record.blockNum)
+		n++
+		if n == leasesBatchSize {
+			if err := l.db.Flush(batch); err != nil {
+				return err
+			}
+			n = 0
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return errors.Errorf("failed to iterate leases: %v\n", err)
+	}
+	return l.db.Flush(batch)
+}
+
+// activeAddressLeases returns IDs of leases which address has sent or received and which may be active,
+// leases cancelled in valid blocks are skipped. Leases of rolled back blocks may be among them as well.
+func (l *leases) activeAddressLeases(addr proto.Address) ([]crypto.Digest, error) {
+	iter, err := l.db.NewKeyIterator(addressLeasesPrefix(addr))
+	if err != nil {
+		return nil, errors.Errorf("failed to create key iterator for address leases: %v\n", err)
+	}
+	defer iter.Release()
+
+	var ids []crypto.Digest
+	for iter.Next() {
+		if val := iter.Value(); len(val) == 4 {
+			cancelled, err := l.stateDB.isValidBlock(binary.BigEndian.Uint32(val))
+			if err != nil {
+				return nil, err
+			}
+			if cancelled {
+				continue
+			}
+		}
+		id, err := crypto.NewDigestFromBytes(iter.Key()[1+proto.AddressSize:])
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, errors.Errorf("failed to iterate address leases: %v\n", err)
+	}
+	return ids, nil
+}
+
 func (l *leases) cancelLeasing(id crypto.Digest, blockID crypto.Signature, filter bool) error {
 	leasing, err := l.newestLeasingInfo(id, filter)
 	if err != nil {
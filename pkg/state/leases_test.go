@@ -20,7 +20,7 @@ func createLeases() (*leasesTestObjects, []string, error) {
 	if err != nil {
 		return nil, path, err
 	}
-	leases, err := newLeases(stor.db, stor.stateDB, stor.hs)
+	leases, err := newLeases(stor.db, stor.dbBatch, stor.stateDB, stor.hs)
 	if err != nil {
 		return nil, path, err
 	}
@@ -192,3 +192,59 @@ func TestCancelLeasing(t *testing.T) {
 	assert.NoError(t, err, "failed to get leasing info")
 	assert.Equal(t, resLeasing, r, "invalid leasing record after cancelation")
 }
+
+func TestAddressLeases(t *testing.T) {
+	to, path, err := createLeases()
+	assert.NoError(t, err, "createLeases() failed")
+
+	defer func() {
+		err = to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	to.stor.addBlock(t, blockID0)
+	to.stor.addBlock(t, blockID1)
+	id0 := crypto.Digest{1}
+	id1 := crypto.Digest{2}
+	lease0 := createLease(t, "3PNXHYoWp83VaWudq9ds9LpS5xykWuJHiHp")
+	lease1 := createLease(t, "3P9MUoSW7jfHNVFcq84rurfdWZYZuvVghVi")
+	err = to.leases.addLeasing(id0, lease0, blockID0)
+	assert.NoError(t, err, "addLeasing() failed")
+	err = to.leases.addLeasing(id1, lease1, blockID0)
+	assert.NoError(t, err, "addLeasing() failed")
+	err = to.leases.cancelLeasing(id0, blockID1, true)
+	assert.NoError(t, err, "cancelLeasing() failed")
+	to.stor.flush(t)
+
+	check := func(addr proto.Address, expected []crypto.Digest) {
+		ids, err := to.leases.activeAddressLeases(addr)
+		assert.NoError(t, err, "activeAddressLeases() failed")
+		assert.Equal(t, expected, ids)
+	}
+	// Cancelled leases are skipped.
+	check(lease0.recipient, []crypto.Digest{id1})
+	check(lease0.sender, nil)
+	check(lease1.sender, []crypto.Digest{id1})
+
+	// Index is the same when it is built from stored leases.
+	for _, addr := range []proto.Address{lease0.recipient, lease0.sender, lease1.sender} {
+		for _, id := range []crypto.Digest{id0, id1} {
+			key := addressLeaseKey{address: addr, leaseID: id}
+			err = to.stor.db.Delete(key.bytes())
+			assert.NoError(t, err, "Delete() failed")
+		}
+	}
+	check(lease0.recipient, nil)
+	err = to.leases.buildAddressLeases()
+	assert.NoError(t, err, "buildAddressLeases() failed")
+	check(lease0.recipient, []crypto.Digest{id1})
+	check(lease0.sender, nil)
+
+	// Lease is active again if its cancellation is rolled back.
+	err = to.stor.stateDB.rollbackBlock(blockID1)
+	assert.NoError(t, err, "rollbackBlock() failed")
+	check(lease0.recipient, []crypto.Digest{id0, id1})
+	check(lease0.sender, []crypto.Digest{id0})
+}
@@ -0,0 +1,195 @@
+package state
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+const (
+	// Filled amount + filled fee + blockNum.
+	orderVolumeRecordSize = 8 + 8 + 4
+)
+
+// orderVolumeRecord stores amount and matcher fee of order which have been filled by exchanges.
+type orderVolumeRecord struct {
+	amountFilled uint64
+	feeFilled    uint64
+	blockNum     uint32
+}
+
+func (r *orderVolumeRecord) marshalBinary() ([]byte, error) {
+	res := make([]byte, orderVolumeRecordSize)
+	binary.BigEndian.PutUint64(res[:8], r.amountFilled)
+	binary.BigEndian.PutUint64(res[8:16], r.feeFilled)
+	binary.BigEndian.PutUint32(res[16:], r.blockNum)
+	return res, nil
+}
+
+func (r *orderVolumeRecord) unmarshalBinary(data []byte) error {
+	if len(data) != orderVolumeRecordSize {
+		return errors.New("invalid data size")
+	}
+	r.amountFilled = binary.BigEndian.Uint64(data[:8])
+	r.feeFilled = binary.BigEndian.Uint64(data[8:16])
+	r.blockNum = binary.BigEndian.Uint32(data[16:])
+	return nil
+}
+
+type ordersVolumes struct {
+	stateDB *stateDB
+	hs      *historyStorage
+}
+
+func newOrdersVolumes(stateDB *stateDB, hs *historyStorage) (*ordersVolumes, error) {
+	return &ordersVolumes{stateDB, hs}, nil
+}
+
+func (ov *ordersVolumes) newestVolumeByID(orderID []byte, filter bool) (*orderVolumeRecord, error) {
+	key := ordersVolumeKey{orderID}
+	recordBytes, err := ov.hs.getFresh(ordersVolume, key.bytes(), filter)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist {
+		// Order has not been filled yet.
+		return &orderVolumeRecord{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record orderVolumeRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return nil, errors.Errorf("failed to unmarshal record: %v\n", err)
+	}
+	return &record, nil
+}
+
+func (ov *ordersVolumes) volumeByID(orderID []byte, filter bool) (*orderVolumeRecord, error) {
+	key := ordersVolumeKey{orderID}
+	recordBytes, err := ov.hs.get(ordersVolume, key.bytes(), filter)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist {
+		// Order has not been filled yet.
+		return &orderVolumeRecord{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record orderVolumeRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return nil, errors.Errorf("failed to unmarshal record: %v\n", err)
+	}
+	return &record, nil
+}
+
+// increaseFilled adds amount and fee filled by exchange to the order's volume.
+func (ov *ordersVolumes) increaseFilled(orderID []byte, amountChange, feeChange uint64, blockID crypto.Signature, filter bool) error {
+	prev, err := ov.newestVolumeByID(orderID, filter)
+	if err != nil {
+		return err
+	}
+	blockNum, err := ov.stateDB.blockIdToNum(blockID)
+	if err != nil {
+		return err
+	}
+	r := &orderVolumeRecord{amountFilled: prev.amountFilled + amountChange, feeFilled: prev.feeFilled + feeChange, blockNum: blockNum}
+	recordBytes, err := r.marshalBinary()
+	if err != nil {
+		return errors.Errorf("failed to marshal record: %v\n", err)
+	}
+	key := ordersVolumeKey{orderID}
+	return ov.hs.set(ordersVolume, key.bytes(), recordBytes)
+}
+
+// Newest filled amount and fee of order, including changes of blocks which are being added.
+func (ov *ordersVolumes) newestFilled(orderID []byte, filter bool) (uint64, uint64, error) {
+	r, err := ov.newestVolumeByID(orderID, filter)
+	if err != nil {
+		return 0, 0, err
+	}
+	return r.amountFilled, r.feeFilled, nil
+}
+
+// Filled amount and fee of order from DB.
+func (ov *ordersVolumes) filled(orderID []byte, filter bool) (uint64, uint64, error) {
+	r, err := ov.volumeByID(orderID, filter)
+	if err != nil {
+		return 0, 0, err
+	}
+	return r.amountFilled, r.feeFilled, nil
+}
+
+// orderFill is amount and matcher fee filled by exchange for one of its orders.
+type orderFill struct {
+	orderID []byte
+	amount  uint64
+	fee     uint64
+}
+
+// exchangeFills returns fills of buy and sell orders of Exchange transaction.
+func exchangeFills(transaction proto.Transaction) ([]orderFill, error) {
+	tx, ok := transaction.(proto.Exchange)
+	if !ok {
+		return nil, errors.New("failed to convert interface to Exchange transaction")
+	}
+	buyOrder, sellOrder, err := txOrders(transaction)
+	if err != nil {
+		return nil, err
+	}
+	buyID, err := buyOrder.GetID()
+	if err != nil {
+		return nil, err
+	}
+	sellID, err := sellOrder.GetID()
+	if err != nil {
+		return nil, err
+	}
+	return []orderFill{
+		{orderID: buyID, amount: tx.GetAmount(), fee: tx.GetBuyMatcherFee()},
+		{orderID: sellID, amount: tx.GetAmount(), fee: tx.GetSellMatcherFee()},
+	}, nil
+}
+
+// ordersVolumesDiff holds volumes filled by exchanges which have been validated, but are not stored yet.
+// It is used when transactions are validated without blocks, so performer does not store their fills.
+type ordersVolumesDiff struct {
+	volumes map[string]*orderVolumeRecord
+}
+
+func newOrdersVolumesDiff() *ordersVolumesDiff {
+	return &ordersVolumesDiff{volumes: make(map[string]*orderVolumeRecord)}
+}
+
+// filled returns pending filled amount and fee of order, diff may be nil.
+func (d *ordersVolumesDiff) filled(orderID []byte) (uint64, uint64) {
+	if d == nil {
+		return 0, 0
+	}
+	r, ok := d.volumes[string(orderID)]
+	if !ok {
+		return 0, 0
+	}
+	return r.amountFilled, r.feeFilled
+}
+
+// saveExchange adds fills of validated Exchange transaction to the pending volumes of its orders.
+func (d *ordersVolumesDiff) saveExchange(transaction proto.Transaction) error {
+	fills, err := exchangeFills(transaction)
+	if err != nil {
+		return err
+	}
+	for _, fill := range fills {
+		r, ok := d.volumes[string(fill.orderID)]
+		if !ok {
+			r = &orderVolumeRecord{}
+			d.volumes[string(fill.orderID)] = r
+		}
+		r.amountFilled += fill.amount
+		r.feeFilled += fill.fee
+	}
+	return nil
+}
+
+func (d *ordersVolumesDiff) reset() {
+	d.volumes = make(map[string]*orderVolumeRecord)
+}
@@ -0,0 +1,79 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wavesplatform/gowaves/pkg/util"
+)
+
+type ordersVolumesTestObjects struct {
+	stor          *storageObjects
+	ordersVolumes *ordersVolumes
+}
+
+func createOrdersVolumesTestObjects() (*ordersVolumesTestObjects, []string, error) {
+	stor, path, err := createStorageObjects()
+	if err != nil {
+		return nil, path, err
+	}
+	ordersVolumes, err := newOrdersVolumes(stor.stateDB, stor.hs)
+	if err != nil {
+		return nil, path, err
+	}
+	return &ordersVolumesTestObjects{stor, ordersVolumes}, path, nil
+}
+
+func TestIncreaseFilled(t *testing.T) {
+	to, path, err := createOrdersVolumesTestObjects()
+	assert.NoError(t, err, "createOrdersVolumesTestObjects() failed")
+
+	defer func() {
+		err = to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	orderID := testGlobal.asset0.assetID
+	// Order which has not been filled yet.
+	amount, fee, err := to.ordersVolumes.newestFilled(orderID, true)
+	assert.NoError(t, err, "newestFilled() failed")
+	assert.Equal(t, uint64(0), amount)
+	assert.Equal(t, uint64(0), fee)
+
+	to.stor.addBlock(t, blockID0)
+	err = to.ordersVolumes.increaseFilled(orderID, 10, 1, blockID0, true)
+	assert.NoError(t, err, "increaseFilled() failed")
+	err = to.ordersVolumes.increaseFilled(orderID, 20, 2, blockID0, true)
+	assert.NoError(t, err, "increaseFilled() failed")
+	amount, fee, err = to.ordersVolumes.newestFilled(orderID, true)
+	assert.NoError(t, err, "newestFilled() failed")
+	assert.Equal(t, uint64(30), amount)
+	assert.Equal(t, uint64(3), fee)
+	amount, _, err = to.ordersVolumes.filled(orderID, true)
+	assert.NoError(t, err, "filled() failed")
+	assert.Equal(t, uint64(0), amount, "volume is stable before flush")
+	to.stor.flush(t)
+	amount, fee, err = to.ordersVolumes.filled(orderID, true)
+	assert.NoError(t, err, "filled() failed")
+	assert.Equal(t, uint64(30), amount)
+	assert.Equal(t, uint64(3), fee)
+
+	to.stor.addBlock(t, blockID1)
+	err = to.ordersVolumes.increaseFilled(orderID, 5, 1, blockID1, true)
+	assert.NoError(t, err, "increaseFilled() failed")
+	to.stor.flush(t)
+	amount, fee, err = to.ordersVolumes.filled(orderID, true)
+	assert.NoError(t, err, "filled() failed")
+	assert.Equal(t, uint64(35), amount)
+	assert.Equal(t, uint64(4), fee)
+
+	// Rollback removes volume filled in the block.
+	err = to.stor.stateDB.rollbackBlock(blockID1)
+	assert.NoError(t, err, "rollbackBlock() failed")
+	amount, fee, err = to.ordersVolumes.filled(orderID, true)
+	assert.NoError(t, err, "filled() failed")
+	assert.Equal(t, uint64(30), amount)
+	assert.Equal(t, uint64(3), fee)
+}
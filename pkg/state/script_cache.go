@@ -0,0 +1,76 @@
+package state
+
+import (
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/parser"
+)
+
+// scriptCache holds compiled scripts by hash of their bytes, so the script
+// of account or asset is parsed and compiled once and not on every call.
+type scriptCache struct {
+	scripts map[crypto.Digest]*ast.Script
+	dApps   map[crypto.Digest]*ast.ContractScript
+	size    int
+}
+
+func newScriptCache(size int) *scriptCache {
+	return &scriptCache{
+		scripts: make(map[crypto.Digest]*ast.Script),
+		dApps:   make(map[crypto.Digest]*ast.ContractScript),
+		size:    size,
+	}
+}
+
+// evict drops all cached scripts when cache is full.
+func (c *scriptCache) evict() {
+	if len(c.scripts)+len(c.dApps) < c.size {
+		return
+	}
+	c.scripts = make(map[crypto.Digest]*ast.Script)
+	c.dApps = make(map[crypto.Digest]*ast.ContractScript)
+}
+
+func (c *scriptCache) script(script proto.Script) (*ast.Script, error) {
+	hash, err := crypto.FastHash(script)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := c.scripts[hash]; ok {
+		return s, nil
+	}
+	s, err := parser.LoadScript(script)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build AST of script")
+	}
+	compiled, err := s.Compile()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compile script")
+	}
+	c.evict()
+	c.scripts[hash] = compiled
+	return compiled, nil
+}
+
+func (c *scriptCache) dApp(script proto.Script) (*ast.ContractScript, error) {
+	hash, err := crypto.FastHash(script)
+	if err != nil {
+		return nil, err
+	}
+	if dApp, ok := c.dApps[hash]; ok {
+		return dApp, nil
+	}
+	dApp, err := parser.LoadContract(script)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build DApp")
+	}
+	compiled, err := dApp.Compile()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compile DApp")
+	}
+	c.evict()
+	c.dApps[hash] = compiled
+	return compiled, nil
+}
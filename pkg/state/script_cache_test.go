@@ -0,0 +1,38 @@
+package state
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScriptCache(t *testing.T) {
+	cache := newScriptCache(2)
+	decode := func(s string) []byte {
+		b, err := base64.StdEncoding.DecodeString(s)
+		assert.NoError(t, err, "DecodeString() failed")
+		return b
+	}
+
+	s1, err := cache.script(decode(trueScript))
+	assert.NoError(t, err, "script() failed")
+	s2, err := cache.script(decode(trueScript))
+	assert.NoError(t, err, "script() failed")
+	assert.True(t, s1 == s2, "same script is compiled once")
+
+	dApp1, err := cache.dApp(decode(dAppScript))
+	assert.NoError(t, err, "dApp() failed")
+	dApp2, err := cache.dApp(decode(dAppScript))
+	assert.NoError(t, err, "dApp() failed")
+	assert.True(t, dApp1 == dApp2, "same DApp is compiled once")
+
+	// Cache is full, it is cleared before the next script is added.
+	_, err = cache.script(decode(falseScript))
+	assert.NoError(t, err, "script() failed")
+	assert.Equal(t, 1, len(cache.scripts))
+	assert.Equal(t, 0, len(cache.dApps))
+
+	_, err = cache.dApp(decode(trueScript))
+	assert.Error(t, err, "expression script is not a DApp")
+}
@@ -0,0 +1,205 @@
+package state
+
+import (
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/evaluate"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/parser"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+)
+
+// defaultFunctionName is the name of DApp function which is invoked when function call is omitted.
+const defaultFunctionName = "default"
+
+type scriptCaller struct {
+	stor     *blockchainEntitiesStorage
+	settings *settings.BlockchainSettings
+
+	// pending holds changes which are not stored yet, but must be visible to scripts.
+	pending *pendingChanges
+	cache   *scriptCache
+}
+
+func newScriptCaller(stor *blockchainEntitiesStorage, settings *settings.BlockchainSettings) (*scriptCaller, error) {
+	return &scriptCaller{
+		stor:     stor,
+		settings: settings,
+		pending:  newPendingChanges(),
+		cache:    newScriptCache(scriptCacheSize),
+	}, nil
+}
+
+func (a *scriptCaller) state(initialisation bool) *scriptState {
+	return &scriptState{stor: a.stor, pending: a.pending, filter: !initialisation, scheme: a.settings.AddressSchemeCharacter}
+}
+
+// scope returns scope of script which checks transaction or order given as tx object.
+func (a *scriptCaller) scope(version int, tx ast.Expr, height uint64, initialisation bool) (ast.Scope, error) {
+	funcs, err := ast.NewFuncScope(version)
+	if err != nil {
+		return nil, err
+	}
+	variables := map[string]ast.Expr{
+		"tx":     tx,
+		"height": ast.NewLong(int64(height)),
+	}
+	return ast.NewScope(a.settings.AddressSchemeCharacter, a.state(initialisation), funcs, variables), nil
+}
+
+func (a *scriptCaller) callScript(script proto.Script, tx ast.Expr, height uint64, initialisation bool) (bool, error) {
+	s, err := a.cache.script(script)
+	if err != nil {
+		return false, err
+	}
+	scope, err := a.scope(s.Version, tx, height, initialisation)
+	if err != nil {
+		return false, err
+	}
+	return evaluate.Eval(s.Verifier, scope)
+}
+
+// dAppScope returns scope with global declarations of DApp of given address.
+func (a *scriptCaller) dAppScope(dApp *ast.ContractScript, dAppAddr proto.Address, height uint64, initialisation bool) (ast.Scope, error) {
+	funcs, err := ast.NewFuncScope(dApp.Version)
+	if err != nil {
+		return nil, err
+	}
+	variables := map[string]ast.Expr{
+		"this":   ast.NewAddressFromProtoAddress(dAppAddr),
+		"height": ast.NewLong(int64(height)),
+	}
+	scope := ast.NewScope(a.settings.AddressSchemeCharacter, a.state(initialisation), funcs, variables)
+	dApp.Declare(scope)
+	return scope, nil
+}
+
+// callVerifier runs verifier function of DApp script of given address with transaction or order.
+func (a *scriptCaller) callVerifier(script proto.Script, tx ast.Expr, dAppAddr proto.Address, height uint64, initialisation bool) (bool, error) {
+	dApp, err := a.cache.dApp(script)
+	if err != nil {
+		return false, err
+	}
+	scope, err := a.dAppScope(dApp, dAppAddr, height, initialisation)
+	if err != nil {
+		return false, err
+	}
+	return dApp.Verify(scope, tx)
+}
+
+// invokeFunction runs callable function of DApp script of given address with the invocation of transaction.
+func (a *scriptCaller) invokeFunction(tx *proto.InvokeScriptV1, dAppAddr proto.Address, height uint64, initialisation bool) (*ast.ScriptResult, error) {
+	info, err := a.stor.scriptsStorage.newestAccountScriptInfo(dAppAddr, !initialisation)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve DApp script")
+	}
+	if len(info.script) == 0 {
+		return nil, errors.New("DApp has no script")
+	}
+	dApp, err := a.cache.dApp(info.script)
+	if err != nil {
+		return nil, err
+	}
+	invocation, err := ast.NewInvocation(a.settings.AddressSchemeCharacter, tx)
+	if err != nil {
+		return nil, err
+	}
+	args, err := ast.NewArguments(tx.FunctionCall.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	name := tx.FunctionCall.Name
+	if tx.FunctionCall.Default {
+		name = defaultFunctionName
+	}
+	scope, err := a.dAppScope(dApp, dAppAddr, height, initialisation)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := dApp.Invoke(scope, name, invocation, args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invocation of function %s failed", name)
+	}
+	return ast.NewScriptResult(rs)
+}
+
+// callAccountScript runs verifier script of sender account with given transaction or order.
+// It returns error if the script failed or rejected it.
+func (a *scriptCaller) callAccountScript(tx ast.Expr, senderAddr proto.Address, height uint64, initialisation bool) (bool, error) {
+	info, err := a.stor.scriptsStorage.newestAccountScriptInfo(senderAddr, !initialisation)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to retrieve account script")
+	}
+	if len(info.script) == 0 {
+		return false, errors.New("account has no script")
+	}
+	var ok bool
+	if parser.IsContract(info.script) {
+		ok, err = a.callVerifier(info.script, tx, senderAddr, height, initialisation)
+	} else {
+		ok, err = a.callScript(info.script, tx, height, initialisation)
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "account script execution failed")
+	}
+	return ok, nil
+}
+
+// callAccountScriptWithTx runs verifier script of sender account with given transaction.
+// It returns error if the script failed or rejected the transaction.
+func (a *scriptCaller) callAccountScriptWithTx(tx proto.Transaction, senderAddr proto.Address, height uint64, initialisation bool) error {
+	txVars, err := ast.NewVariablesFromTransaction(a.settings.AddressSchemeCharacter, tx)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert transaction")
+	}
+	ok, err := a.callAccountScript(ast.NewObject(txVars), senderAddr, height, initialisation)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("transaction is not allowed by account script")
+	}
+	return nil
+}
+
+// callAccountScriptWithOrder runs verifier script of sender account with given order of Exchange transaction.
+// It returns error if the script failed or rejected the order.
+func (a *scriptCaller) callAccountScriptWithOrder(order proto.Order, senderAddr proto.Address, height uint64, initialisation bool) error {
+	orderVars, err := ast.NewVariablesFromOrder(a.settings.AddressSchemeCharacter, order)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert order")
+	}
+	ok, err := a.callAccountScript(ast.NewObject(orderVars), senderAddr, height, initialisation)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("order is not allowed by account script")
+	}
+	return nil
+}
+
+// callAssetScriptWithTx runs script of smart asset with given transaction.
+// It returns error if the script failed or rejected the transaction.
+func (a *scriptCaller) callAssetScriptWithTx(tx proto.Transaction, assetID crypto.Digest, height uint64, initialisation bool) error {
+	info, err := a.stor.scriptsStorage.newestAssetScriptInfo(assetID, !initialisation)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve asset script")
+	}
+	if len(info.script) == 0 {
+		return errors.New("asset has no script")
+	}
+	txVars, err := ast.NewVariablesFromTransaction(a.settings.AddressSchemeCharacter, tx)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert transaction")
+	}
+	ok, err := a.callScript(info.script, ast.NewObject(txVars), height, initialisation)
+	if err != nil {
+		return errors.Wrap(err, "asset script execution failed")
+	}
+	if !ok {
+		return errors.New("transaction is not allowed by asset script")
+	}
+	return nil
+}
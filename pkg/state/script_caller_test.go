@@ -0,0 +1,169 @@
+package state
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/util"
+)
+
+const (
+	// Compiled `true` and `false` scripts.
+	trueScript  = "AQa3b8tH"
+	falseScript = "AQfeYll6"
+	// sigVerify(base58'', base58'', base58'') && ... 21 times, its complexity is 2185.
+	complexScript = "AQMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAMJAAH0AAAAAwEAAAAAAQAAAAABAAAAAAYHBwcHBwcHBwcHBwcHBwcHBwcHBwdaeL+f"
+
+	// Compiled DApp script:
+	//
+	// {-# STDLIB_VERSION 3 #-}
+	// {-# CONTENT_TYPE DAPP #-}
+	// let prefix = "key_"
+	// func key(k: String) = prefix + k
+	//
+	// @Callable(i)
+	// func store(k: String, v: Int) = {
+	//     let callerKey = key("caller")
+	//     WriteSet([DataEntry(key(k), v), DataEntry(callerKey, i.callerPublicKey)])
+	// }
+	//
+	// @Callable(i)
+	// func withdraw(amount: Int) = TransferSet([ScriptTransfer(i.caller, amount, unit)])
+	dAppScript = "AAIDAAAAAAAAAAAAAAACAAAAAAZwcmVmaXgCAAAABGtleV8BAAAAA2tleQAAAAEAAAABawkAASwAAAACBQAAAAZwcmVmaXgFAAAAAWsAAAACAAAAAWkBAAAABXN0b3JlAAAAAgAAAAFrAAAAAXYKAAAAAAljYWxsZXJLZXkJAQAAAANrZXkAAAABAgAAAAZjYWxsZXIJAQAAAAhXcml0ZVNldAAAAAEJAARMAAAAAgkBAAAACURhdGFFbnRyeQAAAAIJAQAAAANrZXkAAAABBQAAAAFrBQAAAAF2CQAETAAAAAIJAQAAAAlEYXRhRW50cnkAAAACBQAAAAljYWxsZXJLZXkIBQAAAAFpAAAAD2NhbGxlclB1YmxpY0tleQUAAAADbmlsAAAAAWkBAAAACHdpdGhkcmF3AAAAAQAAAAZhbW91bnQJAQAAAAtUcmFuc2ZlclNldAAAAAEJAARMAAAAAgkBAAAADlNjcmlwdFRyYW5zZmVyAAAAAwgFAAAAAWkAAAAGY2FsbGVyBQAAAAZhbW91bnQFAAAABHVuaXQFAAAAA25pbAAAAADjeRRi"
+
+	// Compiled DApp script with metadata and verifier:
+	//
+	// {-# STDLIB_VERSION 3 #-}
+	// {-# CONTENT_TYPE DAPP #-}
+	//
+	// @Callable(i)
+	// func deposit(amount: Int, note: String) = WriteSet([DataEntry("note", note)])
+	//
+	// @Verifier(tx)
+	// func verify() = this == tx.sender
+	dAppWithVerifierScript = "AAIDAAAAAAAAAAgIARIECgIBCAAAAAAAAAABAAAAAWkBAAAAB2RlcG9zaXQAAAACAAAABmFtb3VudAAAAARub3RlCQEAAAAIV3JpdGVTZXQAAAABCQAETAAAAAIJAQAAAAlEYXRhRW50cnkAAAACAgAAAARub3RlBQAAAARub3RlBQAAAANuaWwAAAABAAAAAnR4AQAAAAZ2ZXJpZnkAAAAACQAAAAAAAAIFAAAABHRoaXMIBQAAAAJ0eAAAAAZzZW5kZXLKYrfh"
+	// The same DApp without verifier.
+	dAppWithoutVerifierScript = "AAIDAAAAAAAAAAgIARIECgIBCAAAAAAAAAABAAAAAWkBAAAAB2RlcG9zaXQAAAACAAAABmFtb3VudAAAAARub3RlCQEAAAAIV3JpdGVTZXQAAAABCQAETAAAAAIJAQAAAAlEYXRhRW50cnkAAAACAgAAAARub3RlBQAAAARub3RlBQAAAANuaWwAAAAAjPEmGQ=="
+)
+
+type scriptCallerTestObjects struct {
+	stor     *storageObjects
+	entities *blockchainEntitiesStorage
+	sc       *scriptCaller
+}
+
+func createScriptCallerTestObjects(t *testing.T) (*scriptCallerTestObjects, []string) {
+	stor, path, err := createStorageObjects()
+	assert.NoError(t, err, "createStorageObjects() failed")
+	entities, err := newBlockchainEntitiesStorage(stor.hs, stor.stateDB, settings.MainNetSettings)
+	assert.NoError(t, err, "newBlockchainEntitiesStorage() failed")
+	sc, err := newScriptCaller(entities, settings.MainNetSettings)
+	assert.NoError(t, err, "newScriptCaller() failed")
+	return &scriptCallerTestObjects{stor, entities, sc}, path
+}
+
+func setTestAccountScript(t *testing.T, to *scriptCallerTestObjects, addr proto.Address, scriptBase64 string) {
+	script, err := base64.StdEncoding.DecodeString(scriptBase64)
+	assert.NoError(t, err, "DecodeString() failed")
+	info, err := newScriptInfo(script)
+	assert.NoError(t, err, "newScriptInfo() failed")
+	to.stor.addBlock(t, blockID0)
+	err = to.entities.scriptsStorage.setAccountScript(addr, info, blockID0)
+	assert.NoError(t, err, "setAccountScript() failed")
+	to.stor.flush(t)
+}
+
+func TestCallAccountScriptWithTx(t *testing.T) {
+	to, path := createScriptCallerTestObjects(t)
+
+	defer func() {
+		err := to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	sk, pk := crypto.GenerateKeyPair([]byte("script caller test seed"))
+	addr, err := proto.NewAddressFromPublicKey(settings.MainNetSettings.AddressSchemeCharacter, pk)
+	assert.NoError(t, err, "NewAddressFromPublicKey() failed")
+	tx := proto.NewUnsignedData(pk, defaultFee, defaultTimestamp)
+	err = tx.AppendEntry(proto.IntegerDataEntry{Key: "key", Value: 1})
+	assert.NoError(t, err, "AppendEntry() failed")
+	err = tx.Sign(sk)
+	assert.NoError(t, err, "Sign() failed")
+
+	err = to.sc.callAccountScriptWithTx(tx, addr, 1, false)
+	assert.Error(t, err, "callAccountScriptWithTx() did not fail for account without script")
+
+	setTestAccountScript(t, to, addr, trueScript)
+	err = to.sc.callAccountScriptWithTx(tx, addr, 1, false)
+	assert.NoError(t, err, "callAccountScriptWithTx() failed with script which allows transaction")
+
+	setTestAccountScript(t, to, addr, falseScript)
+	err = to.sc.callAccountScriptWithTx(tx, addr, 1, false)
+	assert.EqualError(t, err, "transaction is not allowed by account script")
+
+	// DApp verifier allows only transactions sent by DApp itself.
+	setTestAccountScript(t, to, addr, dAppWithVerifierScript)
+	err = to.sc.callAccountScriptWithTx(tx, addr, 1, false)
+	assert.NoError(t, err, "callAccountScriptWithTx() failed with DApp verifier which allows transaction")
+	otherAddr := testGlobal.recipientInfo.addr
+	setTestAccountScript(t, to, otherAddr, dAppWithVerifierScript)
+	err = to.sc.callAccountScriptWithTx(tx, otherAddr, 1, false)
+	assert.EqualError(t, err, "transaction is not allowed by account script")
+}
+
+func TestInvokeFunction(t *testing.T) {
+	to, path := createScriptCallerTestObjects(t)
+
+	defer func() {
+		err := to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	dAppAddr := testGlobal.recipientInfo.addr
+	storeCall := proto.FunctionCall{Name: "store", Arguments: proto.Arguments{&proto.StringArgument{Value: "a"}, &proto.IntegerArgument{Value: 5}}}
+	tx := createInvokeScriptV1(t, storeCall, nil)
+	_, err := to.sc.invokeFunction(tx, dAppAddr, 1, false)
+	assert.Error(t, err, "invokeFunction() did not fail for account without script")
+
+	setTestAccountScript(t, to, dAppAddr, dAppScript)
+	res, err := to.sc.invokeFunction(tx, dAppAddr, 1, false)
+	assert.NoError(t, err, "invokeFunction() failed")
+	correctWrites := []proto.DataEntry{
+		proto.IntegerDataEntry{Key: "key_a", Value: 5},
+		proto.BinaryDataEntry{Key: "key_caller", Value: testGlobal.senderInfo.pk.Bytes()},
+	}
+	assert.Equal(t, correctWrites, res.Writes)
+	assert.Empty(t, res.Transfers)
+
+	withdrawCall := proto.FunctionCall{Name: "withdraw", Arguments: proto.Arguments{&proto.IntegerArgument{Value: 10}}}
+	tx = createInvokeScriptV1(t, withdrawCall, nil)
+	res, err = to.sc.invokeFunction(tx, dAppAddr, 1, false)
+	assert.NoError(t, err, "invokeFunction() failed")
+	assert.Empty(t, res.Writes)
+	assert.Equal(t, 1, len(res.Transfers))
+	assert.Equal(t, proto.NewRecipientFromAddress(testGlobal.senderInfo.addr), res.Transfers[0].Recipient)
+	assert.Equal(t, int64(10), res.Transfers[0].Amount)
+	assert.False(t, res.Transfers[0].Asset.Present)
+
+	tx = createInvokeScriptV1(t, proto.FunctionCall{Default: true}, nil)
+	_, err = to.sc.invokeFunction(tx, dAppAddr, 1, false)
+	assert.Error(t, err, "invokeFunction() did not fail for DApp without default function")
+
+	// Arguments are checked against types from script metadata.
+	setTestAccountScript(t, to, dAppAddr, dAppWithVerifierScript)
+	depositCall := proto.FunctionCall{Name: "deposit", Arguments: proto.Arguments{&proto.IntegerArgument{Value: 5}, &proto.StringArgument{Value: "note"}}}
+	res, err = to.sc.invokeFunction(createInvokeScriptV1(t, depositCall, nil), dAppAddr, 1, false)
+	assert.NoError(t, err, "invokeFunction() failed")
+	assert.Equal(t, []proto.DataEntry{proto.StringDataEntry{Key: "note", Value: "note"}}, res.Writes)
+	depositCall.Arguments = proto.Arguments{&proto.StringArgument{Value: "5"}, &proto.StringArgument{Value: "note"}}
+	_, err = to.sc.invokeFunction(createInvokeScriptV1(t, depositCall, nil), dAppAddr, 1, false)
+	assert.Error(t, err, "invokeFunction() did not fail with argument of wrong type")
+}
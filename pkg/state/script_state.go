@@ -0,0 +1,258 @@
+package state
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/mockstate"
+)
+
+// pendingTx is a transaction of block which is being added, it is not stored yet.
+type pendingTx struct {
+	tx     proto.Transaction
+	height uint64
+}
+
+// pendingChanges are changes of transactions which have been validated, but are not stored yet.
+type pendingChanges struct {
+	// diffStor holds balance changes, it is nil if there are no pending balance changes.
+	diffStor *diffStorage
+	// txs are transactions of blocks which are being added, by their IDs.
+	txs map[string]*pendingTx
+	// blocks are headers of blocks which are being added, by their heights.
+	blocks map[uint64]*proto.BlockHeader
+}
+
+func newPendingChanges() *pendingChanges {
+	return &pendingChanges{txs: make(map[string]*pendingTx), blocks: make(map[uint64]*proto.BlockHeader)}
+}
+
+func (p *pendingChanges) addTx(tx proto.Transaction, height uint64) error {
+	txID, err := tx.GetID()
+	if err != nil {
+		return err
+	}
+	p.txs[string(txID)] = &pendingTx{tx: tx, height: height}
+	return nil
+}
+
+// lastBalanceDiff returns cumulative balance diff of all pending changes for given key.
+func (p *pendingChanges) lastBalanceDiff(key []byte) (*balanceDiff, bool) {
+	if p.diffStor == nil {
+		return nil, false
+	}
+	changes, err := p.diffStor.balanceChanges(string(key))
+	if err != nil || len(changes.balanceDiffs) == 0 {
+		return nil, false
+	}
+	return &changes.balanceDiffs[len(changes.balanceDiffs)-1], true
+}
+
+func (p *pendingChanges) addBlock(header *proto.BlockHeader, height uint64) {
+	p.blocks[height] = header
+}
+
+func (p *pendingChanges) reset() {
+	p.diffStor = nil
+	p.txs = make(map[string]*pendingTx)
+	p.blocks = make(map[uint64]*proto.BlockHeader)
+}
+
+// scriptAccount provides account data to RIDE scripts.
+type scriptAccount struct {
+	state *scriptState
+	addr  proto.Address
+}
+
+func (a *scriptAccount) Data() []proto.DataEntry {
+	entries, err := a.state.stor.accountsDataStor.newestEntries(a.addr, a.state.filter)
+	if err != nil {
+		return nil
+	}
+	return entries
+}
+
+func (a *scriptAccount) DataEntry(key string) (proto.DataEntry, error) {
+	entry, err := a.state.stor.accountsDataStor.newestEntry(a.addr, key, a.state.filter)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist {
+		return nil, mockstate.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (a *scriptAccount) AssetBalance(asset *proto.OptionalAsset) uint64 {
+	if !asset.Present {
+		balance, err := a.state.wavesBalance(a.addr)
+		if err != nil {
+			return 0
+		}
+		return balance
+	}
+	balance, err := a.state.assetBalance(a.addr, asset.ID.Bytes())
+	if err != nil {
+		return 0
+	}
+	return balance
+}
+
+func (a *scriptAccount) Address() proto.Address {
+	return a.addr
+}
+
+// scriptState provides blockchain state to RIDE scripts.
+// Stored state is combined with pending changes of blocks or transactions which are being validated.
+type scriptState struct {
+	stor    *blockchainEntitiesStorage
+	pending *pendingChanges
+	filter  bool
+	scheme  byte
+}
+
+func (s *scriptState) wavesBalance(addr proto.Address) (uint64, error) {
+	profile, err := s.stor.balances.wavesBalance(addr, s.filter)
+	if err != nil {
+		return 0, err
+	}
+	key := wavesBalanceKey{address: addr}
+	diff, ok := s.pending.lastBalanceDiff(key.bytes())
+	if !ok {
+		return profile.balance, nil
+	}
+	newProfile, err := diff.applyTo(profile)
+	if err != nil {
+		return 0, err
+	}
+	return newProfile.balance, nil
+}
+
+func (s *scriptState) assetBalance(addr proto.Address, asset []byte) (uint64, error) {
+	balance, err := s.stor.balances.assetBalance(addr, asset, s.filter)
+	if err != nil {
+		return 0, err
+	}
+	key := assetBalanceKey{address: addr, asset: asset}
+	diff, ok := s.pending.lastBalanceDiff(key.bytes())
+	if !ok {
+		return balance, nil
+	}
+	return diff.applyToAssetBalance(balance)
+}
+
+func (s *scriptState) TransactionByID(id []byte) (proto.Transaction, error) {
+	if p, ok := s.pending.txs[string(id)]; ok {
+		return p.tx, nil
+	}
+	txBytes, err := s.stor.hs.rw.readTransaction(id)
+	if err == keyvalue.ErrNotFound {
+		return nil, mockstate.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bytesToStoredTransaction(txBytes)
+}
+
+func (s *scriptState) TransactionHeightByID(id []byte) (uint64, error) {
+	if p, ok := s.pending.txs[string(id)]; ok {
+		return p.height, nil
+	}
+	height, err := s.stor.hs.rw.transactionHeight(id)
+	if err == keyvalue.ErrNotFound {
+		return 0, mockstate.ErrNotFound
+	}
+	return height, err
+}
+
+func (s *scriptState) AssetInfo(assetID crypto.Digest) (*mockstate.AssetInfo, error) {
+	info, err := s.stor.assets.newestAssetInfo(assetID, s.filter)
+	if errors.Cause(err) == keyvalue.ErrNotFound || err == errEmptyHist {
+		return nil, mockstate.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	issuer, err := proto.NewAddressFromPublicKey(s.scheme, info.issuer)
+	if err != nil {
+		return nil, err
+	}
+	scripted, err := s.stor.scriptsStorage.newestIsSmartAsset(assetID, s.filter)
+	if err != nil {
+		return nil, err
+	}
+	sponsored, err := s.stor.assets.newestIsSponsored(assetID, s.filter)
+	if err != nil {
+		return nil, err
+	}
+	return &mockstate.AssetInfo{
+		ID:              assetID,
+		Quantity:        info.quantity.Int64(),
+		Decimals:        int64(info.decimals),
+		Issuer:          issuer,
+		IssuerPublicKey: info.issuer,
+		Reissuable:      info.reissuable,
+		Scripted:        scripted,
+		Sponsored:       sponsored,
+	}, nil
+}
+
+func (s *scriptState) blockInfo(header *proto.BlockHeader, height uint64) (*mockstate.BlockInfo, error) {
+	generator, err := proto.NewAddressFromPublicKey(s.scheme, header.GenPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &mockstate.BlockInfo{
+		Timestamp:           header.Timestamp,
+		Height:              height,
+		BaseTarget:          header.BaseTarget,
+		GenerationSignature: header.GenSignature,
+		Generator:           generator,
+		GeneratorPublicKey:  header.GenPublicKey,
+	}, nil
+}
+
+func (s *scriptState) BlockInfoByHeight(height uint64) (*mockstate.BlockInfo, error) {
+	if header, ok := s.pending.blocks[height]; ok {
+		return s.blockInfo(header, height)
+	}
+	rw := s.stor.hs.rw
+	maxHeight, err := rw.currentHeight()
+	if err != nil {
+		return nil, err
+	}
+	if height < 1 || height > maxHeight {
+		return nil, mockstate.ErrNotFound
+	}
+	blockID, err := rw.blockIDByHeight(height)
+	if err == io.EOF {
+		return nil, mockstate.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	headerBytes, err := rw.readBlockHeader(blockID)
+	if err != nil {
+		return nil, err
+	}
+	var header proto.BlockHeader
+	if err := header.UnmarshalHeaderFromBinary(headerBytes); err != nil {
+		return nil, err
+	}
+	return s.blockInfo(&header, height)
+}
+
+func (s *scriptState) Account(r proto.Recipient) (mockstate.Account, error) {
+	if r.Address != nil {
+		return &scriptAccount{state: s, addr: *r.Address}, nil
+	}
+	addr, err := s.stor.aliases.newestAddrByAlias(r.Alias.Alias, s.filter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find alias %s", r.Alias.String())
+	}
+	return &scriptAccount{state: s, addr: *addr}, nil
+}
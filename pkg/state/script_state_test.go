@@ -0,0 +1,186 @@
+package state
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/mockstate"
+	"github.com/wavesplatform/gowaves/pkg/util"
+)
+
+func TestScriptStateBalances(t *testing.T) {
+	to, path := createScriptCallerTestObjects(t)
+
+	defer func() {
+		err := to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	addr := testGlobal.senderInfo.addr
+	asset := testGlobal.asset0.asset
+	to.stor.addBlock(t, blockID0)
+	err := to.entities.balances.setWavesBalance(addr, &balanceProfile{balance: 100}, blockID0)
+	assert.NoError(t, err, "setWavesBalance() failed")
+	err = to.entities.balances.setAssetBalance(addr, asset.ID.Bytes(), 10, blockID0)
+	assert.NoError(t, err, "setAssetBalance() failed")
+	to.stor.flush(t)
+
+	account, err := to.sc.state(false).Account(proto.NewRecipientFromAddress(addr))
+	require.NoError(t, err, "Account() failed")
+	assert.Equal(t, uint64(100), account.AssetBalance(&proto.OptionalAsset{}))
+	assert.Equal(t, uint64(10), account.AssetBalance(asset))
+
+	// Pending changes are added to stored balances.
+	diffStor, err := newDiffStorage()
+	require.NoError(t, err)
+	wavesKey := wavesBalanceKey{address: addr}
+	err = diffStor.addBalanceDiff(string(wavesKey.bytes()), balanceDiff{balance: -30, blockID: blockID1})
+	assert.NoError(t, err, "addBalanceDiff() failed")
+	err = diffStor.addBalanceDiff(string(wavesKey.bytes()), balanceDiff{balance: 5, blockID: blockID1})
+	assert.NoError(t, err, "addBalanceDiff() failed")
+	assetKey := assetBalanceKey{address: addr, asset: asset.ID.Bytes()}
+	err = diffStor.addBalanceDiff(string(assetKey.bytes()), balanceDiff{balance: 7, blockID: blockID1})
+	assert.NoError(t, err, "addBalanceDiff() failed")
+	to.sc.pending.diffStor = diffStor
+	assert.Equal(t, uint64(75), account.AssetBalance(&proto.OptionalAsset{}))
+	assert.Equal(t, uint64(17), account.AssetBalance(asset))
+
+	to.sc.pending.reset()
+	assert.Equal(t, uint64(100), account.AssetBalance(&proto.OptionalAsset{}))
+}
+
+func TestScriptStateAccount(t *testing.T) {
+	to, path := createScriptCallerTestObjects(t)
+
+	defer func() {
+		err := to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	addr := testGlobal.senderInfo.addr
+	stored := proto.IntegerDataEntry{Key: "stored", Value: 1}
+	to.stor.addBlock(t, blockID0)
+	err := to.entities.accountsDataStor.appendEntry(addr, stored, blockID0)
+	assert.NoError(t, err, "appendEntry() failed")
+	err = to.entities.aliases.createAlias("alias", &aliasInfo{addr: addr}, blockID0)
+	assert.NoError(t, err, "createAlias() failed")
+	to.stor.flush(t)
+
+	// Entries and aliases which are not flushed yet are visible too.
+	to.stor.addBlock(t, blockID1)
+	fresh := proto.StringDataEntry{Key: "fresh", Value: "value"}
+	err = to.entities.accountsDataStor.appendEntry(addr, fresh, blockID1)
+	assert.NoError(t, err, "appendEntry() failed")
+	err = to.entities.aliases.createAlias("fresh", &aliasInfo{addr: addr}, blockID1)
+	assert.NoError(t, err, "createAlias() failed")
+
+	state := to.sc.state(false)
+	for _, alias := range []string{"alias", "fresh"} {
+		account, err := state.Account(proto.NewRecipientFromAlias(*proto.NewAlias('W', alias)))
+		require.NoError(t, err, "Account() failed")
+		assert.Equal(t, addr, account.Address())
+		assert.ElementsMatch(t, []proto.DataEntry{stored, fresh}, account.Data())
+		entry, err := account.DataEntry("stored")
+		assert.NoError(t, err, "DataEntry() failed")
+		assert.Equal(t, stored, entry)
+		entry, err = account.DataEntry("fresh")
+		assert.NoError(t, err, "DataEntry() failed")
+		assert.Equal(t, fresh, entry)
+		_, err = account.DataEntry("missing")
+		assert.Equal(t, mockstate.ErrNotFound, err)
+	}
+	_, err = state.Account(proto.NewRecipientFromAlias(*proto.NewAlias('W', "unknown")))
+	assert.Error(t, err, "Account() did not fail with unknown alias")
+}
+
+func TestScriptStateAssetInfo(t *testing.T) {
+	to, path := createScriptCallerTestObjects(t)
+
+	defer func() {
+		err := to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	asset := testGlobal.asset0.asset
+	state := to.sc.state(false)
+	_, err := state.AssetInfo(asset.ID)
+	assert.Equal(t, mockstate.ErrNotFound, err)
+
+	to.stor.addBlock(t, blockID0)
+	err = to.entities.assets.issueAsset(asset.ID, defaultAssetInfo(true), blockID0)
+	assert.NoError(t, err, "issueAsset() failed")
+	to.stor.flush(t)
+	info, err := state.AssetInfo(asset.ID)
+	require.NoError(t, err, "AssetInfo() failed")
+	assert.Equal(t, asset.ID, info.ID)
+	assert.True(t, info.Reissuable)
+}
+
+func TestScriptStateTransactions(t *testing.T) {
+	to, path := createScriptCallerTestObjects(t)
+
+	defer func() {
+		err := to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	sk, pk := crypto.GenerateKeyPair([]byte("script state test seed"))
+	stored := proto.NewUnsignedTransferV2(pk, proto.OptionalAsset{}, proto.OptionalAsset{}, defaultTimestamp, defaultAmount, defaultFee, proto.NewRecipientFromAddress(testGlobal.recipientInfo.addr), "stored")
+	err := stored.Sign(sk)
+	require.NoError(t, err)
+	storedID, err := stored.GetID()
+	require.NoError(t, err)
+	txBytes, err := stored.MarshalBinary()
+	require.NoError(t, err)
+	sizeBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(sizeBytes, uint32(len(txBytes)))
+
+	to.stor.addBlock(t, blockID0)
+	err = to.stor.rw.startBlock(blockID1)
+	assert.NoError(t, err, "startBlock() failed")
+	err = to.stor.rw.writeTransaction(storedID, append(sizeBytes, txBytes...))
+	assert.NoError(t, err, "writeTransaction() failed")
+	err = to.stor.rw.finishBlock(blockID1)
+	assert.NoError(t, err, "finishBlock() failed")
+	to.stor.flush(t)
+
+	pending := proto.NewUnsignedTransferV2(pk, proto.OptionalAsset{}, proto.OptionalAsset{}, defaultTimestamp, defaultAmount, defaultFee, proto.NewRecipientFromAddress(testGlobal.recipientInfo.addr), "pending")
+	err = pending.Sign(sk)
+	require.NoError(t, err)
+	pendingID, err := pending.GetID()
+	require.NoError(t, err)
+	err = to.sc.pending.addTx(pending, 3)
+	assert.NoError(t, err, "addTx() failed")
+
+	state := to.sc.state(false)
+	tx, err := state.TransactionByID(storedID)
+	assert.NoError(t, err, "TransactionByID() failed")
+	assert.Equal(t, stored, tx)
+	height, err := state.TransactionHeightByID(storedID)
+	assert.NoError(t, err, "TransactionHeightByID() failed")
+	assert.Equal(t, uint64(2), height)
+
+	tx, err = state.TransactionByID(pendingID)
+	assert.NoError(t, err, "TransactionByID() failed")
+	assert.Equal(t, pending, tx)
+	height, err = state.TransactionHeightByID(pendingID)
+	assert.NoError(t, err, "TransactionHeightByID() failed")
+	assert.Equal(t, uint64(3), height)
+
+	_, err = state.TransactionByID([]byte("unknown"))
+	assert.Equal(t, mockstate.ErrNotFound, err)
+	_, err = state.TransactionHeightByID([]byte("unknown"))
+	assert.Equal(t, mockstate.ErrNotFound, err)
+}
@@ -0,0 +1,238 @@
+package state
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/keyvalue"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/estimation"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/parser"
+)
+
+const (
+	// Complexity + hasVerifier + blockNum.
+	scriptRecordMinSize = 8 + 1 + 4
+)
+
+type scriptInfo struct {
+	// Empty script means that there is no script.
+	script     proto.Script
+	complexity uint64
+	// hasVerifier is computed once when script is set, so DApps are not parsed to check transactions.
+	hasVerifier bool
+}
+
+type scriptRecord struct {
+	scriptInfo
+	blockNum uint32
+}
+
+func (r *scriptRecord) marshalBinary() ([]byte, error) {
+	res := make([]byte, len(r.script)+scriptRecordMinSize)
+	copy(res, r.script)
+	binary.BigEndian.PutUint64(res[len(r.script):], r.complexity)
+	proto.PutBool(res[len(r.script)+8:], r.hasVerifier)
+	binary.BigEndian.PutUint32(res[len(r.script)+9:], r.blockNum)
+	return res, nil
+}
+
+func (r *scriptRecord) unmarshalBinary(data []byte) error {
+	if len(data) < scriptRecordMinSize {
+		return errors.New("invalid data size")
+	}
+	scriptLen := len(data) - scriptRecordMinSize
+	r.script = make([]byte, scriptLen)
+	copy(r.script, data[:scriptLen])
+	r.complexity = binary.BigEndian.Uint64(data[scriptLen : scriptLen+8])
+	hasVerifier, err := proto.Bool(data[scriptLen+8 : scriptLen+9])
+	if err != nil {
+		return err
+	}
+	r.hasVerifier = hasVerifier
+	r.blockNum = binary.BigEndian.Uint32(data[scriptLen+9:])
+	return nil
+}
+
+type scriptsStorage struct {
+	db      keyvalue.IterableKeyVal
+	dbBatch keyvalue.Batch
+	stateDB *stateDB
+	hs      *historyStorage
+}
+
+func newScriptsStorage(db keyvalue.IterableKeyVal, dbBatch keyvalue.Batch, stateDB *stateDB, hs *historyStorage) (*scriptsStorage, error) {
+	return &scriptsStorage{db, dbBatch, stateDB, hs}, nil
+}
+
+func (ss *scriptsStorage) setScript(entity blockchainEntity, key []byte, info *scriptInfo, blockID crypto.Signature) error {
+	blockNum, err := ss.stateDB.blockIdToNum(blockID)
+	if err != nil {
+		return err
+	}
+	r := &scriptRecord{*info, blockNum}
+	recordBytes, err := r.marshalBinary()
+	if err != nil {
+		return errors.Errorf("failed to marshal record: %v\n", err)
+	}
+	return ss.hs.set(entity, key, recordBytes)
+}
+
+func (ss *scriptsStorage) newestScriptInfoByKey(entity blockchainEntity, key []byte, filter bool) (*scriptInfo, error) {
+	recordBytes, err := ss.hs.getFresh(entity, key, filter)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist {
+		// No script.
+		return &scriptInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record scriptRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return nil, errors.Errorf("failed to unmarshal record: %v\n", err)
+	}
+	return &record.scriptInfo, nil
+}
+
+func (ss *scriptsStorage) scriptInfoByKey(entity blockchainEntity, key []byte, filter bool) (*scriptInfo, error) {
+	recordBytes, err := ss.hs.get(entity, key, filter)
+	if err == keyvalue.ErrNotFound || err == errEmptyHist {
+		// No script.
+		return &scriptInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record scriptRecord
+	if err := record.unmarshalBinary(recordBytes); err != nil {
+		return nil, errors.Errorf("failed to unmarshal record: %v\n", err)
+	}
+	return &record.scriptInfo, nil
+}
+
+func (ss *scriptsStorage) setAccountScript(addr proto.Address, info *scriptInfo, blockID crypto.Signature) error {
+	key := accountScriptKey{addr}
+	return ss.setScript(accountScript, key.bytes(), info, blockID)
+}
+
+// Account script info from DB or local storage.
+func (ss *scriptsStorage) newestAccountScriptInfo(addr proto.Address, filter bool) (*scriptInfo, error) {
+	key := accountScriptKey{addr}
+	return ss.newestScriptInfoByKey(accountScript, key.bytes(), filter)
+}
+
+// scriptHasVerifier tells if account script verifies transactions: expression scripts always do,
+// DApps only if they have verifier function.
+func scriptHasVerifier(script proto.Script) (bool, error) {
+	if len(script) == 0 {
+		return false, nil
+	}
+	if !parser.IsContract(script) {
+		return true, nil
+	}
+	dApp, err := parser.LoadContract(script)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to build DApp")
+	}
+	return dApp.HasVerifier(), nil
+}
+
+// scriptEstimation is the result of script complexity estimation.
+type scriptEstimation struct {
+	version     int
+	isDApp      bool
+	hasVerifier bool
+	complexity  uint64
+}
+
+// estimateScript parses script and estimates its complexity, for DApps it is the maximal complexity of its functions.
+func estimateScript(script proto.Script) (*scriptEstimation, error) {
+	if !parser.IsContract(script) {
+		s, err := parser.LoadScript(script)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build script")
+		}
+		complexity, err := estimation.EstimateScript(s)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to estimate script complexity")
+		}
+		return &scriptEstimation{version: s.Version, hasVerifier: true, complexity: uint64(complexity)}, nil
+	}
+	dApp, err := parser.LoadContract(script)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build DApp")
+	}
+	complexity, err := estimation.EstimateContract(dApp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to estimate DApp complexity")
+	}
+	return &scriptEstimation{version: dApp.Version, isDApp: true, hasVerifier: dApp.HasVerifier(), complexity: uint64(complexity.Max)}, nil
+}
+
+// newScriptInfo returns info of script with estimated complexity and verifier presence, empty script means removing of script.
+func newScriptInfo(script proto.Script) (*scriptInfo, error) {
+	if len(script) == 0 {
+		return &scriptInfo{script: script}, nil
+	}
+	e, err := estimateScript(script)
+	if err != nil {
+		return nil, err
+	}
+	return &scriptInfo{script: script, complexity: e.complexity, hasVerifier: e.hasVerifier}, nil
+}
+
+func (ss *scriptsStorage) newestAccountHasVerifier(addr proto.Address, filter bool) (bool, error) {
+	info, err := ss.newestAccountScriptInfo(addr, filter)
+	if err != nil {
+		return false, err
+	}
+	return info.hasVerifier, nil
+}
+
+// Stable account script info from DB.
+func (ss *scriptsStorage) accountScriptInfo(addr proto.Address, filter bool) (*scriptInfo, error) {
+	key := accountScriptKey{addr}
+	return ss.scriptInfoByKey(accountScript, key.bytes(), filter)
+}
+
+func (ss *scriptsStorage) accountHasVerifier(addr proto.Address, filter bool) (bool, error) {
+	info, err := ss.accountScriptInfo(addr, filter)
+	if err != nil {
+		return false, err
+	}
+	return info.hasVerifier, nil
+}
+
+func (ss *scriptsStorage) setAssetScript(assetID crypto.Digest, info *scriptInfo, blockID crypto.Signature) error {
+	key := assetScriptKey{assetID}
+	return ss.setScript(assetScript, key.bytes(), info, blockID)
+}
+
+// Asset script info from DB or local storage.
+func (ss *scriptsStorage) newestAssetScriptInfo(assetID crypto.Digest, filter bool) (*scriptInfo, error) {
+	key := assetScriptKey{assetID}
+	return ss.newestScriptInfoByKey(assetScript, key.bytes(), filter)
+}
+
+func (ss *scriptsStorage) newestIsSmartAsset(assetID crypto.Digest, filter bool) (bool, error) {
+	info, err := ss.newestAssetScriptInfo(assetID, filter)
+	if err != nil {
+		return false, err
+	}
+	return len(info.script) != 0, nil
+}
+
+// Stable asset script info from DB.
+func (ss *scriptsStorage) assetScriptInfo(assetID crypto.Digest, filter bool) (*scriptInfo, error) {
+	key := assetScriptKey{assetID}
+	return ss.scriptInfoByKey(assetScript, key.bytes(), filter)
+}
+
+func (ss *scriptsStorage) isSmartAsset(assetID crypto.Digest, filter bool) (bool, error) {
+	info, err := ss.assetScriptInfo(assetID, filter)
+	if err != nil {
+		return false, err
+	}
+	return len(info.script) != 0, nil
+}
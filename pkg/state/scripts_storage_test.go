@@ -0,0 +1,135 @@
+package state
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wavesplatform/gowaves/pkg/util"
+)
+
+type scriptsStorageTestObjects struct {
+	stor           *storageObjects
+	scriptsStorage *scriptsStorage
+}
+
+func createScriptsStorageTestObjects() (*scriptsStorageTestObjects, []string, error) {
+	stor, path, err := createStorageObjects()
+	if err != nil {
+		return nil, path, err
+	}
+	scriptsStorage, err := newScriptsStorage(stor.db, stor.dbBatch, stor.stateDB, stor.hs)
+	if err != nil {
+		return nil, path, err
+	}
+	return &scriptsStorageTestObjects{stor, scriptsStorage}, path, nil
+}
+
+func TestSetAccountScript(t *testing.T) {
+	to, path, err := createScriptsStorageTestObjects()
+	assert.NoError(t, err, "createScriptsStorageTestObjects() failed")
+
+	defer func() {
+		err = to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	addr := testGlobal.senderInfo.addr
+	// Account without script.
+	hasVerifier, err := to.scriptsStorage.newestAccountHasVerifier(addr, true)
+	assert.NoError(t, err, "newestAccountHasVerifier() failed")
+	assert.Equal(t, false, hasVerifier)
+
+	to.stor.addBlock(t, blockID0)
+	info := &scriptInfo{script: []byte{1, 6, 183, 111, 203, 71}, complexity: 1, hasVerifier: true}
+	err = to.scriptsStorage.setAccountScript(addr, info, blockID0)
+	assert.NoError(t, err, "setAccountScript() failed")
+	newest, err := to.scriptsStorage.newestAccountScriptInfo(addr, true)
+	assert.NoError(t, err, "newestAccountScriptInfo() failed")
+	assert.Equal(t, info, newest)
+	hasVerifier, err = to.scriptsStorage.accountHasVerifier(addr, true)
+	assert.NoError(t, err, "accountHasVerifier() failed")
+	assert.Equal(t, false, hasVerifier, "script is stable before flush")
+	to.stor.flush(t)
+	stable, err := to.scriptsStorage.accountScriptInfo(addr, true)
+	assert.NoError(t, err, "accountScriptInfo() failed")
+	assert.Equal(t, info, stable)
+	hasVerifier, err = to.scriptsStorage.accountHasVerifier(addr, true)
+	assert.NoError(t, err, "accountHasVerifier() failed")
+	assert.Equal(t, true, hasVerifier)
+
+	// Rollback removes the script.
+	err = to.stor.stateDB.rollbackBlock(blockID0)
+	assert.NoError(t, err, "rollbackBlock() failed")
+	hasVerifier, err = to.scriptsStorage.accountHasVerifier(addr, true)
+	assert.NoError(t, err, "accountHasVerifier() failed")
+	assert.Equal(t, false, hasVerifier, "script was not removed by rollback")
+}
+
+func TestScriptHasVerifier(t *testing.T) {
+	for _, c := range []struct {
+		script      string
+		hasVerifier bool
+	}{
+		{"", false},
+		{trueScript, true},
+		{dAppWithVerifierScript, true},
+		{dAppWithoutVerifierScript, false},
+	} {
+		script, err := base64.StdEncoding.DecodeString(c.script)
+		assert.NoError(t, err, "DecodeString() failed")
+		hasVerifier, err := scriptHasVerifier(script)
+		assert.NoError(t, err, "scriptHasVerifier() failed")
+		assert.Equal(t, c.hasVerifier, hasVerifier)
+		info, err := newScriptInfo(script)
+		assert.NoError(t, err, "newScriptInfo() failed")
+		assert.Equal(t, c.hasVerifier, info.hasVerifier)
+	}
+}
+
+func TestSetAssetScript(t *testing.T) {
+	to, path, err := createScriptsStorageTestObjects()
+	assert.NoError(t, err, "createScriptsStorageTestObjects() failed")
+
+	defer func() {
+		err = to.stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	assetID := testGlobal.asset0.asset.ID
+	// Asset without script.
+	isSmart, err := to.scriptsStorage.newestIsSmartAsset(assetID, true)
+	assert.NoError(t, err, "newestIsSmartAsset() failed")
+	assert.Equal(t, false, isSmart)
+
+	to.stor.addBlock(t, blockID0)
+	info := &scriptInfo{script: []byte{1, 6, 183, 111, 203, 71}}
+	err = to.scriptsStorage.setAssetScript(assetID, info, blockID0)
+	assert.NoError(t, err, "setAssetScript() failed")
+	newest, err := to.scriptsStorage.newestAssetScriptInfo(assetID, true)
+	assert.NoError(t, err, "newestAssetScriptInfo() failed")
+	assert.Equal(t, info, newest)
+	to.stor.flush(t)
+	isSmart, err = to.scriptsStorage.isSmartAsset(assetID, true)
+	assert.NoError(t, err, "isSmartAsset() failed")
+	assert.Equal(t, true, isSmart)
+
+	// Replace the script in the next block and roll it back.
+	to.stor.addBlock(t, blockID1)
+	newInfo := &scriptInfo{script: []byte{1, 7, 222, 98, 89, 122}}
+	err = to.scriptsStorage.setAssetScript(assetID, newInfo, blockID1)
+	assert.NoError(t, err, "setAssetScript() failed")
+	to.stor.flush(t)
+	stable, err := to.scriptsStorage.assetScriptInfo(assetID, true)
+	assert.NoError(t, err, "assetScriptInfo() failed")
+	assert.Equal(t, newInfo, stable)
+	err = to.stor.stateDB.rollbackBlock(blockID1)
+	assert.NoError(t, err, "rollbackBlock() failed")
+	stable, err = to.scriptsStorage.assetScriptInfo(assetID, true)
+	assert.NoError(t, err, "assetScriptInfo() failed")
+	assert.Equal(t, info, stable)
+}
@@ -36,14 +36,18 @@ func wrapErr(stateErrorType ErrorType, err error) error {
 }
 
 type blockchainEntitiesStorage struct {
-	hs         *historyStorage
-	aliases    *aliases
-	assets     *assets
-	leases     *leases
-	scores     *scores
-	blocksInfo *blocksInfo
-	balances   *balances
-	features   *features
+	hs               *historyStorage
+	aliases          *aliases
+	assets           *assets
+	leases           *leases
+	scores           *scores
+	blocksInfo       *blocksInfo
+	balances         *balances
+	features         *features
+	accountsDataStor *accountsDataStorage
+	scriptsStorage   *scriptsStorage
+	invokeResults    *invokeResults
+	ordersVolumes    *ordersVolumes
 }
 
 func newBlockchainEntitiesStorage(hs *historyStorage, stateDB *stateDB, sets *settings.BlockchainSettings) (*blockchainEntitiesStorage, error) {
@@ -55,7 +59,7 @@ func newBlockchainEntitiesStorage(hs *historyStorage, stateDB *stateDB, sets *se
 	if err != nil {
 		return nil, err
 	}
-	leases, err := newLeases(hs.db, stateDB, hs)
+	leases, err := newLeases(hs.db, hs.dbBatch, stateDB, hs)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +71,7 @@ func newBlockchainEntitiesStorage(hs *historyStorage, stateDB *stateDB, sets *se
 	if err != nil {
 		return nil, err
 	}
-	balances, err := newBalances(hs.db, stateDB, hs)
+	balances, err := newBalances(hs.db, hs.dbBatch, stateDB, hs)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +79,19 @@ func newBlockchainEntitiesStorage(hs *historyStorage, stateDB *stateDB, sets *se
 	if err != nil {
 		return nil, err
 	}
-	return &blockchainEntitiesStorage{hs, aliases, assets, leases, scores, blocksInfo, balances, features}, nil
+	accountsDataStor, err := newAccountsDataStorage(hs.db, hs.dbBatch, stateDB, hs)
+	if err != nil {
+		return nil, err
+	}
+	scriptsStorage, err := newScriptsStorage(hs.db, hs.dbBatch, stateDB, hs)
+	if err != nil {
+		return nil, err
+	}
+	ordersVolumes, err := newOrdersVolumes(stateDB, hs)
+	if err != nil {
+		return nil, err
+	}
+	return &blockchainEntitiesStorage{hs, aliases, assets, leases, scores, blocksInfo, balances, features, accountsDataStor, scriptsStorage, newInvokeResults(), ordersVolumes}, nil
 }
 
 func (s *blockchainEntitiesStorage) reset() {
@@ -91,8 +107,12 @@ type txAppender struct {
 	// rw is needed to check for duplicate tx IDs.
 	rw *blockReadWriter
 
+	stor     *blockchainEntitiesStorage
 	settings *settings.BlockchainSettings
 
+	// scriptCaller is used to run account scripts, it is shared with transaction checker.
+	sc *scriptCaller
+
 	// TransactionHandler is handler for any operations on transactions.
 	txHandler *transactionHandler
 	// Block differ is used to create diffs from blocks.
@@ -107,16 +127,24 @@ type txAppender struct {
 	// Ids of all transactions whose diffs are currently in diffStorNoBlocks.
 	// This is needed to check that transaction ids are unique.
 	noBlocksTxIds map[string]struct{}
+	// Volumes of orders filled by transactions which are validated without blocks.
+	ordersVolumesNoBlocks *ordersVolumesDiff
 	// diffApplier is used to both validate and apply balance diffs.
 	diffApplier *diffApplier
+	// addressTransactions is nil if index of transactions by addresses is not stored.
+	addressTransactions *addressTransactions
 }
 
-func newTxAppender(rw *blockReadWriter, stor *blockchainEntitiesStorage, settings *settings.BlockchainSettings) (*txAppender, error) {
+func newTxAppender(rw *blockReadWriter, stor *blockchainEntitiesStorage, settings *settings.BlockchainSettings, addressTransactions *addressTransactions) (*txAppender, error) {
 	genesis, err := settings.GenesisGetter.Get()
 	if err != nil {
 		return nil, err
 	}
-	txHandler, err := newTransactionHandler(genesis.BlockSignature, stor, settings)
+	sc, err := newScriptCaller(stor, settings)
+	if err != nil {
+		return nil, err
+	}
+	txHandler, err := newTransactionHandler(genesis.BlockSignature, stor, settings, sc)
 	if err != nil {
 		return nil, err
 	}
@@ -138,14 +166,18 @@ func newTxAppender(rw *blockReadWriter, stor *blockchainEntitiesStorage, setting
 	}
 	return &txAppender{
 		rw:                     rw,
+		stor:                   stor,
 		settings:               settings,
+		sc:                     sc,
 		txHandler:              txHandler,
 		blockDiffer:            blockDiffer,
 		appendedBlocksTxIds:    make(map[string]struct{}),
 		diffStorAppendedBlocks: diffStorAppendedBlocks,
 		noBlocksTxIds:          make(map[string]struct{}),
 		diffStorNoBlocks:       diffStorNoBlocks,
+		ordersVolumesNoBlocks:  newOrdersVolumesDiff(),
 		diffApplier:            diffApplier,
+		addressTransactions:    addressTransactions,
 	}, nil
 }
 
@@ -179,6 +211,71 @@ func (a *txAppender) checkDuplicateTxIds(tx proto.Transaction, recentIds map[str
 	return a.checkDuplicateTxIdsImpl(txID, recentIds)
 }
 
+func (a *txAppender) senderAddress(tx proto.Transaction) (*proto.Address, bool, error) {
+	senderPK, ok := txSenderPK(tx)
+	if !ok {
+		return nil, false, nil
+	}
+	senderAddr, err := proto.NewAddressFromPublicKey(a.settings.AddressSchemeCharacter, senderPK)
+	if err != nil {
+		return nil, false, err
+	}
+	return &senderAddr, true, nil
+}
+
+// senderHasVerifier checks if transaction is sent from scripted account.
+func (a *txAppender) senderHasVerifier(tx proto.Transaction, initialisation bool) (bool, error) {
+	senderAddr, ok, err := a.senderAddress(tx)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, nil
+	}
+	return a.stor.scriptsStorage.newestAccountHasVerifier(*senderAddr, !initialisation)
+}
+
+// verifyWithAccountScript checks transaction from scripted account using account script instead of signature.
+func (a *txAppender) verifyWithAccountScript(tx proto.Transaction, height uint64, initialisation bool) error {
+	if !isProvenTx(tx) {
+		return errors.New("transaction with signature can not be sent from scripted account")
+	}
+	senderAddr, _, err := a.senderAddress(tx)
+	if err != nil {
+		return err
+	}
+	if err := a.sc.callAccountScriptWithTx(tx, *senderAddr, height, initialisation); err != nil {
+		return errors.Wrap(err, "account script verification failed")
+	}
+	return nil
+}
+
+// needToCheckTxSig tells if verifier should check tx signature, transactions from scripted accounts are checked by scripts.
+// blockScripts holds script presence of accounts which have set scripts earlier in the current block.
+func (a *txAppender) needToCheckTxSig(tx proto.Transaction, blockScripts map[proto.Address]bool, initialisation bool) (bool, error) {
+	senderAddr, ok, err := a.senderAddress(tx)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return true, nil
+	}
+	hasVerifier, ok := blockScripts[*senderAddr]
+	if !ok {
+		hasVerifier, err = a.stor.scriptsStorage.newestAccountHasVerifier(*senderAddr, !initialisation)
+		if err != nil {
+			return false, err
+		}
+	}
+	if setScript, ok := tx.(*proto.SetScriptV1); ok {
+		blockScripts[*senderAddr], err = scriptHasVerifier(setScript.Script)
+		if err != nil {
+			return false, err
+		}
+	}
+	return !hasVerifier, nil
+}
+
 type appendBlockParams struct {
 	transactions   []proto.Transaction
 	block, parent  *proto.BlockHeader
@@ -188,12 +285,23 @@ type appendBlockParams struct {
 
 func (a *txAppender) appendBlock(params *appendBlockParams) error {
 	hasParent := (params.parent != nil)
-	for _, tx := range params.transactions {
+	// Scripts see balance changes of previous transactions of this block and of blocks which are not applied yet.
+	a.sc.pending.diffStor = a.diffStorAppendedBlocks
+	minerDiff, err := a.blockDiffer.createMinerDiff(params.block, hasParent)
+	if err != nil {
+		return err
+	}
+	if err := a.diffStorAppendedBlocks.saveTxDiff(minerDiff); err != nil {
+		return err
+	}
+	// Block is added at the next height after the current one.
+	blockHeight := params.height + 1
+	for i, tx := range params.transactions {
 		checkerInfo := &checkerInfo{
 			initialisation:   params.initialisation,
 			currentTimestamp: params.block.Timestamp,
 			blockID:          params.block.BlockSignature,
-			height:           params.height,
+			height:           blockHeight,
 		}
 		if err := a.checkDuplicateTxIds(tx, a.appendedBlocksTxIds, params.block.Timestamp); err != nil {
 			return err
@@ -207,27 +315,47 @@ func (a *txAppender) appendBlock(params *appendBlockParams) error {
 		if hasParent {
 			checkerInfo.parentTimestamp = params.parent.Timestamp
 		}
+		hasVerifier, err := a.senderHasVerifier(tx, params.initialisation)
+		if err != nil {
+			return err
+		}
+		if hasVerifier {
+			if err := a.verifyWithAccountScript(tx, blockHeight, params.initialisation); err != nil {
+				return err
+			}
+		}
 		if err := a.txHandler.checkTx(tx, checkerInfo); err != nil {
 			return err
 		}
 		if err := a.txHandler.performTx(tx, &performerInfo{params.initialisation, params.block.BlockSignature}); err != nil {
 			return err
 		}
+		diff, err := a.blockDiffer.createTransactionDiff(tx, params.block, blockHeight, params.initialisation)
+		if err != nil {
+			return err
+		}
+		if err := a.diffStorAppendedBlocks.saveTxDiff(diff); err != nil {
+			return err
+		}
+		if a.addressTransactions != nil {
+			if err := a.addressTransactions.saveTransaction(tx, params.block.BlockSignature, uint32(i), !params.initialisation); err != nil {
+				return errors.Wrap(err, "failed to save transaction to address transactions")
+			}
+		}
+		if err := a.sc.pending.addTx(tx, blockHeight); err != nil {
+			return err
+		}
 	}
-	blockDiff, err := a.blockDiffer.createBlockDiff(params.transactions, params.block, params.initialisation, hasParent)
-	if err != nil {
-		return err
-	}
-	if err := a.diffStorAppendedBlocks.saveBlockDiff(blockDiff); err != nil {
-		return err
-	}
-	return nil
+	// Blocks are not readable from storage until they are flushed, so next blocks take their headers from here.
+	a.sc.pending.addBlock(params.block, blockHeight)
+	return a.blockDiffer.finishBlock(params.block)
 }
 
 func (a *txAppender) applyAllDiffs(initialisation bool) error {
 	changes := a.diffStorAppendedBlocks.allChanges()
 	a.appendedBlocksTxIds = make(map[string]struct{})
 	a.diffStorAppendedBlocks.reset()
+	a.sc.pending.reset()
 	if err := a.diffApplier.applyBalancesChanges(changes, !initialisation); err != nil {
 		return err
 	}
@@ -235,20 +363,45 @@ func (a *txAppender) applyAllDiffs(initialisation bool) error {
 }
 
 func (a *txAppender) validateSingleTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64) error {
+	a.sc.pending.diffStor = nil
 	dummy := make(map[string]struct{})
 	if err := a.checkDuplicateTxIds(tx, dummy, currentTimestamp); err != nil {
 		return err
 	}
 	// Check tx signature and data.
-	if err := checkTx(tx); err != nil {
+	hasVerifier, err := a.senderHasVerifier(tx, false)
+	if err != nil {
 		return err
 	}
+	if err := checkTx(tx, !hasVerifier); err != nil {
+		return err
+	}
+	// Transaction is validated at the height of the block it is going to be added to.
+	curHeight, err := a.rw.currentHeight()
+	if err != nil {
+		return err
+	}
+	height := curHeight + 1
+	if hasVerifier {
+		if err := a.verifyWithAccountScript(tx, height, false); err != nil {
+			return err
+		}
+	}
+	txID, err := tx.GetID()
+	if err != nil {
+		return err
+	}
+	// Checker saves results of invocations, single transaction is never applied, so they are dropped in any case.
+	defer a.stor.invokeResults.removeResult(txID)
 	// Check tx data against state.
-	checkerInfo := &checkerInfo{initialisation: false, currentTimestamp: currentTimestamp, parentTimestamp: parentTimestamp}
+	checkerInfo := &checkerInfo{initialisation: false, currentTimestamp: currentTimestamp, parentTimestamp: parentTimestamp, height: height}
 	if err := a.txHandler.checkTx(tx, checkerInfo); err != nil {
 		return err
 	}
-	diff, err := a.txHandler.createDiffTx(tx, &differInfo{initialisation: false, blockTime: currentTimestamp})
+	if err := checkMinFee(a.stor, a.settings.AddressSchemeCharacter, tx, height, true); err != nil {
+		return err
+	}
+	diff, err := a.txHandler.createDiffTx(tx, &differInfo{initialisation: false, blockTime: currentTimestamp, height: height})
 	if err != nil {
 		return err
 	}
@@ -261,9 +414,13 @@ func (a *txAppender) validateSingleTx(tx proto.Transaction, currentTimestamp, pa
 func (a *txAppender) resetValidationList() {
 	a.noBlocksTxIds = make(map[string]struct{})
 	a.diffStorNoBlocks.reset()
+	a.ordersVolumesNoBlocks.reset()
+	a.stor.invokeResults.reset()
 }
 
 func (a *txAppender) validateNextTx(tx proto.Transaction, currentTimestamp, parentTimestamp uint64) error {
+	// Scripts see balance changes of previously validated transactions.
+	a.sc.pending.diffStor = a.diffStorNoBlocks
 	if err := a.checkDuplicateTxIds(tx, a.noBlocksTxIds, currentTimestamp); err != nil {
 		return err
 	}
@@ -274,15 +431,39 @@ func (a *txAppender) validateNextTx(tx proto.Transaction, currentTimestamp, pare
 	}
 	a.noBlocksTxIds[string(txID)] = empty
 	// Check tx signature and data.
-	if err := checkTx(tx); err != nil {
+	hasVerifier, err := a.senderHasVerifier(tx, false)
+	if err != nil {
+		return err
+	}
+	if err := checkTx(tx, !hasVerifier); err != nil {
+		return err
+	}
+	// Transaction is validated at the height of the block it is going to be added to.
+	curHeight, err := a.rw.currentHeight()
+	if err != nil {
 		return err
 	}
+	height := curHeight + 1
+	if hasVerifier {
+		if err := a.verifyWithAccountScript(tx, height, false); err != nil {
+			return err
+		}
+	}
 	// Check tx data against state.
-	checkerInfo := &checkerInfo{initialisation: false, currentTimestamp: currentTimestamp, parentTimestamp: parentTimestamp}
+	checkerInfo := &checkerInfo{
+		initialisation:   false,
+		currentTimestamp: currentTimestamp,
+		parentTimestamp:  parentTimestamp,
+		height:           height,
+		pendingVolumes:   a.ordersVolumesNoBlocks,
+	}
 	if err := a.txHandler.checkTx(tx, checkerInfo); err != nil {
 		return err
 	}
-	diff, err := a.txHandler.createDiffTx(tx, &differInfo{initialisation: false, blockTime: currentTimestamp})
+	if err := checkMinFee(a.stor, a.settings.AddressSchemeCharacter, tx, height, true); err != nil {
+		return err
+	}
+	diff, err := a.txHandler.createDiffTx(tx, &differInfo{initialisation: false, blockTime: currentTimestamp, height: height})
 	if err != nil {
 		return err
 	}
@@ -296,13 +477,21 @@ func (a *txAppender) validateNextTx(tx proto.Transaction, currentTimestamp, pare
 	if err := a.diffStorNoBlocks.saveBalanceChanges(changes); err != nil {
 		return err
 	}
+	if tx.GetTypeVersion().Type == proto.ExchangeTransaction {
+		// Next exchanges of the same orders are checked against fills of this one.
+		if err := a.ordersVolumesNoBlocks.saveExchange(tx); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (a *txAppender) reset() {
 	a.appendedBlocksTxIds = make(map[string]struct{})
 	a.diffStorAppendedBlocks.reset()
+	a.sc.pending.reset()
 	a.blockDiffer.reset()
+	a.stor.invokeResults.reset()
 }
 
 type stateManager struct {
@@ -325,6 +514,8 @@ type stateManager struct {
 	// Miscellaneous/utility fields.
 	// Specifies how many goroutines will be run for verification of transactions and blocks signatures.
 	verificationGoroutinesNum int
+	// Indicates whether full history of blockchain entities is stored.
+	fullHistory bool
 	// Indicates whether lease cancellations were performed.
 	leasesCl0, leasesCl1, leasesCl2 bool
 	// The height when last features voting took place.
@@ -362,6 +553,14 @@ func newStateManager(dataDir string, params StateParams, settings *settings.Bloc
 	if err != nil {
 		return nil, wrapErr(Other, errors.Errorf("failed to create stateDB: %v\n", err))
 	}
+	sp := &storedParams{addressTransactions: params.StoreAddressTransactions, fullHistory: params.StoreFullHistory}
+	saved, err := stateDB.checkParams(sp)
+	if err != nil {
+		if err := db.Close(); err != nil {
+			return nil, wrapErr(ClosureError, err)
+		}
+		return nil, wrapErr(Other, errors.Errorf("incompatible state parameters: %v\n", err))
+	}
 	// rw is storage for blocks.
 	rw, err := newBlockReadWriter(blockStorageDir, params.OffsetLen, params.HeaderOffsetLen, db, dbBatch)
 	if err != nil {
@@ -370,7 +569,7 @@ func newStateManager(dataDir string, params StateParams, settings *settings.Bloc
 	if err := stateDB.syncRw(rw); err != nil {
 		return nil, wrapErr(Other, errors.Errorf("failed to sync block storage and DB: %v\n", err))
 	}
-	hs, err := newHistoryStorage(db, dbBatch, rw, stateDB)
+	hs, err := newHistoryStorage(db, dbBatch, rw, stateDB, params.StoreFullHistory)
 	if err != nil {
 		return nil, wrapErr(Other, errors.Errorf("failed to create history storage: %v\n", err))
 	}
@@ -378,7 +577,23 @@ func newStateManager(dataDir string, params StateParams, settings *settings.Bloc
 	if err != nil {
 		return nil, wrapErr(Other, errors.Errorf("failed to create blockchain entities storage: %v\n", err))
 	}
-	appender, err := newTxAppender(rw, stor, settings)
+	if !saved {
+		// DBs created before parameters were saved have no indexes of asset holders and leases of addresses.
+		if err := buildAssetHolders(db); err != nil {
+			return nil, wrapErr(Other, errors.Errorf("failed to build index of asset holders: %v\n", err))
+		}
+		if err := stor.leases.buildAddressLeases(); err != nil {
+			return nil, wrapErr(Other, errors.Errorf("failed to build index of address leases: %v\n", err))
+		}
+		if err := stateDB.saveParams(sp); err != nil {
+			return nil, wrapErr(ModificationError, err)
+		}
+	}
+	var addressTxs *addressTransactions
+	if params.StoreAddressTransactions {
+		addressTxs = newAddressTransactions(db, dbBatch, stateDB, stor, settings.AddressSchemeCharacter)
+	}
+	appender, err := newTxAppender(rw, stor, settings, addressTxs)
 	if err != nil {
 		return nil, wrapErr(Other, err)
 	}
@@ -390,6 +605,7 @@ func newStateManager(dataDir string, params StateParams, settings *settings.Bloc
 		peers:                     newPeerStorage(db),
 		appender:                  appender,
 		verificationGoroutinesNum: params.VerificationGoroutinesNum,
+		fullHistory:               params.StoreFullHistory,
 		mu:                        &sync.RWMutex{},
 	}
 	// Set fields which depend on state.
@@ -681,6 +897,8 @@ func (s *stateManager) addNewBlock(block, parent *proto.Block, initialisation bo
 	}
 	transactionsBytes := block.Transactions
 	var transactions []proto.Transaction
+	// Script presence of accounts which set scripts in this block.
+	blockScripts := make(map[proto.Address]bool)
 	for i := 0; i < block.TransactionCount; i++ {
 		n := int(binary.BigEndian.Uint32(transactionsBytes[0:4]))
 		if n+4 > len(transactionsBytes) {
@@ -692,10 +910,15 @@ func (s *stateManager) addNewBlock(block, parent *proto.Block, initialisation bo
 			return err
 		}
 		transactions = append(transactions, tx)
+		checkTxSig, err := s.appender.needToCheckTxSig(tx, blockScripts, initialisation)
+		if err != nil {
+			return err
+		}
 		// Send transaction for signature/data verification.
 		task := &verifyTask{
-			taskType: verifyTx,
-			tx:       tx,
+			taskType:   verifyTx,
+			tx:         tx,
+			checkTxSig: checkTxSig,
 		}
 		select {
 		case verifyError := <-chans.errChan:
@@ -1303,6 +1526,310 @@ func (s *stateManager) ApprovalHeight(featureID int16) (uint64, error) {
 	return height, nil
 }
 
+func (s *stateManager) RetrieveEntry(addr proto.Address, key string) (proto.DataEntry, error) {
+	entry, err := s.stor.accountsDataStor.retrieveEntry(addr, key, true)
+	if err != nil {
+		if err == keyvalue.ErrNotFound || err == errEmptyHist {
+			return nil, wrapErr(NotFoundError, err)
+		}
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return entry, nil
+}
+
+func (s *stateManager) RetrieveEntries(addr proto.Address) ([]proto.DataEntry, error) {
+	entries, err := s.stor.accountsDataStor.retrieveEntries(addr, true)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return entries, nil
+}
+
+func (s *stateManager) AccountScript(addr proto.Address) (proto.Script, error) {
+	info, err := s.stor.scriptsStorage.accountScriptInfo(addr, true)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return info.script, nil
+}
+
+func (s *stateManager) HasVerifier(addr proto.Address) (bool, error) {
+	hasVerifier, err := s.stor.scriptsStorage.accountHasVerifier(addr, true)
+	if err != nil {
+		return false, wrapErr(RetrievalError, err)
+	}
+	return hasVerifier, nil
+}
+
+func (s *stateManager) ScriptInfo(addr proto.Address) (*ScriptInfo, error) {
+	info, err := s.stor.scriptsStorage.accountScriptInfo(addr, true)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	res := &ScriptInfo{Script: info.script, Complexity: info.complexity}
+	if len(info.script) != 0 {
+		res.ExtraFee = scriptExtraFee
+	}
+	return res, nil
+}
+
+func (s *stateManager) AssetScript(assetID crypto.Digest) (proto.Script, error) {
+	info, err := s.stor.scriptsStorage.assetScriptInfo(assetID, true)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return info.script, nil
+}
+
+func newAssetInfo(assetID crypto.Digest, info *assetInfo) *AssetInfo {
+	return &AssetInfo{
+		ID:          assetID,
+		Issuer:      info.issuer,
+		Name:        info.name,
+		Description: info.description,
+		Decimals:    info.decimals,
+		Quantity:    info.quantity.Uint64(),
+		Reissuable:  info.reissuable,
+	}
+}
+
+func (s *stateManager) AddressAssets(addr proto.Address) ([]*AssetBalance, error) {
+	var res []*AssetBalance
+	err := s.stor.balances.iterateAssetBalances(addr, nil, true, func(assetID crypto.Digest, balance uint64) (bool, error) {
+		info, err := s.stor.assets.assetInfo(assetID, true)
+		if err != nil {
+			return false, err
+		}
+		res = append(res, &AssetBalance{Asset: *newAssetInfo(assetID, info), Balance: balance})
+		return true, nil
+	})
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return res, nil
+}
+
+func (s *stateManager) AddressNFTs(addr proto.Address, after []byte, limit int) ([]*AssetInfo, error) {
+	if limit <= 0 {
+		return nil, wrapErr(InvalidInputError, errors.New("limit should be positive"))
+	}
+	if after != nil && len(after) != crypto.DigestSize {
+		return nil, wrapErr(InvalidInputError, errors.New("invalid asset ID"))
+	}
+	var res []*AssetInfo
+	err := s.stor.balances.iterateAssetBalances(addr, after, true, func(assetID crypto.Digest, balance uint64) (bool, error) {
+		info, err := s.stor.assets.assetInfo(assetID, true)
+		if err != nil {
+			return false, err
+		}
+		if info.isNFT() {
+			res = append(res, newAssetInfo(assetID, info))
+		}
+		return len(res) < limit, nil
+	})
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return res, nil
+}
+
+func (s *stateManager) AssetDistribution(assetID crypto.Digest, height uint64, after *proto.Address, limit int) ([]*AssetHolder, error) {
+	if limit <= 0 {
+		return nil, wrapErr(InvalidInputError, errors.New("limit should be positive"))
+	}
+	maxHeight, err := s.Height()
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	minHeight := uint64(1)
+	if !s.fullHistory {
+		// Older history is cut.
+		minHeight, err = s.stateDB.getRollbackMinHeight()
+		if err != nil {
+			return nil, wrapErr(RetrievalError, err)
+		}
+	}
+	if minHeight == 0 {
+		minHeight = 1
+	}
+	if height < minHeight || height > maxHeight {
+		return nil, wrapErr(InvalidInputError, errors.Errorf("invalid height; valid range is: [%d, %d]", minHeight, maxHeight))
+	}
+	var res []*AssetHolder
+	err = s.stor.balances.iterateAssetHolders(assetID, after, func(addr proto.Address) (bool, error) {
+		balance, err := s.stor.balances.assetBalanceAtHeight(addr, assetID.Bytes(), height, true)
+		if err != nil {
+			return false, err
+		}
+		if balance != 0 {
+			res = append(res, &AssetHolder{Address: addr, Balance: balance})
+		}
+		return len(res) < limit, nil
+	})
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return res, nil
+}
+
+func (s *stateManager) LeaseInfo(leaseID crypto.Digest) (*LeaseInfo, error) {
+	l, err := s.stor.leases.leasingInfo(leaseID, true)
+	if err != nil {
+		if err == keyvalue.ErrNotFound || err == errEmptyHist {
+			return nil, wrapErr(NotFoundError, err)
+		}
+		return nil, wrapErr(RetrievalError, err)
+	}
+	// Lease has the same ID as transaction which created it.
+	height, err := s.TransactionHeightByID(leaseID.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &LeaseInfo{
+		ID:        leaseID,
+		Sender:    l.sender,
+		Recipient: l.recipient,
+		Amount:    l.leaseAmount,
+		Height:    height,
+		IsActive:  l.isActive,
+	}, nil
+}
+
+func (s *stateManager) ActiveLeases(addr proto.Address) ([]*LeaseInfo, error) {
+	ids, err := s.stor.leases.activeAddressLeases(addr)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	var res []*LeaseInfo
+	for _, id := range ids {
+		info, err := s.LeaseInfo(id)
+		if IsNotFound(err) {
+			// Lease was rolled back.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if info.IsActive {
+			res = append(res, info)
+		}
+	}
+	return res, nil
+}
+
+func (s *stateManager) OrderVolume(orderID []byte) (*OrderVolume, error) {
+	amount, fee, err := s.stor.ordersVolumes.filled(orderID, true)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return &OrderVolume{FilledAmount: amount, FilledFee: fee}, nil
+}
+
+// bytesToStoredTransaction deserializes transaction from block storage, transactions are stored with their sizes.
+func bytesToStoredTransaction(txBytes []byte) (proto.Transaction, error) {
+	if len(txBytes) < 4 {
+		return nil, errors.New("invalid transaction size")
+	}
+	return proto.BytesToTransaction(txBytes[4:])
+}
+
+func (s *stateManager) TransactionByID(id []byte) (proto.Transaction, error) {
+	txBytes, err := s.rw.readTransaction(id)
+	if err != nil {
+		if err == keyvalue.ErrNotFound {
+			return nil, wrapErr(NotFoundError, err)
+		}
+		return nil, wrapErr(RetrievalError, err)
+	}
+	tx, err := bytesToStoredTransaction(txBytes)
+	if err != nil {
+		return nil, wrapErr(DeserializationError, err)
+	}
+	return tx, nil
+}
+
+func (s *stateManager) TransactionHeightByID(id []byte) (uint64, error) {
+	height, err := s.rw.transactionHeight(id)
+	if err != nil {
+		if err == keyvalue.ErrNotFound {
+			return 0, wrapErr(NotFoundError, err)
+		}
+		return 0, wrapErr(RetrievalError, err)
+	}
+	return height, nil
+}
+
+func (s *stateManager) TransactionInfoByID(id []byte) (*TransactionInfo, error) {
+	tx, err := s.TransactionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	height, err := s.TransactionHeightByID(id)
+	if err != nil {
+		return nil, err
+	}
+	blockID, err := s.rw.blockIDByHeight(height)
+	if err != nil {
+		return nil, wrapErr(RetrievalError, err)
+	}
+	return &TransactionInfo{Transaction: tx, BlockID: blockID, Height: height}, nil
+}
+
+func (s *stateManager) IsTransactionConfirmed(id []byte) (bool, error) {
+	if _, err := s.TransactionHeightByID(id); err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *stateManager) AddressTransactions(addr proto.Address, after []byte, limit int) ([]*TransactionInfo, error) {
+	addressTxs := s.appender.addressTransactions
+	if addressTxs == nil {
+		return nil, wrapErr(RetrievalError, errors.New("address transactions are not stored"))
+	}
+	if limit <= 0 {
+		return nil, wrapErr(InvalidInputError, errors.New("limit should be positive"))
+	}
+	var afterBlockNum uint32
+	if after != nil {
+		info, err := s.TransactionInfoByID(after)
+		if err != nil {
+			return nil, err
+		}
+		afterBlockNum, err = s.stateDB.blockIdToNum(info.BlockID)
+		if err != nil {
+			return nil, wrapErr(RetrievalError, err)
+		}
+	}
+	records, err := addressTxs.transactions(addr, after, afterBlockNum, limit)
+	if err != nil {
+		if err == errTxNotInvolveAddress {
+			return nil, wrapErr(InvalidInputError, err)
+		}
+		return nil, wrapErr(RetrievalError, err)
+	}
+	res := make([]*TransactionInfo, len(records))
+	for i, r := range records {
+		tx, err := s.TransactionByID(r.txID)
+		if err != nil {
+			return nil, err
+		}
+		blockID, err := s.stateDB.blockNumToId(r.blockNum)
+		if err != nil {
+			return nil, wrapErr(RetrievalError, err)
+		}
+		height, err := s.rw.heightByBlockID(blockID)
+		if err != nil {
+			return nil, wrapErr(RetrievalError, err)
+		}
+		res[i] = &TransactionInfo{Transaction: tx, BlockID: blockID, Height: height}
+	}
+	return res, nil
+}
+
 func (s *stateManager) Close() error {
 	if err := s.rw.close(); err != nil {
 		return wrapErr(ClosureError, err)
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -18,6 +19,7 @@ import (
 	"github.com/wavesplatform/gowaves/pkg/importer"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"github.com/wavesplatform/gowaves/pkg/settings"
+	"github.com/wavesplatform/gowaves/pkg/util"
 )
 
 const (
@@ -397,6 +399,420 @@ func TestDisallowDuplicateTxIds(t *testing.T) {
 	assert.EqualError(t, err, expectedErrStr)
 }
 
+func TestTransactionByID(t *testing.T) {
+	blocksPath := blocksPath(t)
+	dataDir, err := ioutil.TempDir(os.TempDir(), "dataDir")
+	assert.NoError(t, err, "failed to create dir for test data")
+	manager, err := newStateManager(dataDir, DefaultStateParams(), settings.MainNetSettings)
+	assert.NoError(t, err, "newStateManager() failed")
+
+	defer func() {
+		err := manager.Close()
+		assert.NoError(t, err, "manager.Close() failed")
+		err = os.RemoveAll(dataDir)
+		assert.NoError(t, err, "failed to remove test data dirs")
+	}()
+
+	height := uint64(75)
+	err = importer.ApplyFromFile(manager, blocksPath, height, 1, false)
+	assert.NoError(t, err, "ApplyFromFile() failed")
+	// Look up all transactions of the last block with transactions.
+	var block *proto.Block
+	for h := height; h > 1; h-- {
+		block, err = manager.BlockByHeight(h)
+		require.NoError(t, err, "BlockByHeight() failed")
+		if block.TransactionCount > 0 {
+			height = h
+			break
+		}
+	}
+	require.NotZero(t, block.TransactionCount, "no blocks with transactions")
+	txs, err := proto.BytesToTransactions(block.TransactionCount, block.Transactions)
+	require.NoError(t, err, "BytesToTransactions() failed")
+	for _, expected := range txs {
+		id, err := expected.GetID()
+		require.NoError(t, err, "GetID() failed")
+		tx, err := manager.TransactionByID(id)
+		require.NoError(t, err, "TransactionByID() failed")
+		assert.Equal(t, expected, tx)
+		txHeight, err := manager.TransactionHeightByID(id)
+		require.NoError(t, err, "TransactionHeightByID() failed")
+		assert.Equal(t, height, txHeight)
+		info, err := manager.TransactionInfoByID(id)
+		require.NoError(t, err, "TransactionInfoByID() failed")
+		assert.Equal(t, &TransactionInfo{Transaction: expected, BlockID: block.BlockSignature, Height: height}, info)
+		confirmed, err := manager.IsTransactionConfirmed(id)
+		require.NoError(t, err, "IsTransactionConfirmed() failed")
+		assert.True(t, confirmed)
+	}
+
+	unknownID := make([]byte, crypto.DigestSize)
+	_, err = manager.TransactionByID(unknownID)
+	assert.True(t, IsNotFound(err))
+	_, err = manager.TransactionHeightByID(unknownID)
+	assert.True(t, IsNotFound(err))
+	_, err = manager.TransactionInfoByID(unknownID)
+	assert.True(t, IsNotFound(err))
+	confirmed, err := manager.IsTransactionConfirmed(unknownID)
+	require.NoError(t, err, "IsTransactionConfirmed() failed")
+	assert.False(t, confirmed)
+}
+
+func TestStateManagerAddressTransactions(t *testing.T) {
+	blocksPath := blocksPath(t)
+	dataDir, err := ioutil.TempDir(os.TempDir(), "dataDir")
+	assert.NoError(t, err, "failed to create dir for test data")
+	params := DefaultStateParams()
+	params.StoreAddressTransactions = true
+	manager, err := newStateManager(dataDir, params, settings.MainNetSettings)
+	assert.NoError(t, err, "newStateManager() failed")
+
+	defer func() {
+		err := manager.Close()
+		assert.NoError(t, err, "manager.Close() failed")
+		err = os.RemoveAll(dataDir)
+		assert.NoError(t, err, "failed to remove test data dirs")
+	}()
+
+	height := uint64(75)
+	err = importer.ApplyFromFile(manager, blocksPath, height, 1, false)
+	assert.NoError(t, err, "ApplyFromFile() failed")
+	// Recipient of genesis transaction.
+	addr, err := proto.NewAddressFromString("3PAWwWa6GbwcJaFzwqXQN5KQm7H96Y7SHTQ")
+	require.NoError(t, err, "NewAddressFromString() failed")
+	all, err := manager.AddressTransactions(addr, nil, 2000)
+	require.NoError(t, err, "AddressTransactions() failed")
+	require.True(t, len(all) > 1, "too few transactions of address")
+	assert.Equal(t, uint64(1), all[len(all)-1].Height)
+	assert.Equal(t, proto.GenesisTransaction, all[len(all)-1].Transaction.GetTypeVersion().Type)
+	for i, info := range all {
+		id, err := info.Transaction.GetID()
+		require.NoError(t, err, "GetID() failed")
+		expected, err := manager.TransactionInfoByID(id)
+		require.NoError(t, err, "TransactionInfoByID() failed")
+		assert.Equal(t, expected, info)
+		if i > 0 {
+			assert.True(t, info.Height <= all[i-1].Height, "transactions are not ordered by height")
+		}
+	}
+	// Page through all the transactions.
+	var paged []*TransactionInfo
+	var after []byte
+	for {
+		page, err := manager.AddressTransactions(addr, after, 100)
+		require.NoError(t, err, "AddressTransactions() failed")
+		if len(page) == 0 {
+			break
+		}
+		paged = append(paged, page...)
+		after, err = page[len(page)-1].Transaction.GetID()
+		require.NoError(t, err, "GetID() failed")
+	}
+	assert.Equal(t, all, paged)
+
+	_, err = manager.AddressTransactions(addr, nil, 0)
+	assert.Error(t, err)
+	unknownID := make([]byte, crypto.DigestSize)
+	_, err = manager.AddressTransactions(addr, unknownID, 1)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestStoredParams(t *testing.T) {
+	dataDir, err := ioutil.TempDir(os.TempDir(), "dataDir")
+	assert.NoError(t, err, "failed to create dir for test data")
+	defer func() {
+		err := os.RemoveAll(dataDir)
+		assert.NoError(t, err, "failed to remove test data dirs")
+	}()
+
+	params := DefaultStateParams()
+	params.StoreAddressTransactions = true
+	manager, err := newStateManager(dataDir, params, settings.MainNetSettings)
+	require.NoError(t, err, "newStateManager() failed")
+	err = manager.Close()
+	require.NoError(t, err, "manager.Close() failed")
+
+	// Index of address transactions can't be turned off for existing DB.
+	_, err = newStateManager(dataDir, DefaultStateParams(), settings.MainNetSettings)
+	assert.Error(t, err)
+
+	// Neither can full history be turned on.
+	fullHistoryParams := params
+	fullHistoryParams.StoreFullHistory = true
+	_, err = newStateManager(dataDir, fullHistoryParams, settings.MainNetSettings)
+	assert.Error(t, err)
+
+	manager, err = newStateManager(dataDir, params, settings.MainNetSettings)
+	require.NoError(t, err, "newStateManager() failed")
+	_, err = manager.AddressTransactions(proto.Address{}, nil, 1)
+	assert.NoError(t, err)
+	err = manager.Close()
+	require.NoError(t, err, "manager.Close() failed")
+}
+
+func TestScriptHeight(t *testing.T) {
+	dataDir, err := ioutil.TempDir(os.TempDir(), "dataDir")
+	assert.NoError(t, err, "failed to create dir for test data")
+	manager, err := newStateManager(dataDir, DefaultStateParams(), settings.MainNetSettings)
+	require.NoError(t, err, "newStateManager() failed")
+
+	defer func() {
+		err := manager.Close()
+		assert.NoError(t, err, "manager.Close() failed")
+		err = os.RemoveAll(dataDir)
+		assert.NoError(t, err, "failed to remove test data dirs")
+	}()
+
+	// Only genesis block is applied, so transactions are added to the block at height 2.
+	height, err := manager.Height()
+	require.NoError(t, err, "Height() failed")
+	require.Equal(t, uint64(1), height)
+	// Compiled `height == 2` script.
+	script, err := base64.StdEncoding.DecodeString("AQkAAAAAAAACBQAAAAZoZWlnaHQAAAAAAAAAAAJlvYWl")
+	require.NoError(t, err, "DecodeString() failed")
+	info, err := newScriptInfo(script)
+	require.NoError(t, err, "newScriptInfo() failed")
+	sender := testGlobal.senderInfo
+	genesisID := manager.genesis.BlockSignature
+	err = manager.stor.scriptsStorage.setAccountScript(sender.addr, info, genesisID)
+	require.NoError(t, err, "setAccountScript() failed")
+	err = manager.stor.balances.setWavesBalance(sender.addr, &balanceProfile{defaultAmount * 10, 0, 0}, genesisID)
+	require.NoError(t, err, "setWavesBalance() failed")
+	err = manager.flush(true)
+	require.NoError(t, err, "manager.flush() failed")
+
+	timestamp := manager.genesis.Timestamp + 1000
+	recipient := proto.NewRecipientFromAddress(testGlobal.recipientInfo.addr)
+	tx := proto.NewUnsignedTransferV2(sender.pk, proto.OptionalAsset{}, proto.OptionalAsset{}, timestamp, defaultAmount, defaultFee, recipient, "")
+	// Transactions from scripted accounts are checked by scripts instead of signatures.
+	tx.GenerateID()
+	err = manager.ValidateNextTx(tx, timestamp, manager.genesis.Timestamp)
+	assert.NoError(t, err, "ValidateNextTx() failed")
+	manager.ResetValidationList()
+
+	block := &proto.BlockHeader{BlockSignature: blockID0, Timestamp: timestamp, GenPublicKey: testGlobal.minerInfo.pk}
+	err = manager.appender.appendBlock(&appendBlockParams{transactions: []proto.Transaction{tx}, block: block, parent: &manager.genesis.BlockHeader, height: height})
+	assert.NoError(t, err, "appendBlock() failed")
+	// Script fails in the block at height 3.
+	tx = proto.NewUnsignedTransferV2(sender.pk, proto.OptionalAsset{}, proto.OptionalAsset{}, timestamp+1, defaultAmount, defaultFee, recipient, "")
+	tx.GenerateID()
+	err = manager.appender.appendBlock(&appendBlockParams{transactions: []proto.Transaction{tx}, block: block, parent: &manager.genesis.BlockHeader, height: height + 1})
+	assert.Error(t, err, "appendBlock() did not fail at height where script is false")
+}
+
+func TestAddressAssets(t *testing.T) {
+	dataDir, err := ioutil.TempDir(os.TempDir(), "dataDir")
+	assert.NoError(t, err, "failed to create dir for test data")
+	manager, err := newStateManager(dataDir, DefaultStateParams(), settings.MainNetSettings)
+	assert.NoError(t, err, "newStateManager() failed")
+
+	defer func() {
+		err := manager.Close()
+		assert.NoError(t, err, "manager.Close() failed")
+		err = os.RemoveAll(dataDir)
+		assert.NoError(t, err, "failed to remove test data dirs")
+	}()
+
+	addr := testGlobal.recipientInfo.addr
+	blockID := manager.genesis.BlockSignature
+	nft := func(name string) *assetInfo {
+		return &assetInfo{
+			assetConstInfo:      assetConstInfo{issuer: testGlobal.senderInfo.pk, name: name, description: "nft"},
+			assetChangeableInfo: assetChangeableInfo{quantity: *big.NewInt(1)},
+		}
+	}
+	assets := []struct {
+		id      crypto.Digest
+		info    *assetInfo
+		balance uint64
+	}{
+		{crypto.Digest{1}, defaultAssetInfo(true), 100},
+		{crypto.Digest{2}, nft("nft2"), 1},
+		{crypto.Digest{3}, nft("nft3"), 0},
+		{crypto.Digest{4}, nft("nft4"), 1},
+		{crypto.Digest{5}, nft("nft5"), 1},
+	}
+	for _, a := range assets {
+		err = manager.stor.assets.issueAsset(a.id, a.info, blockID)
+		assert.NoError(t, err, "issueAsset() failed")
+		err = manager.stor.balances.setAssetBalance(addr, a.id.Bytes(), a.balance, blockID)
+		assert.NoError(t, err, "setAssetBalance() failed")
+	}
+	err = manager.flush(true)
+	assert.NoError(t, err, "manager.flush() failed")
+
+	portfolio, err := manager.AddressAssets(addr)
+	require.NoError(t, err, "AddressAssets() failed")
+	require.Len(t, portfolio, 4)
+	assert.Equal(t, &AssetBalance{Asset: *newAssetInfo(assets[0].id, assets[0].info), Balance: 100}, portfolio[0])
+	assert.Equal(t, AssetInfo{ID: assets[0].id, Issuer: testGlobal.senderInfo.pk, Name: "asset", Description: "description", Decimals: 2, Quantity: 10000000, Reissuable: true}, portfolio[0].Asset)
+	for i, j := range []int{1, 3, 4} {
+		assert.Equal(t, &AssetBalance{Asset: *newAssetInfo(assets[j].id, assets[j].info), Balance: 1}, portfolio[i+1])
+	}
+	other, err := manager.AddressAssets(testGlobal.senderInfo.addr)
+	require.NoError(t, err, "AddressAssets() failed")
+	assert.Empty(t, other)
+
+	nfts, err := manager.AddressNFTs(addr, nil, 10)
+	require.NoError(t, err, "AddressNFTs() failed")
+	require.Len(t, nfts, 3)
+	assert.Equal(t, []string{"nft2", "nft4", "nft5"}, []string{nfts[0].Name, nfts[1].Name, nfts[2].Name})
+	page, err := manager.AddressNFTs(addr, nil, 2)
+	require.NoError(t, err, "AddressNFTs() failed")
+	assert.Equal(t, nfts[:2], page)
+	page, err = manager.AddressNFTs(addr, page[1].ID.Bytes(), 2)
+	require.NoError(t, err, "AddressNFTs() failed")
+	assert.Equal(t, nfts[2:], page)
+	page, err = manager.AddressNFTs(addr, nfts[2].ID.Bytes(), 2)
+	require.NoError(t, err, "AddressNFTs() failed")
+	assert.Empty(t, page)
+
+	_, err = manager.AddressNFTs(addr, nil, 0)
+	assert.Error(t, err)
+	_, err = manager.AddressNFTs(addr, []byte{1, 2, 3}, 1)
+	assert.Error(t, err)
+}
+
+func TestAssetDistribution(t *testing.T) {
+	blocksPath := blocksPath(t)
+	dataDir, err := ioutil.TempDir(os.TempDir(), "dataDir")
+	assert.NoError(t, err, "failed to create dir for test data")
+	manager, err := newStateManager(dataDir, DefaultStateParams(), settings.MainNetSettings)
+	assert.NoError(t, err, "newStateManager() failed")
+
+	defer func() {
+		err := manager.Close()
+		assert.NoError(t, err, "manager.Close() failed")
+		err = os.RemoveAll(dataDir)
+		assert.NoError(t, err, "failed to remove test data dirs")
+	}()
+
+	height := uint64(75)
+	err = importer.ApplyFromFile(manager, blocksPath, height, 1, false)
+	assert.NoError(t, err, "ApplyFromFile() failed")
+	assetID := crypto.MustDigestFromBase58(assetStr)
+	sender := testGlobal.senderInfo.addr
+	recipient := testGlobal.recipientInfo.addr
+	for _, tc := range []struct {
+		addr    proto.Address
+		balance uint64
+		height  uint64
+	}{
+		{sender, 1000, 10},
+		{sender, 700, 20},
+		{recipient, 300, 20},
+		{sender, 0, 30},
+		{recipient, 1000, 30},
+	} {
+		blockID, err := manager.HeightToBlockID(tc.height)
+		require.NoError(t, err, "HeightToBlockID() failed")
+		err = manager.stor.balances.setAssetBalance(tc.addr, assetID.Bytes(), tc.balance, blockID)
+		require.NoError(t, err, "setAssetBalance() failed")
+	}
+	err = manager.flush(true)
+	require.NoError(t, err, "manager.flush() failed")
+
+	for _, tc := range []struct {
+		height  uint64
+		holders []*AssetHolder
+	}{
+		{5, nil},
+		{10, []*AssetHolder{{Address: sender, Balance: 1000}}},
+		{25, []*AssetHolder{{Address: recipient, Balance: 300}, {Address: sender, Balance: 700}}},
+		{height, []*AssetHolder{{Address: recipient, Balance: 1000}}},
+	} {
+		holders, err := manager.AssetDistribution(assetID, tc.height, nil, 10)
+		require.NoError(t, err, "AssetDistribution() failed")
+		assert.Equal(t, tc.holders, holders, "height %d", tc.height)
+	}
+	// Pagination.
+	page, err := manager.AssetDistribution(assetID, 25, nil, 1)
+	require.NoError(t, err, "AssetDistribution() failed")
+	assert.Equal(t, []*AssetHolder{{Address: recipient, Balance: 300}}, page)
+	page, err = manager.AssetDistribution(assetID, 25, &page[0].Address, 1)
+	require.NoError(t, err, "AssetDistribution() failed")
+	assert.Equal(t, []*AssetHolder{{Address: sender, Balance: 700}}, page)
+	page, err = manager.AssetDistribution(assetID, 25, &page[0].Address, 1)
+	require.NoError(t, err, "AssetDistribution() failed")
+	assert.Empty(t, page)
+
+	_, err = manager.AssetDistribution(assetID, height+2, nil, 10)
+	assert.Error(t, err)
+	_, err = manager.AssetDistribution(assetID, 0, nil, 10)
+	assert.Error(t, err)
+	_, err = manager.AssetDistribution(assetID, height, nil, 0)
+	assert.Error(t, err)
+}
+
+func TestLeases(t *testing.T) {
+	stor, path, err := createStorageObjects()
+	assert.NoError(t, err, "createStorageObjects() failed")
+
+	defer func() {
+		err = stor.stateDB.close()
+		assert.NoError(t, err, "stateDB.close() failed")
+		err = util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	entities, err := newBlockchainEntitiesStorage(stor.hs, stor.stateDB, settings.MainNetSettings)
+	require.NoError(t, err, "newBlockchainEntitiesStorage() failed")
+	manager := &stateManager{stateDB: stor.stateDB, stor: entities, rw: stor.rw}
+
+	// Lease transactions are stored in blocks at heights 1 and 2.
+	txs := []proto.Transaction{createLeaseV1(t), createLeaseV2(t)}
+	blockIDs := []crypto.Signature{blockID0, blockID1}
+	ids := make([]crypto.Digest, len(txs))
+	for i, tx := range txs {
+		err = stor.stateDB.addBlock(blockIDs[i])
+		require.NoError(t, err, "stateDB.addBlock() failed")
+		err = stor.rw.startBlock(blockIDs[i])
+		require.NoError(t, err, "startBlock() failed")
+		txBytes, err := tx.MarshalBinary()
+		require.NoError(t, err, "MarshalBinary() failed")
+		id, err := tx.GetID()
+		require.NoError(t, err, "GetID() failed")
+		err = stor.rw.writeTransaction(id, txBytes)
+		require.NoError(t, err, "writeTransaction() failed")
+		err = stor.rw.finishBlock(blockIDs[i])
+		require.NoError(t, err, "finishBlock() failed")
+		ids[i], err = crypto.NewDigestFromBytes(id)
+		require.NoError(t, err, "NewDigestFromBytes() failed")
+	}
+	sender := testGlobal.senderInfo.addr
+	recipient := testGlobal.recipientInfo.addr
+	other := testGlobal.matcherInfo.addr
+	err = entities.leases.addLeasing(ids[0], &leasing{isActive: true, leaseAmount: 100, sender: sender, recipient: recipient}, blockID0)
+	require.NoError(t, err, "addLeasing() failed")
+	err = entities.leases.addLeasing(ids[1], &leasing{isActive: true, leaseAmount: 200, sender: recipient, recipient: other}, blockID1)
+	require.NoError(t, err, "addLeasing() failed")
+	err = entities.leases.cancelLeasing(ids[0], blockID1, true)
+	require.NoError(t, err, "cancelLeasing() failed")
+	stor.flush(t)
+
+	info0, err := manager.LeaseInfo(ids[0])
+	require.NoError(t, err, "LeaseInfo() failed")
+	assert.Equal(t, &LeaseInfo{ID: ids[0], Sender: sender, Recipient: recipient, Amount: 100, Height: 1, IsActive: false}, info0)
+	info1, err := manager.LeaseInfo(ids[1])
+	require.NoError(t, err, "LeaseInfo() failed")
+	assert.Equal(t, &LeaseInfo{ID: ids[1], Sender: recipient, Recipient: other, Amount: 200, Height: 2, IsActive: true}, info1)
+	_, err = manager.LeaseInfo(crypto.Digest{})
+	assert.True(t, IsNotFound(err))
+
+	for _, tc := range []struct {
+		addr   proto.Address
+		leases []*LeaseInfo
+	}{
+		{sender, nil},
+		{recipient, []*LeaseInfo{info1}},
+		{other, []*LeaseInfo{info1}},
+	} {
+		active, err := manager.ActiveLeases(tc.addr)
+		require.NoError(t, err, "ActiveLeases() failed")
+		assert.Equal(t, tc.leases, active)
+	}
+}
+
 func TestStateManager_Mutex(t *testing.T) {
 	dataDir, err := ioutil.TempDir(os.TempDir(), "dataDir")
 	if err != nil {
@@ -3,33 +3,48 @@ package state
 import (
 	"bytes"
 	"math"
+	"math/big"
 
 	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 )
 
+const (
+	// Limits of script size in bytes and complexity, the same as in Scala implementation.
+	maxExpressionScriptSize = 8 * 1024
+	maxDAppScriptSize       = 32 * 1024
+	maxComplexityV12        = 2000
+	maxComplexityV3         = 4000
+)
+
 type checkerInfo struct {
 	initialisation   bool
 	currentTimestamp uint64
 	parentTimestamp  uint64
 	blockID          crypto.Signature
 	height           uint64
+	// pendingVolumes holds order fills of transactions validated without blocks, it is nil for blocks.
+	pendingVolumes *ordersVolumesDiff
 }
 
 type transactionChecker struct {
 	genesis  crypto.Signature
 	stor     *blockchainEntitiesStorage
 	settings *settings.BlockchainSettings
+	// sc is used to run scripts of smart assets.
+	sc *scriptCaller
 }
 
 func newTransactionChecker(
 	genesis crypto.Signature,
 	stor *blockchainEntitiesStorage,
 	settings *settings.BlockchainSettings,
+	sc *scriptCaller,
 ) (*transactionChecker, error) {
-	return &transactionChecker{genesis, stor, settings}, nil
+	return &transactionChecker{genesis, stor, settings, sc}, nil
 }
 
 func (tc *transactionChecker) checkFromFuture(timestamp uint64) bool {
@@ -57,6 +72,22 @@ func (tc *transactionChecker) checkAsset(asset *proto.OptionalAsset, initialisat
 	return nil
 }
 
+// checkAssetScript runs script of smart asset with the transaction, assets without scripts are not checked.
+func (tc *transactionChecker) checkAssetScript(transaction proto.Transaction, asset *proto.OptionalAsset, info *checkerInfo) error {
+	if !asset.Present {
+		// Waves have no script.
+		return nil
+	}
+	isSmart, err := tc.stor.scriptsStorage.newestIsSmartAsset(asset.ID, !info.initialisation)
+	if err != nil {
+		return err
+	}
+	if !isSmart {
+		return nil
+	}
+	return tc.sc.callAssetScriptWithTx(transaction, asset.ID, info.height, info.initialisation)
+}
+
 func (tc *transactionChecker) checkGenesis(transaction proto.Transaction, info *checkerInfo) error {
 	if info.blockID != tc.genesis {
 		return errors.New("genesis transaction inside of non-genesis block")
@@ -72,7 +103,7 @@ func (tc *transactionChecker) checkPayment(transaction proto.Transaction, info *
 	if !ok {
 		return errors.New("failed to convert interface to Payment transaction")
 	}
-	if info.height >= tc.settings.BlockVersion3AfterHeight {
+	if info.height > tc.settings.BlockVersion3AfterHeight {
 		return errors.Errorf("Payment transaction is deprecated after height %d", tc.settings.BlockVersion3AfterHeight)
 	}
 	if err := tc.checkTimestamps(tx.Timestamp, info.currentTimestamp, info.parentTimestamp); err != nil {
@@ -91,6 +122,33 @@ func (tc *transactionChecker) checkTransfer(tx *proto.Transfer, info *checkerInf
 	if err := tc.checkAsset(&tx.FeeAsset, info.initialisation); err != nil {
 		return err
 	}
+	if err := tc.checkFeeAsset(&tx.FeeAsset, info); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkFeeAsset checks that after sponsorship activation fees are paid only in Waves or sponsored assets.
+func (tc *transactionChecker) checkFeeAsset(asset *proto.OptionalAsset, info *checkerInfo) error {
+	if !asset.Present {
+		// Waves are always valid fee asset.
+		return nil
+	}
+	sponsorshipActivated, err := tc.stor.features.isActivatedForWindow(int16(settings.FeeSponsorship), info.height)
+	if err != nil {
+		return err
+	}
+	if !sponsorshipActivated {
+		// Any asset is valid fee asset before sponsorship activation.
+		return nil
+	}
+	isSponsored, err := tc.stor.assets.newestIsSponsored(asset.ID, !info.initialisation)
+	if err != nil {
+		return err
+	}
+	if !isSponsored {
+		return errors.Errorf("asset %s is not sponsored and can not be used to pay fees", asset.ID.String())
+	}
 	return nil
 }
 
@@ -99,7 +157,10 @@ func (tc *transactionChecker) checkTransferV1(transaction proto.Transaction, inf
 	if !ok {
 		return errors.New("failed to convert interface to TransferV1 transaction")
 	}
-	return tc.checkTransfer(&tx.Transfer, info)
+	if err := tc.checkTransfer(&tx.Transfer, info); err != nil {
+		return err
+	}
+	return tc.checkAssetScript(transaction, &tx.AmountAsset, info)
 }
 
 func (tc *transactionChecker) checkTransferV2(transaction proto.Transaction, info *checkerInfo) error {
@@ -107,7 +168,10 @@ func (tc *transactionChecker) checkTransferV2(transaction proto.Transaction, inf
 	if !ok {
 		return errors.New("failed to convert interface to TransferV2 transaction")
 	}
-	return tc.checkTransfer(&tx.Transfer, info)
+	if err := tc.checkTransfer(&tx.Transfer, info); err != nil {
+		return err
+	}
+	return tc.checkAssetScript(transaction, &tx.AmountAsset, info)
 }
 
 func (tc *transactionChecker) checkIssue(tx *proto.Issue, info *checkerInfo) error {
@@ -117,6 +181,48 @@ func (tc *transactionChecker) checkIssue(tx *proto.Issue, info *checkerInfo) err
 	return nil
 }
 
+// checkScript checks that script can be parsed, its version is activated and it does not exceed size and complexity limits.
+func (tc *transactionChecker) checkScript(script proto.Script, dAppAllowed bool) error {
+	e, err := estimateScript(script)
+	if err != nil {
+		return err
+	}
+	if e.isDApp && !dAppAllowed {
+		return errors.New("DApp can not be used as asset script")
+	}
+	maxSize := maxExpressionScriptSize
+	if e.isDApp {
+		maxSize = maxDAppScriptSize
+	}
+	if len(script) > maxSize {
+		return errors.Errorf("script size %d is greater than limit %d", len(script), maxSize)
+	}
+	maxComplexity := uint64(maxComplexityV12)
+	switch e.version {
+	case 2:
+		activated, err := tc.stor.features.isActivated(int16(settings.SmartAccountTrading))
+		if err != nil {
+			return err
+		}
+		if !activated {
+			return errors.New("SmartAccountTrading feature must be activated for scripts version 2")
+		}
+	case 3:
+		activated, err := tc.stor.features.isActivated(int16(settings.Ride4DApps))
+		if err != nil {
+			return err
+		}
+		if !activated {
+			return errors.New("Ride4DApps feature must be activated for scripts version 3")
+		}
+		maxComplexity = maxComplexityV3
+	}
+	if e.complexity > maxComplexity {
+		return errors.Errorf("script complexity %d is greater than limit %d", e.complexity, maxComplexity)
+	}
+	return nil
+}
+
 func (tc *transactionChecker) checkIssueV1(transaction proto.Transaction, info *checkerInfo) error {
 	tx, ok := transaction.(*proto.IssueV1)
 	if !ok {
@@ -130,6 +236,18 @@ func (tc *transactionChecker) checkIssueV2(transaction proto.Transaction, info *
 	if !ok {
 		return errors.New("failed to convert interface to IssueV2 transaction")
 	}
+	if len(tx.Script) != 0 {
+		activated, err := tc.stor.features.isActivated(int16(settings.SmartAssets))
+		if err != nil {
+			return err
+		}
+		if !activated {
+			return errors.New("SmartAssets feature has not been activated yet")
+		}
+		if err := tc.checkScript(tx.Script, false); err != nil {
+			return errors.Wrap(err, "invalid script")
+		}
+	}
 	return tc.checkIssue(&tx.Issue, info)
 }
 
@@ -167,7 +285,10 @@ func (tc *transactionChecker) checkReissueV1(transaction proto.Transaction, info
 	if !ok {
 		return errors.New("failed to convert interface to ReissueV1 transaction")
 	}
-	return tc.checkReissue(&tx.Reissue, info)
+	if err := tc.checkReissue(&tx.Reissue, info); err != nil {
+		return err
+	}
+	return tc.checkAssetScript(transaction, &proto.OptionalAsset{Present: true, ID: tx.AssetID}, info)
 }
 
 func (tc *transactionChecker) checkReissueV2(transaction proto.Transaction, info *checkerInfo) error {
@@ -175,7 +296,10 @@ func (tc *transactionChecker) checkReissueV2(transaction proto.Transaction, info
 	if !ok {
 		return errors.New("failed to convert interface to ReissueV2 transaction")
 	}
-	return tc.checkReissue(&tx.Reissue, info)
+	if err := tc.checkReissue(&tx.Reissue, info); err != nil {
+		return err
+	}
+	return tc.checkAssetScript(transaction, &proto.OptionalAsset{Present: true, ID: tx.AssetID}, info)
 }
 
 func (tc *transactionChecker) checkBurn(tx *proto.Burn, info *checkerInfo) error {
@@ -190,7 +314,10 @@ func (tc *transactionChecker) checkBurnV1(transaction proto.Transaction, info *c
 	if !ok {
 		return errors.New("failed to convert interface to BurnV1 transaction")
 	}
-	return tc.checkBurn(&tx.Burn, info)
+	if err := tc.checkBurn(&tx.Burn, info); err != nil {
+		return err
+	}
+	return tc.checkAssetScript(transaction, &proto.OptionalAsset{Present: true, ID: tx.AssetID}, info)
 }
 
 func (tc *transactionChecker) checkBurnV2(transaction proto.Transaction, info *checkerInfo) error {
@@ -198,7 +325,159 @@ func (tc *transactionChecker) checkBurnV2(transaction proto.Transaction, info *c
 	if !ok {
 		return errors.New("failed to convert interface to BurnV2 transaction")
 	}
-	return tc.checkBurn(&tx.Burn, info)
+	if err := tc.checkBurn(&tx.Burn, info); err != nil {
+		return err
+	}
+	return tc.checkAssetScript(transaction, &proto.OptionalAsset{Present: true, ID: tx.AssetID}, info)
+}
+
+// exchangeOrder is an order of Exchange transaction.
+type exchangeOrder struct {
+	proto.OrderBody
+	order           proto.Order
+	id              []byte
+	version         byte
+	matcherFeeAsset proto.OptionalAsset
+}
+
+func newExchangeOrder(order proto.Order) (*exchangeOrder, error) {
+	var (
+		body     proto.OrderBody
+		feeAsset proto.OptionalAsset
+	)
+	switch o := order.(type) {
+	case proto.OrderV1:
+		return newExchangeOrder(&o)
+	case proto.OrderV2:
+		return newExchangeOrder(&o)
+	case proto.OrderV3:
+		return newExchangeOrder(&o)
+	case *proto.OrderV1:
+		body = o.OrderBody
+	case *proto.OrderV2:
+		body = o.OrderBody
+	case *proto.OrderV3:
+		body = o.OrderBody
+		feeAsset = o.MatcherFeeAsset
+	default:
+		return nil, errors.Errorf("unsupported order type %T", order)
+	}
+	id, err := order.GetID()
+	if err != nil {
+		return nil, err
+	}
+	return &exchangeOrder{OrderBody: body, order: order, id: id, version: order.GetVersion(), matcherFeeAsset: feeAsset}, nil
+}
+
+func (o *exchangeOrder) verifySignature() (bool, error) {
+	switch order := o.order.(type) {
+	case *proto.OrderV1:
+		return order.Verify(order.SenderPK)
+	case *proto.OrderV2:
+		return order.Verify(order.SenderPK)
+	case *proto.OrderV3:
+		return order.Verify(order.SenderPK)
+	default:
+		return false, errors.Errorf("unsupported order type %T", o.order)
+	}
+}
+
+// txOrders returns buy and sell orders of Exchange transaction.
+func txOrders(transaction proto.Transaction) (proto.Order, proto.Order, error) {
+	switch tx := transaction.(type) {
+	case *proto.ExchangeV1:
+		return &tx.BuyOrder, &tx.SellOrder, nil
+	case *proto.ExchangeV2:
+		return tx.BuyOrder, tx.SellOrder, nil
+	default:
+		return nil, nil, errors.New("failed to convert interface to Exchange transaction")
+	}
+}
+
+func exchangeOrders(transaction proto.Transaction) (*exchangeOrder, *exchangeOrder, error) {
+	buy, sell, err := txOrders(transaction)
+	if err != nil {
+		return nil, nil, err
+	}
+	buyOrder, err := newExchangeOrder(buy)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid buy order")
+	}
+	sellOrder, err := newExchangeOrder(sell)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid sell order")
+	}
+	return buyOrder, sellOrder, nil
+}
+
+// checkOrderLimits checks that amount and matcher fee filled by all exchanges of the order fit into the order.
+// Matcher fee of orders before version 3 should be proportional to the filled amount.
+func (tc *transactionChecker) checkOrderLimits(order *exchangeOrder, amount, matcherFee uint64, info *checkerInfo) error {
+	filledAmount, filledFee, err := tc.stor.ordersVolumes.newestFilled(order.id, !info.initialisation)
+	if err != nil {
+		return err
+	}
+	pendingAmount, pendingFee := info.pendingVolumes.filled(order.id)
+	totalAmount := new(big.Int).SetUint64(filledAmount)
+	totalAmount.Add(totalAmount, new(big.Int).SetUint64(pendingAmount))
+	totalAmount.Add(totalAmount, new(big.Int).SetUint64(amount))
+	if totalAmount.Cmp(new(big.Int).SetUint64(order.Amount)) > 0 {
+		return errors.New("exchanged amount is larger than order amount")
+	}
+	maxFee := new(big.Int).SetUint64(order.MatcherFee)
+	if order.version < 3 {
+		maxFee.Mul(maxFee, totalAmount)
+		maxFee.Quo(maxFee, new(big.Int).SetUint64(order.Amount))
+	}
+	totalFee := new(big.Int).SetUint64(filledFee)
+	totalFee.Add(totalFee, new(big.Int).SetUint64(pendingFee))
+	totalFee.Add(totalFee, new(big.Int).SetUint64(matcherFee))
+	if totalFee.Cmp(maxFee) > 0 {
+		return errors.New("matcher fee is larger than allowed by order")
+	}
+	return nil
+}
+
+// checkOrderProofs checks order of scripted account with its script and other orders with signatures.
+func (tc *transactionChecker) checkOrderProofs(order *exchangeOrder, info *checkerInfo) error {
+	senderAddr, err := proto.NewAddressFromPublicKey(tc.settings.AddressSchemeCharacter, order.SenderPK)
+	if err != nil {
+		return err
+	}
+	hasVerifier, err := tc.stor.scriptsStorage.newestAccountHasVerifier(senderAddr, !info.initialisation)
+	if err != nil {
+		return err
+	}
+	if hasVerifier {
+		return tc.sc.callAccountScriptWithOrder(order.order, senderAddr, info.height, info.initialisation)
+	}
+	ok, err := order.verifySignature()
+	if err != nil {
+		return errors.Wrap(err, "failed to verify order signature")
+	}
+	if !ok {
+		return errors.New("invalid order signature")
+	}
+	return nil
+}
+
+func (tc *transactionChecker) checkOrder(order *exchangeOrder, orderType proto.OrderType, matcherPK crypto.PublicKey, amount, matcherFee uint64, info *checkerInfo) error {
+	if order.OrderType != orderType {
+		return errors.New("incorrect order type")
+	}
+	if order.MatcherPK != matcherPK {
+		return errors.New("matcher public key of order does not match exchange sender")
+	}
+	if order.Expiration < info.currentTimestamp {
+		return errors.New("order expired")
+	}
+	if err := tc.checkOrderProofs(order, info); err != nil {
+		return err
+	}
+	if err := tc.checkOrderLimits(order, amount, matcherFee, info); err != nil {
+		return err
+	}
+	return tc.checkAsset(&order.matcherFeeAsset, info.initialisation)
 }
 
 func (tc *transactionChecker) checkExchange(transaction proto.Transaction, info *checkerInfo) error {
@@ -209,10 +488,23 @@ func (tc *transactionChecker) checkExchange(transaction proto.Transaction, info
 	if err := tc.checkTimestamps(tx.GetTimestamp(), info.currentTimestamp, info.parentTimestamp); err != nil {
 		return errors.Wrap(err, "invalid timestamp")
 	}
-	sellOrder, err := tx.GetSellOrder()
+	buyOrder, sellOrder, err := exchangeOrders(transaction)
 	if err != nil {
 		return err
 	}
+	matcherPK := tx.GetSenderPK()
+	if err := tc.checkOrder(buyOrder, proto.Buy, matcherPK, tx.GetAmount(), tx.GetBuyMatcherFee(), info); err != nil {
+		return errors.Wrap(err, "invalid buy order")
+	}
+	if err := tc.checkOrder(sellOrder, proto.Sell, matcherPK, tx.GetAmount(), tx.GetSellMatcherFee(), info); err != nil {
+		return errors.Wrap(err, "invalid sell order")
+	}
+	if buyOrder.AssetPair != sellOrder.AssetPair {
+		return errors.New("orders have different asset pairs")
+	}
+	if tx.GetPrice() > buyOrder.Price || tx.GetPrice() < sellOrder.Price {
+		return errors.New("exchange price does not match orders prices")
+	}
 	// Check assets.
 	if err := tc.checkAsset(&sellOrder.AssetPair.AmountAsset, info.initialisation); err != nil {
 		return err
@@ -220,6 +512,13 @@ func (tc *transactionChecker) checkExchange(transaction proto.Transaction, info
 	if err := tc.checkAsset(&sellOrder.AssetPair.PriceAsset, info.initialisation); err != nil {
 		return err
 	}
+	// Check scripts of smart assets.
+	if err := tc.checkAssetScript(transaction, &sellOrder.AssetPair.AmountAsset, info); err != nil {
+		return err
+	}
+	if err := tc.checkAssetScript(transaction, &sellOrder.AssetPair.PriceAsset, info); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -345,5 +644,198 @@ func (tc *transactionChecker) checkMassTransferV1(transaction proto.Transaction,
 	if err := tc.checkAsset(&tx.Asset, info.initialisation); err != nil {
 		return err
 	}
+	if err := tc.checkAssetScript(transaction, &tx.Asset, info); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tc *transactionChecker) checkDataV1(transaction proto.Transaction, info *checkerInfo) error {
+	tx, ok := transaction.(*proto.DataV1)
+	if !ok {
+		return errors.New("failed to convert interface to DataV1 transaction")
+	}
+	if err := tc.checkTimestamps(tx.Timestamp, info.currentTimestamp, info.parentTimestamp); err != nil {
+		return errors.Wrap(err, "invalid timestamp")
+	}
+	activated, err := tc.stor.features.isActivated(int16(settings.DataTransaction))
+	if err != nil {
+		return err
+	}
+	if !activated {
+		return errors.New("Data transaction has not been activated yet")
+	}
+	return nil
+}
+
+func (tc *transactionChecker) checkSetScriptV1(transaction proto.Transaction, info *checkerInfo) error {
+	tx, ok := transaction.(*proto.SetScriptV1)
+	if !ok {
+		return errors.New("failed to convert interface to SetScriptV1 transaction")
+	}
+	if err := tc.checkTimestamps(tx.Timestamp, info.currentTimestamp, info.parentTimestamp); err != nil {
+		return errors.Wrap(err, "invalid timestamp")
+	}
+	activated, err := tc.stor.features.isActivated(int16(settings.SmartAccounts))
+	if err != nil {
+		return err
+	}
+	if !activated {
+		return errors.New("SmartAccounts feature has not been activated yet")
+	}
+	if len(tx.Script) == 0 {
+		// Script removal.
+		return nil
+	}
+	if err := tc.checkScript(tx.Script, true); err != nil {
+		return errors.Wrap(err, "invalid script")
+	}
+	return nil
+}
+
+func (tc *transactionChecker) checkSponsorshipV1(transaction proto.Transaction, info *checkerInfo) error {
+	tx, ok := transaction.(*proto.SponsorshipV1)
+	if !ok {
+		return errors.New("failed to convert interface to SponsorshipV1 transaction")
+	}
+	if err := tc.checkTimestamps(tx.Timestamp, info.currentTimestamp, info.parentTimestamp); err != nil {
+		return errors.Wrap(err, "invalid timestamp")
+	}
+	activated, err := tc.stor.features.isActivated(int16(settings.FeeSponsorship))
+	if err != nil {
+		return err
+	}
+	if !activated {
+		return errors.New("sponsorship has not been activated yet")
+	}
+	assetInfo, err := tc.stor.assets.newestAssetInfo(tx.AssetID, !info.initialisation)
+	if err != nil {
+		return errors.New("unknown asset")
+	}
+	if !bytes.Equal(assetInfo.issuer[:], tx.SenderPK[:]) {
+		return errors.New("asset was issued by other address")
+	}
+	isSmart, err := tc.stor.scriptsStorage.newestIsSmartAsset(tx.AssetID, !info.initialisation)
+	if err != nil {
+		return err
+	}
+	if isSmart {
+		return errors.New("smart asset can not be sponsored")
+	}
+	return nil
+}
+
+func (tc *transactionChecker) checkSetAssetScriptV1(transaction proto.Transaction, info *checkerInfo) error {
+	tx, ok := transaction.(*proto.SetAssetScriptV1)
+	if !ok {
+		return errors.New("failed to convert interface to SetAssetScriptV1 transaction")
+	}
+	if err := tc.checkTimestamps(tx.Timestamp, info.currentTimestamp, info.parentTimestamp); err != nil {
+		return errors.Wrap(err, "invalid timestamp")
+	}
+	activated, err := tc.stor.features.isActivated(int16(settings.SmartAssets))
+	if err != nil {
+		return err
+	}
+	if !activated {
+		return errors.New("SmartAssets feature has not been activated yet")
+	}
+	assetInfo, err := tc.stor.assets.newestAssetInfo(tx.AssetID, !info.initialisation)
+	if err != nil {
+		return errors.New("unknown asset")
+	}
+	if !bytes.Equal(assetInfo.issuer[:], tx.SenderPK[:]) {
+		return errors.New("asset was issued by other address")
+	}
+	isSmart, err := tc.stor.scriptsStorage.newestIsSmartAsset(tx.AssetID, !info.initialisation)
+	if err != nil {
+		return err
+	}
+	if !isSmart {
+		return errors.New("script can not be set for asset which was issued without script")
+	}
+	if len(tx.Script) == 0 {
+		return errors.New("script of smart asset can not be removed")
+	}
+	if err := tc.checkScript(tx.Script, false); err != nil {
+		return errors.Wrap(err, "invalid script")
+	}
+	return nil
+}
+
+func (tc *transactionChecker) checkInvokeScriptV1(transaction proto.Transaction, info *checkerInfo) error {
+	tx, ok := transaction.(*proto.InvokeScriptV1)
+	if !ok {
+		return errors.New("failed to convert interface to InvokeScriptV1 transaction")
+	}
+	if err := tc.checkTimestamps(tx.Timestamp, info.currentTimestamp, info.parentTimestamp); err != nil {
+		return errors.Wrap(err, "invalid timestamp")
+	}
+	activated, err := tc.stor.features.isActivated(int16(settings.Ride4DApps))
+	if err != nil {
+		return err
+	}
+	if !activated {
+		return errors.New("Ride4DApps feature has not been activated yet")
+	}
+	if err := tc.checkAsset(&tx.FeeAsset, info.initialisation); err != nil {
+		return err
+	}
+	if err := tc.checkFeeAsset(&tx.FeeAsset, info); err != nil {
+		return err
+	}
+	for _, payment := range tx.Payments {
+		if payment.Amount == 0 {
+			return errors.New("payment amount must be positive")
+		}
+		if err := tc.checkAsset(&payment.Asset, info.initialisation); err != nil {
+			return err
+		}
+		if err := tc.checkAssetScript(transaction, &payment.Asset, info); err != nil {
+			return err
+		}
+	}
+	dAppAddr, err := recipientToAddress(tx.ScriptRecipient, tc.stor.aliases, !info.initialisation)
+	if err != nil {
+		return err
+	}
+	res, err := tc.sc.invokeFunction(tx, *dAppAddr, info.height, info.initialisation)
+	if err != nil {
+		return err
+	}
+	if err := tc.checkScriptResult(transaction, res, info); err != nil {
+		return errors.Wrap(err, "invalid result of DApp invocation")
+	}
+	txID, err := tx.GetID()
+	if err != nil {
+		return err
+	}
+	tc.stor.invokeResults.saveResult(txID, res)
+	return nil
+}
+
+func (tc *transactionChecker) checkScriptResult(transaction proto.Transaction, res *ast.ScriptResult, info *checkerInfo) error {
+	if len(res.Writes) > maxWriteSetSize {
+		return errors.Errorf("too many data entries: %d", len(res.Writes))
+	}
+	for _, entry := range res.Writes {
+		if ok, err := entry.Valid(); !ok {
+			return errors.Wrap(err, "invalid data entry")
+		}
+	}
+	if len(res.Transfers) > maxTransferSetSize {
+		return errors.Errorf("too many transfers: %d", len(res.Transfers))
+	}
+	for _, transfer := range res.Transfers {
+		if transfer.Amount < 0 {
+			return errors.New("negative transfer amount")
+		}
+		if err := tc.checkAsset(&transfer.Asset, info.initialisation); err != nil {
+			return err
+		}
+		if err := tc.checkAssetScript(transaction, &transfer.Asset, info); err != nil {
+			return err
+		}
+	}
 	return nil
 }
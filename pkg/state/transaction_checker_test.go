@@ -1,6 +1,7 @@
 package state
 
 import (
+	"encoding/base64"
 	"math"
 	"testing"
 
@@ -23,7 +24,9 @@ func createCheckerTestObjects(t *testing.T) (*checkerTestObjects, []string) {
 	assert.NoError(t, err, "createStorageObjects() failed")
 	entities, err := newBlockchainEntitiesStorage(stor.hs, stor.stateDB, settings.MainNetSettings)
 	assert.NoError(t, err, "newBlockchainEntitiesStorage() failed")
-	tc, err := newTransactionChecker(crypto.MustSignatureFromBase58(genesisSignature), entities, settings.MainNetSettings)
+	sc, err := newScriptCaller(entities, settings.MainNetSettings)
+	assert.NoError(t, err, "newScriptCaller() failed")
+	tc, err := newTransactionChecker(crypto.MustSignatureFromBase58(genesisSignature), entities, settings.MainNetSettings, sc)
 	assert.NoError(t, err, "newTransactionChecker() failed")
 	tp, err := newTransactionPerformer(entities, settings.MainNetSettings)
 	assert.NoError(t, err, "newTransactionPerormer() failed")
@@ -31,7 +34,7 @@ func createCheckerTestObjects(t *testing.T) (*checkerTestObjects, []string) {
 }
 
 func defaultCheckerInfo(t *testing.T) *checkerInfo {
-	return &checkerInfo{false, defaultTimestamp, defaultTimestamp - settings.MainNetSettings.MaxTxTimeBackOffset/2, blockID0, 100500}
+	return &checkerInfo{false, defaultTimestamp, defaultTimestamp - settings.MainNetSettings.MaxTxTimeBackOffset/2, blockID0, 100500, nil}
 }
 
 func TestCheckGenesis(t *testing.T) {
@@ -65,7 +68,7 @@ func TestCheckPayment(t *testing.T) {
 
 	tx := createPayment(t)
 	info := defaultCheckerInfo(t)
-	info.height = settings.MainNetSettings.BlockVersion3AfterHeight
+	info.height = settings.MainNetSettings.BlockVersion3AfterHeight + 1
 	err := to.tc.checkPayment(tx, info)
 	assert.Error(t, err, "checkPayment accepted payment tx after Block v3 height")
 	info.height = 10
@@ -271,6 +274,19 @@ func TestCheckBurnV2(t *testing.T) {
 	assert.Error(t, err, "checkBurnV1 did not fail with invalid burn timestamp")
 }
 
+func createSignedOrders(t *testing.T, buyAmount, buyFee, sellAmount, sellFee uint64) (proto.Order, proto.Order) {
+	buySK, buyPK := crypto.GenerateKeyPair([]byte("exchange buyer seed"))
+	sellSK, sellPK := crypto.GenerateKeyPair([]byte("exchange seller seed"))
+	expiration := defaultTimestamp + 1000
+	bo := proto.NewUnsignedOrderV1(buyPK, testGlobal.matcherInfo.pk, *testGlobal.asset0.asset, *testGlobal.asset1.asset, proto.Buy, 10e8, buyAmount, defaultTimestamp, expiration, buyFee)
+	err := bo.Sign(buySK)
+	assert.NoError(t, err, "Sign() failed")
+	so := proto.NewUnsignedOrderV3(sellPK, testGlobal.matcherInfo.pk, *testGlobal.asset0.asset, *testGlobal.asset1.asset, proto.Sell, 9e8, sellAmount, defaultTimestamp, expiration, sellFee, *testGlobal.asset1.asset)
+	err = so.Sign(sellSK)
+	assert.NoError(t, err, "Sign() failed")
+	return bo, so
+}
+
 func TestCheckExchange(t *testing.T) {
 	to, path := createCheckerTestObjects(t)
 
@@ -279,7 +295,8 @@ func TestCheckExchange(t *testing.T) {
 		assert.NoError(t, err, "failed to clean test data dirs")
 	}()
 
-	tx := createExchangeV1(t)
+	bo, so := createSignedOrders(t, 100, 10, 50, 10)
+	tx := proto.NewUnsignedExchangeV2(bo, so, 95e7, 50, 5, 10, defaultFee, defaultTimestamp)
 	info := defaultCheckerInfo(t)
 	err := to.tc.checkExchange(tx, info)
 	assert.Error(t, err, "checkExchange did not fail with exchange with unknown assets")
@@ -288,6 +305,100 @@ func TestCheckExchange(t *testing.T) {
 	createAsset(t, to.entities, to.stor, testGlobal.asset1.asset.ID)
 	err = to.tc.checkExchange(tx, info)
 	assert.NoError(t, err, "checkExchange failed with valid exchange")
+
+	// Unsigned orders.
+	err = to.tc.checkExchange(createExchangeV1(t), info)
+	assert.Error(t, err, "checkExchange did not fail with unsigned orders")
+
+	// Swapped orders.
+	tx = proto.NewUnsignedExchangeV2(so, bo, 95e7, 50, 10, 5, defaultFee, defaultTimestamp)
+	err = to.tc.checkExchange(tx, info)
+	assert.Error(t, err, "checkExchange did not fail with swapped orders")
+
+	// Price out of orders bounds.
+	tx = proto.NewUnsignedExchangeV2(bo, so, 11e8, 50, 5, 10, defaultFee, defaultTimestamp)
+	err = to.tc.checkExchange(tx, info)
+	assert.Error(t, err, "checkExchange did not fail with invalid price")
+
+	// Amount larger than sell order amount.
+	tx = proto.NewUnsignedExchangeV2(bo, so, 95e7, 60, 6, 10, defaultFee, defaultTimestamp)
+	err = to.tc.checkExchange(tx, info)
+	assert.Error(t, err, "checkExchange did not fail with too large amount")
+
+	// Buy matcher fee is not proportional to amount of OrderV1.
+	tx = proto.NewUnsignedExchangeV2(bo, so, 95e7, 50, 6, 10, defaultFee, defaultTimestamp)
+	err = to.tc.checkExchange(tx, info)
+	assert.Error(t, err, "checkExchange did not fail with too large buy matcher fee")
+
+	// Sell matcher fee of OrderV3 is limited by the order's fee only.
+	bo, so = createSignedOrders(t, 100, 10, 100, 10)
+	tx = proto.NewUnsignedExchangeV2(bo, so, 95e7, 50, 5, 10, defaultFee, defaultTimestamp)
+	err = to.tc.checkExchange(tx, info)
+	assert.NoError(t, err, "checkExchange failed with valid exchange")
+	tx = proto.NewUnsignedExchangeV2(bo, so, 95e7, 50, 5, 11, defaultFee, defaultTimestamp)
+	err = to.tc.checkExchange(tx, info)
+	assert.Error(t, err, "checkExchange did not fail with too large sell matcher fee")
+
+	// Volumes filled by previous exchanges are taken into account.
+	tx = proto.NewUnsignedExchangeV2(bo, so, 95e7, 50, 5, 10, defaultFee, defaultTimestamp)
+	to.stor.addBlock(t, blockID0)
+	err = to.tp.performExchange(tx, defaultPerformerInfo(t))
+	assert.NoError(t, err, "performExchange() failed")
+	to.stor.flush(t)
+	tx = proto.NewUnsignedExchangeV2(bo, so, 95e7, 40, 4, 1, defaultFee, defaultTimestamp)
+	err = to.tc.checkExchange(tx, info)
+	assert.Error(t, err, "checkExchange did not fail with overfilled sell order matcher fee")
+	tx = proto.NewUnsignedExchangeV2(bo, so, 95e7, 40, 4, 0, defaultFee, defaultTimestamp)
+	err = to.tc.checkExchange(tx, info)
+	assert.NoError(t, err, "checkExchange failed with exchange which fills orders partially")
+	tx = proto.NewUnsignedExchangeV2(bo, so, 95e7, 60, 6, 0, defaultFee, defaultTimestamp)
+	err = to.tc.checkExchange(tx, info)
+	assert.Error(t, err, "checkExchange did not fail with overfilled orders")
+
+	// Volumes of exchanges validated without blocks are taken into account too.
+	info.pendingVolumes = newOrdersVolumesDiff()
+	tx = proto.NewUnsignedExchangeV2(bo, so, 95e7, 30, 3, 0, defaultFee, defaultTimestamp)
+	err = to.tc.checkExchange(tx, info)
+	assert.NoError(t, err, "checkExchange failed with exchange which fills orders partially")
+	err = info.pendingVolumes.saveExchange(tx)
+	assert.NoError(t, err, "saveExchange() failed")
+	err = to.tc.checkExchange(tx, info)
+	assert.Error(t, err, "checkExchange did not fail with orders overfilled by pending exchanges")
+	info.pendingVolumes = nil
+
+	// Expired orders.
+	tx = proto.NewUnsignedExchangeV2(bo, so, 95e7, 40, 4, 0, defaultFee, defaultTimestamp)
+	info.currentTimestamp = defaultTimestamp + 1001
+	err = to.tc.checkExchange(tx, info)
+	assert.Error(t, err, "checkExchange did not fail with expired orders")
+	info.currentTimestamp = defaultTimestamp
+
+	// Orders of scripted accounts are checked by their scripts instead of signatures.
+	_, buyPK := crypto.GenerateKeyPair([]byte("exchange buyer seed"))
+	_, sellPK := crypto.GenerateKeyPair([]byte("exchange seller seed"))
+	expiration := defaultTimestamp + 1000
+	bo = proto.NewUnsignedOrderV1(buyPK, testGlobal.matcherInfo.pk, *testGlobal.asset0.asset, *testGlobal.asset1.asset, proto.Buy, 10e8, 200, defaultTimestamp, expiration, 20)
+	so = proto.NewUnsignedOrderV3(sellPK, testGlobal.matcherInfo.pk, *testGlobal.asset0.asset, *testGlobal.asset1.asset, proto.Sell, 9e8, 200, defaultTimestamp, expiration, 20, *testGlobal.asset1.asset)
+	tx = proto.NewUnsignedExchangeV2(bo, so, 95e7, 50, 5, 5, defaultFee, defaultTimestamp)
+	err = to.tc.checkExchange(tx, info)
+	assert.Error(t, err, "checkExchange did not fail with unsigned orders")
+	setScript := func(pk crypto.PublicKey, scriptBase64 string) {
+		addr, err := proto.NewAddressFromPublicKey(settings.MainNetSettings.AddressSchemeCharacter, pk)
+		assert.NoError(t, err, "NewAddressFromPublicKey() failed")
+		script, err := base64.StdEncoding.DecodeString(scriptBase64)
+		assert.NoError(t, err, "DecodeString() failed")
+		scriptInfo, err := newScriptInfo(script)
+		assert.NoError(t, err, "newScriptInfo() failed")
+		err = to.entities.scriptsStorage.setAccountScript(addr, scriptInfo, blockID0)
+		assert.NoError(t, err, "setAccountScript() failed")
+	}
+	setScript(buyPK, trueScript)
+	setScript(sellPK, trueScript)
+	err = to.tc.checkExchange(tx, info)
+	assert.NoError(t, err, "checkExchange failed with orders allowed by account scripts")
+	setScript(sellPK, falseScript)
+	err = to.tc.checkExchange(tx, info)
+	assert.Error(t, err, "checkExchange did not fail with order rejected by account script")
 }
 
 func TestCheckLeaseV1(t *testing.T) {
@@ -477,3 +588,238 @@ func TestCheckMassTransferV1(t *testing.T) {
 	err = to.tc.checkMassTransferV1(tx, info)
 	assert.NoError(t, err, "checkMassTransferV1 failed with valid massTransfer tx")
 }
+
+func TestCheckDataV1(t *testing.T) {
+	to, path := createCheckerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	tx := createDataV1(t, 5)
+	info := defaultCheckerInfo(t)
+
+	err := to.tc.checkDataV1(tx, info)
+	assert.Error(t, err, "checkDataV1 did not fail prior to feature activation")
+	assert.EqualError(t, err, "Data transaction has not been activated yet")
+
+	// Activate Data transactions.
+	activateFeature(t, to.entities, to.stor, int16(settings.DataTransaction))
+	err = to.tc.checkDataV1(tx, info)
+	assert.NoError(t, err, "checkDataV1 failed with valid Data tx")
+
+	tx.Timestamp = info.parentTimestamp - settings.MainNetSettings.MaxTxTimeBackOffset - 1
+	err = to.tc.checkDataV1(tx, info)
+	assert.Error(t, err, "checkDataV1 did not fail with invalid timestamp")
+}
+
+func TestCheckSetScriptV1(t *testing.T) {
+	to, path := createCheckerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	tx := createSetScriptV1(t)
+	info := defaultCheckerInfo(t)
+
+	err := to.tc.checkSetScriptV1(tx, info)
+	assert.Error(t, err, "checkSetScriptV1 did not fail prior to feature activation")
+	assert.EqualError(t, err, "SmartAccounts feature has not been activated yet")
+
+	// Activate SmartAccounts.
+	activateFeature(t, to.entities, to.stor, int16(settings.SmartAccounts))
+	err = to.tc.checkSetScriptV1(tx, info)
+	assert.NoError(t, err, "checkSetScriptV1 failed with valid SetScriptV1 tx")
+
+	// Script removal.
+	tx.Script = nil
+	err = to.tc.checkSetScriptV1(tx, info)
+	assert.NoError(t, err, "checkSetScriptV1 failed with script removal")
+
+	tx.Script = proto.Script{1, 42, 0, 0, 0, 0}
+	err = to.tc.checkSetScriptV1(tx, info)
+	assert.Error(t, err, "checkSetScriptV1 did not fail with invalid script")
+
+	tx.Script, err = base64.StdEncoding.DecodeString(complexScript)
+	assert.NoError(t, err, "DecodeString() failed")
+	err = to.tc.checkSetScriptV1(tx, info)
+	assert.Error(t, err, "checkSetScriptV1 did not fail with too complex script")
+
+	tx.Script, err = base64.StdEncoding.DecodeString(dAppScript)
+	assert.NoError(t, err, "DecodeString() failed")
+	err = to.tc.checkSetScriptV1(tx, info)
+	assert.Error(t, err, "checkSetScriptV1 did not fail with DApp prior to Ride4DApps activation")
+	activateFeature(t, to.entities, to.stor, int16(settings.Ride4DApps))
+	err = to.tc.checkSetScriptV1(tx, info)
+	assert.NoError(t, err, "checkSetScriptV1 failed with valid DApp")
+}
+
+func TestCheckTransferWithSponsorship(t *testing.T) {
+	to, path := createCheckerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	tx := createTransferV1(t)
+	info := defaultCheckerInfo(t)
+
+	createAsset(t, to.entities, to.stor, testGlobal.asset0.asset.ID)
+	activateFeature(t, to.entities, to.stor, int16(settings.FeeSponsorship))
+	err := to.tc.checkTransferV1(tx, info)
+	assert.Error(t, err, "checkTransferV1 did not fail with fee in asset which is not sponsored")
+
+	to.stor.addBlock(t, blockID0)
+	err = to.entities.assets.sponsorAsset(testGlobal.asset0.asset.ID, 1000, blockID0)
+	assert.NoError(t, err, "sponsorAsset() failed")
+	to.stor.flush(t)
+	err = to.tc.checkTransferV1(tx, info)
+	assert.NoError(t, err, "checkTransferV1 failed with fee in sponsored asset")
+}
+
+func TestCheckSponsorshipV1(t *testing.T) {
+	to, path := createCheckerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	tx := createSponsorshipV1(t)
+	info := defaultCheckerInfo(t)
+
+	err := to.tc.checkSponsorshipV1(tx, info)
+	assert.Error(t, err, "checkSponsorshipV1 did not fail prior to feature activation")
+	assert.EqualError(t, err, "sponsorship has not been activated yet")
+
+	activateFeature(t, to.entities, to.stor, int16(settings.FeeSponsorship))
+	err = to.tc.checkSponsorshipV1(tx, info)
+	assert.Error(t, err, "checkSponsorshipV1 did not fail with unknown asset")
+
+	createAsset(t, to.entities, to.stor, testGlobal.asset0.asset.ID)
+	err = to.tc.checkSponsorshipV1(tx, info)
+	assert.NoError(t, err, "checkSponsorshipV1 failed with valid SponsorshipV1 tx")
+
+	tx.SenderPK = testGlobal.recipientInfo.pk
+	err = to.tc.checkSponsorshipV1(tx, info)
+	assert.EqualError(t, err, "asset was issued by other address")
+}
+
+func TestCheckSetAssetScriptV1(t *testing.T) {
+	to, path := createCheckerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	tx := createSetAssetScriptV1(t)
+	info := defaultCheckerInfo(t)
+
+	err := to.tc.checkSetAssetScriptV1(tx, info)
+	assert.EqualError(t, err, "SmartAssets feature has not been activated yet")
+
+	activateFeature(t, to.entities, to.stor, int16(settings.SmartAssets))
+	createAsset(t, to.entities, to.stor, testGlobal.asset0.asset.ID)
+	err = to.tc.checkSetAssetScriptV1(tx, info)
+	assert.Error(t, err, "checkSetAssetScriptV1 did not fail with asset issued without script")
+
+	to.stor.addBlock(t, blockID0)
+	err = to.entities.scriptsStorage.setAssetScript(testGlobal.asset0.asset.ID, &scriptInfo{script: tx.Script}, blockID0)
+	assert.NoError(t, err, "setAssetScript() failed")
+	to.stor.flush(t)
+	err = to.tc.checkSetAssetScriptV1(tx, info)
+	assert.NoError(t, err, "checkSetAssetScriptV1 failed with valid SetAssetScriptV1 tx")
+
+	activateFeature(t, to.entities, to.stor, int16(settings.Ride4DApps))
+	script := tx.Script
+	tx.Script, err = base64.StdEncoding.DecodeString(dAppScript)
+	assert.NoError(t, err, "DecodeString() failed")
+	err = to.tc.checkSetAssetScriptV1(tx, info)
+	assert.Error(t, err, "checkSetAssetScriptV1 did not fail with DApp")
+	tx.Script = script
+
+	tx.SenderPK = testGlobal.recipientInfo.pk
+	err = to.tc.checkSetAssetScriptV1(tx, info)
+	assert.EqualError(t, err, "asset was issued by other address")
+}
+
+func TestCheckTransferWithSmartAsset(t *testing.T) {
+	to, path := createCheckerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	tx := createTransferV2(t)
+	sk, _ := crypto.GenerateKeyPair([]byte("smart asset test seed"))
+	err := tx.Sign(sk)
+	assert.NoError(t, err, "Sign() failed")
+	info := defaultCheckerInfo(t)
+	createAsset(t, to.entities, to.stor, testGlobal.asset0.asset.ID)
+
+	setAssetScript := func(scriptBase64 string) {
+		script, err := base64.StdEncoding.DecodeString(scriptBase64)
+		assert.NoError(t, err, "DecodeString() failed")
+		to.stor.addBlock(t, blockID0)
+		err = to.entities.scriptsStorage.setAssetScript(testGlobal.asset0.asset.ID, &scriptInfo{script: script}, blockID0)
+		assert.NoError(t, err, "setAssetScript() failed")
+		to.stor.flush(t)
+	}
+
+	setAssetScript(trueScript)
+	err = to.tc.checkTransferV2(tx, info)
+	assert.NoError(t, err, "checkTransferV2 failed with transfer allowed by asset script")
+
+	setAssetScript(falseScript)
+	err = to.tc.checkTransferV2(tx, info)
+	assert.EqualError(t, err, "transaction is not allowed by asset script")
+}
+
+func TestCheckInvokeScriptV1(t *testing.T) {
+	to, path := createCheckerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	call := proto.FunctionCall{Name: "store", Arguments: proto.Arguments{&proto.StringArgument{Value: "a"}, &proto.IntegerArgument{Value: 5}}}
+	tx := createInvokeScriptV1(t, call, nil)
+	info := defaultCheckerInfo(t)
+
+	err := to.tc.checkInvokeScriptV1(tx, info)
+	assert.EqualError(t, err, "Ride4DApps feature has not been activated yet")
+
+	activateFeature(t, to.entities, to.stor, int16(settings.Ride4DApps))
+	err = to.tc.checkInvokeScriptV1(tx, info)
+	assert.Error(t, err, "checkInvokeScriptV1 did not fail with DApp without script")
+
+	script, err := base64.StdEncoding.DecodeString(dAppScript)
+	assert.NoError(t, err, "DecodeString() failed")
+	scriptInf, err := newScriptInfo(script)
+	assert.NoError(t, err, "newScriptInfo() failed")
+	to.stor.addBlock(t, blockID0)
+	err = to.entities.scriptsStorage.setAccountScript(testGlobal.recipientInfo.addr, scriptInf, blockID0)
+	assert.NoError(t, err, "setAccountScript() failed")
+	to.stor.flush(t)
+	err = to.tc.checkInvokeScriptV1(tx, info)
+	assert.NoError(t, err, "checkInvokeScriptV1 failed with valid InvokeScriptV1 tx")
+	res, err := to.entities.invokeResults.result(tx.ID.Bytes())
+	assert.NoError(t, err, "invocation result was not saved")
+	assert.Equal(t, 2, len(res.Writes))
+
+	tx = createInvokeScriptV1(t, proto.FunctionCall{Name: "unknown"}, nil)
+	err = to.tc.checkInvokeScriptV1(tx, info)
+	assert.Error(t, err, "checkInvokeScriptV1 did not fail with unknown function")
+
+	payments := proto.ScriptPayments{{Amount: 0}}
+	tx = createInvokeScriptV1(t, call, payments)
+	err = to.tc.checkInvokeScriptV1(tx, info)
+	assert.EqualError(t, err, "payment amount must be positive")
+}
@@ -180,6 +180,8 @@ type differInfo struct {
 	initialisation bool
 	minerPK        crypto.PublicKey
 	blockTime      uint64
+	// Blockchain height the transaction is applied on.
+	height uint64
 }
 
 func (i *differInfo) hasMiner() bool {
@@ -257,6 +259,18 @@ func (td *transactionDiffer) minerPayout(diff txDiff, fee uint64, info *differIn
 	return nil
 }
 
+func (td *transactionDiffer) assetIssuerAddress(assetID crypto.Digest) (*proto.Address, error) {
+	constInfo, err := td.stor.assets.newestConstInfo(assetID)
+	if err != nil {
+		return nil, err
+	}
+	issuerAddr, err := proto.NewAddressFromPublicKey(td.settings.AddressSchemeCharacter, constInfo.issuer)
+	if err != nil {
+		return nil, err
+	}
+	return &issuerAddr, nil
+}
+
 func (td *transactionDiffer) createDiffGenesis(transaction proto.Transaction, info *differInfo) (txDiff, error) {
 	tx, ok := transaction.(*proto.Genesis)
 	if !ok {
@@ -316,6 +330,45 @@ func recipientToAddress(rcp proto.Recipient, aliases *aliases, filter bool) (*pr
 	return recipientAddr, nil
 }
 
+// appendFeeDiff appends sender fee diff and miner payout to the diff.
+// Fee in sponsored asset is transferred to the sponsor, who pays fee in Waves instead.
+func (td *transactionDiffer) appendFeeDiff(diff txDiff, senderAddr proto.Address, fee uint64, feeAsset proto.OptionalAsset, info *differInfo, updateMinIntermediateBalance bool) error {
+	senderFeeKey := byteKey(senderAddr, feeAsset.ToID())
+	senderFeeBalanceDiff := -int64(fee)
+	if err := diff.appendBalanceDiff(senderFeeKey, newBalanceDiff(senderFeeBalanceDiff, 0, 0, updateMinIntermediateBalance)); err != nil {
+		return err
+	}
+	wavesFee, sponsored, err := feeInWaves(td.stor, fee, feeAsset, info.height, !info.initialisation)
+	if err != nil {
+		return err
+	}
+	if sponsored {
+		issuerAddr, err := td.assetIssuerAddress(feeAsset.ID)
+		if err != nil {
+			return err
+		}
+		issuerAssetKey := byteKey(*issuerAddr, feeAsset.ToID())
+		if err := diff.appendBalanceDiff(issuerAssetKey, newBalanceDiff(int64(fee), 0, 0, updateMinIntermediateBalance)); err != nil {
+			return err
+		}
+		issuerWavesKey := wavesBalanceKey{*issuerAddr}
+		if err := diff.appendBalanceDiff(issuerWavesKey.bytes(), newBalanceDiff(-int64(wavesFee), 0, 0, updateMinIntermediateBalance)); err != nil {
+			return err
+		}
+	}
+	if info.hasMiner() {
+		minerFee, minerFeeAsset := fee, feeAsset.ToID()
+		if sponsored {
+			// Miner gets fee in Waves.
+			minerFee, minerFeeAsset = wavesFee, nil
+		}
+		if err := td.minerPayout(diff, minerFee, info, minerFeeAsset); err != nil {
+			return errors.Wrap(err, "failed to append miner payout")
+		}
+	}
+	return nil
+}
+
 func (td *transactionDiffer) createDiffTransfer(tx *proto.Transfer, info *differInfo) (txDiff, error) {
 	diff := newTxDiff()
 	updateMinIntermediateBalance := false
@@ -327,9 +380,7 @@ func (td *transactionDiffer) createDiffTransfer(tx *proto.Transfer, info *differ
 	if err != nil {
 		return txDiff{}, err
 	}
-	senderFeeKey := byteKey(senderAddr, tx.FeeAsset.ToID())
-	senderFeeBalanceDiff := -int64(tx.Fee)
-	if err := diff.appendBalanceDiff(senderFeeKey, newBalanceDiff(senderFeeBalanceDiff, 0, 0, updateMinIntermediateBalance)); err != nil {
+	if err := td.appendFeeDiff(diff, senderAddr, tx.Fee, tx.FeeAsset, info, updateMinIntermediateBalance); err != nil {
 		return txDiff{}, err
 	}
 	senderAmountKey := byteKey(senderAddr, tx.AmountAsset.ToID())
@@ -347,11 +398,6 @@ func (td *transactionDiffer) createDiffTransfer(tx *proto.Transfer, info *differ
 	if err := diff.appendBalanceDiff(receiverKey, newBalanceDiff(receiverBalanceDiff, 0, 0, updateMinIntermediateBalance)); err != nil {
 		return txDiff{}, err
 	}
-	if info.hasMiner() {
-		if err := td.minerPayout(diff, tx.Fee, info, tx.FeeAsset.ToID()); err != nil {
-			return txDiff{}, errors.Wrap(err, "failed to append miner payout")
-		}
-	}
 	return diff, nil
 }
 
@@ -770,3 +816,158 @@ func (td *transactionDiffer) createDiffMassTransferV1(transaction proto.Transact
 	}
 	return diff, nil
 }
+
+func (td *transactionDiffer) createDiffDataV1(transaction proto.Transaction, info *differInfo) (txDiff, error) {
+	tx, ok := transaction.(*proto.DataV1)
+	if !ok {
+		return txDiff{}, errors.New("failed to convert interface to DataV1 transaction")
+	}
+	diff := newTxDiff()
+	senderAddr, err := proto.NewAddressFromPublicKey(td.settings.AddressSchemeCharacter, tx.SenderPK)
+	if err != nil {
+		return txDiff{}, err
+	}
+	// Append sender diff.
+	senderFeeKey := wavesBalanceKey{address: senderAddr}
+	senderFeeBalanceDiff := -int64(tx.Fee)
+	if err := diff.appendBalanceDiff(senderFeeKey.bytes(), newBalanceDiff(senderFeeBalanceDiff, 0, 0, false)); err != nil {
+		return txDiff{}, err
+	}
+	if info.hasMiner() {
+		if err := td.minerPayout(diff, tx.Fee, info, nil); err != nil {
+			return txDiff{}, errors.Wrap(err, "failed to append miner payout")
+		}
+	}
+	return diff, nil
+}
+
+func (td *transactionDiffer) createDiffSetScriptV1(transaction proto.Transaction, info *differInfo) (txDiff, error) {
+	tx, ok := transaction.(*proto.SetScriptV1)
+	if !ok {
+		return txDiff{}, errors.New("failed to convert interface to SetScriptV1 transaction")
+	}
+	diff := newTxDiff()
+	senderAddr, err := proto.NewAddressFromPublicKey(td.settings.AddressSchemeCharacter, tx.SenderPK)
+	if err != nil {
+		return txDiff{}, err
+	}
+	// Append sender diff.
+	senderFeeKey := wavesBalanceKey{address: senderAddr}
+	senderFeeBalanceDiff := -int64(tx.Fee)
+	if err := diff.appendBalanceDiff(senderFeeKey.bytes(), newBalanceDiff(senderFeeBalanceDiff, 0, 0, false)); err != nil {
+		return txDiff{}, err
+	}
+	if info.hasMiner() {
+		if err := td.minerPayout(diff, tx.Fee, info, nil); err != nil {
+			return txDiff{}, errors.Wrap(err, "failed to append miner payout")
+		}
+	}
+	return diff, nil
+}
+
+func (td *transactionDiffer) createDiffSponsorshipV1(transaction proto.Transaction, info *differInfo) (txDiff, error) {
+	tx, ok := transaction.(*proto.SponsorshipV1)
+	if !ok {
+		return txDiff{}, errors.New("failed to convert interface to SponsorshipV1 transaction")
+	}
+	diff := newTxDiff()
+	senderAddr, err := proto.NewAddressFromPublicKey(td.settings.AddressSchemeCharacter, tx.SenderPK)
+	if err != nil {
+		return txDiff{}, err
+	}
+	// Append sender diff.
+	senderFeeKey := wavesBalanceKey{address: senderAddr}
+	senderFeeBalanceDiff := -int64(tx.Fee)
+	if err := diff.appendBalanceDiff(senderFeeKey.bytes(), newBalanceDiff(senderFeeBalanceDiff, 0, 0, false)); err != nil {
+		return txDiff{}, err
+	}
+	if info.hasMiner() {
+		if err := td.minerPayout(diff, tx.Fee, info, nil); err != nil {
+			return txDiff{}, errors.Wrap(err, "failed to append miner payout")
+		}
+	}
+	return diff, nil
+}
+
+func (td *transactionDiffer) createDiffSetAssetScriptV1(transaction proto.Transaction, info *differInfo) (txDiff, error) {
+	tx, ok := transaction.(*proto.SetAssetScriptV1)
+	if !ok {
+		return txDiff{}, errors.New("failed to convert interface to SetAssetScriptV1 transaction")
+	}
+	diff := newTxDiff()
+	senderAddr, err := proto.NewAddressFromPublicKey(td.settings.AddressSchemeCharacter, tx.SenderPK)
+	if err != nil {
+		return txDiff{}, err
+	}
+	// Append sender diff.
+	senderFeeKey := wavesBalanceKey{address: senderAddr}
+	senderFeeBalanceDiff := -int64(tx.Fee)
+	if err := diff.appendBalanceDiff(senderFeeKey.bytes(), newBalanceDiff(senderFeeBalanceDiff, 0, 0, false)); err != nil {
+		return txDiff{}, err
+	}
+	if info.hasMiner() {
+		if err := td.minerPayout(diff, tx.Fee, info, nil); err != nil {
+			return txDiff{}, errors.Wrap(err, "failed to append miner payout")
+		}
+	}
+	return diff, nil
+}
+
+func (td *transactionDiffer) createDiffInvokeScriptV1(transaction proto.Transaction, info *differInfo) (txDiff, error) {
+	tx, ok := transaction.(*proto.InvokeScriptV1)
+	if !ok {
+		return txDiff{}, errors.New("failed to convert interface to InvokeScriptV1 transaction")
+	}
+	txID, err := tx.GetID()
+	if err != nil {
+		return txDiff{}, err
+	}
+	res, err := td.stor.invokeResults.result(txID)
+	if err != nil {
+		return txDiff{}, err
+	}
+	td.stor.invokeResults.removeResult(txID)
+	diff := newTxDiff()
+	updateMinIntermediateBalance := false
+	if info.blockTime >= td.settings.CheckTempNegativeAfterTime {
+		updateMinIntermediateBalance = true
+	}
+	senderAddr, err := proto.NewAddressFromPublicKey(td.settings.AddressSchemeCharacter, tx.SenderPK)
+	if err != nil {
+		return txDiff{}, err
+	}
+	if err := td.appendFeeDiff(diff, senderAddr, tx.Fee, tx.FeeAsset, info, updateMinIntermediateBalance); err != nil {
+		return txDiff{}, err
+	}
+	dAppAddr, err := recipientToAddress(tx.ScriptRecipient, td.stor.aliases, !info.initialisation)
+	if err != nil {
+		return txDiff{}, err
+	}
+	// Attached payments go from sender to DApp.
+	for _, payment := range tx.Payments {
+		senderPaymentKey := byteKey(senderAddr, payment.Asset.ToID())
+		if err := diff.appendBalanceDiff(senderPaymentKey, newBalanceDiff(-int64(payment.Amount), 0, 0, updateMinIntermediateBalance)); err != nil {
+			return txDiff{}, err
+		}
+		dAppPaymentKey := byteKey(*dAppAddr, payment.Asset.ToID())
+		if err := diff.appendBalanceDiff(dAppPaymentKey, newBalanceDiff(int64(payment.Amount), 0, 0, updateMinIntermediateBalance)); err != nil {
+			return txDiff{}, err
+		}
+	}
+	// Transfers of DApp go from DApp to recipients.
+	for _, transfer := range res.Transfers {
+		recipientAddr, err := recipientToAddress(transfer.Recipient, td.stor.aliases, !info.initialisation)
+		if err != nil {
+			return txDiff{}, err
+		}
+		dAppTransferKey := byteKey(*dAppAddr, transfer.Asset.ToID())
+		if err := diff.appendBalanceDiff(dAppTransferKey, newBalanceDiff(-transfer.Amount, 0, 0, updateMinIntermediateBalance)); err != nil {
+			return txDiff{}, err
+		}
+		recipientKey := byteKey(*recipientAddr, transfer.Asset.ToID())
+		if err := diff.appendBalanceDiff(recipientKey, newBalanceDiff(transfer.Amount, 0, 0, updateMinIntermediateBalance)); err != nil {
+			return txDiff{}, err
+		}
+	}
+	return diff, nil
+}
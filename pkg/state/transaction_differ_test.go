@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 	"github.com/wavesplatform/gowaves/pkg/util"
 )
@@ -40,7 +41,7 @@ func createDifferTestObjects(t *testing.T) (*differTestObjects, []string) {
 }
 
 func defaultDifferInfo(t *testing.T) *differInfo {
-	return &differInfo{false, testGlobal.minerInfo.pk, defaultTimestamp}
+	return &differInfo{false, testGlobal.minerInfo.pk, defaultTimestamp, 100500}
 }
 
 func createGenesis(t *testing.T) *proto.Genesis {
@@ -571,3 +572,181 @@ func TestCreateDiffMassTransferV1(t *testing.T) {
 	}
 	assert.Equal(t, correctDiff, diff)
 }
+
+func createDataV1(t *testing.T, entriesNum int) *proto.DataV1 {
+	tx := proto.NewUnsignedData(testGlobal.senderInfo.pk, defaultFee, defaultTimestamp)
+	for i := 0; i < entriesNum; i++ {
+		entry := proto.IntegerDataEntry{Key: fmt.Sprintf("key%d", i), Value: int64(i)}
+		err := tx.AppendEntry(entry)
+		assert.NoError(t, err, "AppendEntry() failed")
+	}
+	return tx
+}
+
+func TestCreateDiffDataV1(t *testing.T) {
+	to, path := createDifferTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	tx := createDataV1(t, 10)
+	diff, err := to.td.createDiffDataV1(tx, defaultDifferInfo(t))
+	assert.NoError(t, err, "createDiffDataV1 failed")
+
+	correctDiff := txDiff{
+		testGlobal.senderInfo.wavesKey: newBalanceDiff(-int64(tx.Fee), 0, 0, false),
+		testGlobal.minerInfo.wavesKey:  newBalanceDiff(int64(tx.Fee), 0, 0, false),
+	}
+	assert.Equal(t, correctDiff, diff)
+}
+
+func createSetScriptV1(t *testing.T) *proto.SetScriptV1 {
+	script := []byte{1, 6, 183, 111, 203, 71}
+	return proto.NewUnsignedSetScriptV1('W', testGlobal.senderInfo.pk, script, defaultFee, defaultTimestamp)
+}
+
+func TestCreateDiffSetScriptV1(t *testing.T) {
+	to, path := createDifferTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	tx := createSetScriptV1(t)
+	diff, err := to.td.createDiffSetScriptV1(tx, defaultDifferInfo(t))
+	assert.NoError(t, err, "createDiffSetScriptV1 failed")
+
+	correctDiff := txDiff{
+		testGlobal.senderInfo.wavesKey: newBalanceDiff(-int64(tx.Fee), 0, 0, false),
+		testGlobal.minerInfo.wavesKey:  newBalanceDiff(int64(tx.Fee), 0, 0, false),
+	}
+	assert.Equal(t, correctDiff, diff)
+}
+
+func TestCreateDiffTransferWithSponsorship(t *testing.T) {
+	to, path := createDifferTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	// Asset is issued and sponsored by matcher.
+	to.stor.addBlock(t, blockID0)
+	assetInfo := defaultAssetInfo(true)
+	assetInfo.issuer = testGlobal.matcherInfo.pk
+	err := to.entities.assets.issueAsset(testGlobal.asset0.asset.ID, assetInfo, blockID0)
+	assert.NoError(t, err, "issueAsset() failed")
+	assetCost := uint64(10)
+	err = to.entities.assets.sponsorAsset(testGlobal.asset0.asset.ID, assetCost, blockID0)
+	assert.NoError(t, err, "sponsorAsset() failed")
+	to.stor.flush(t)
+	activateFeature(t, to.entities, to.stor, int16(settings.FeeSponsorship))
+
+	tx := createTransferV1(t)
+	diff, err := to.td.createDiffTransferV1(tx, defaultDifferInfo(t))
+	assert.NoError(t, err, "createDiffTransferV1() failed")
+
+	feeInWaves := int64(tx.Fee * feeUnit / assetCost)
+	senderCorrectDiff := newBalanceDiff(-int64(tx.Amount+tx.Fee), 0, 0, true)
+	senderCorrectDiff.minBalance = -int64(tx.Amount + tx.Fee)
+	correctDiff := txDiff{
+		testGlobal.senderInfo.assetKey:    senderCorrectDiff,
+		testGlobal.recipientInfo.assetKey: newBalanceDiff(int64(tx.Amount), 0, 0, true),
+		testGlobal.matcherInfo.assetKey:   newBalanceDiff(int64(tx.Fee), 0, 0, true),
+		testGlobal.matcherInfo.wavesKey:   newBalanceDiff(-feeInWaves, 0, 0, true),
+		testGlobal.minerInfo.wavesKey:     newBalanceDiff(feeInWaves, 0, 0, false),
+	}
+	assert.Equal(t, correctDiff, diff)
+}
+
+func createSponsorshipV1(t *testing.T) *proto.SponsorshipV1 {
+	return proto.NewUnsignedSponsorshipV1(testGlobal.senderInfo.pk, testGlobal.asset0.asset.ID, 1000, defaultFee, defaultTimestamp)
+}
+
+func TestCreateDiffSponsorshipV1(t *testing.T) {
+	to, path := createDifferTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	tx := createSponsorshipV1(t)
+	diff, err := to.td.createDiffSponsorshipV1(tx, defaultDifferInfo(t))
+	assert.NoError(t, err, "createDiffSponsorshipV1 failed")
+
+	correctDiff := txDiff{
+		testGlobal.senderInfo.wavesKey: newBalanceDiff(-int64(tx.Fee), 0, 0, false),
+		testGlobal.minerInfo.wavesKey:  newBalanceDiff(int64(tx.Fee), 0, 0, false),
+	}
+	assert.Equal(t, correctDiff, diff)
+}
+
+func createSetAssetScriptV1(t *testing.T) *proto.SetAssetScriptV1 {
+	script := []byte{1, 6, 183, 111, 203, 71}
+	return proto.NewUnsignedSetAssetScriptV1('W', testGlobal.senderInfo.pk, testGlobal.asset0.asset.ID, script, defaultFee, defaultTimestamp)
+}
+
+func TestCreateDiffSetAssetScriptV1(t *testing.T) {
+	to, path := createDifferTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	tx := createSetAssetScriptV1(t)
+	diff, err := to.td.createDiffSetAssetScriptV1(tx, defaultDifferInfo(t))
+	assert.NoError(t, err, "createDiffSetAssetScriptV1 failed")
+
+	correctDiff := txDiff{
+		testGlobal.senderInfo.wavesKey: newBalanceDiff(-int64(tx.Fee), 0, 0, false),
+		testGlobal.minerInfo.wavesKey:  newBalanceDiff(int64(tx.Fee), 0, 0, false),
+	}
+	assert.Equal(t, correctDiff, diff)
+}
+
+func createInvokeScriptV1(t *testing.T, call proto.FunctionCall, payments proto.ScriptPayments) *proto.InvokeScriptV1 {
+	tx := proto.NewUnsignedInvokeScriptV1('W', testGlobal.senderInfo.pk, proto.NewRecipientFromAddress(testGlobal.recipientInfo.addr), call, payments, proto.OptionalAsset{}, defaultFee, defaultTimestamp)
+	tx.GenerateID()
+	return tx
+}
+
+func TestCreateDiffInvokeScriptV1(t *testing.T) {
+	to, path := createDifferTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	payments := proto.ScriptPayments{{Amount: defaultAmount, Asset: *testGlobal.asset0.asset}}
+	tx := createInvokeScriptV1(t, proto.FunctionCall{Default: true}, payments)
+	_, err := to.td.createDiffInvokeScriptV1(tx, defaultDifferInfo(t))
+	assert.Error(t, err, "createDiffInvokeScriptV1() did not fail without invocation result")
+
+	transferAmount := int64(10)
+	res := &ast.ScriptResult{
+		Transfers: []ast.ScriptTransfer{{Recipient: proto.NewRecipientFromAddress(testGlobal.matcherInfo.addr), Amount: transferAmount}},
+	}
+	to.entities.invokeResults.saveResult(tx.ID.Bytes(), res)
+	diff, err := to.td.createDiffInvokeScriptV1(tx, defaultDifferInfo(t))
+	assert.NoError(t, err, "createDiffInvokeScriptV1() failed")
+
+	correctDiff := txDiff{
+		testGlobal.senderInfo.wavesKey:    newBalanceDiff(-int64(tx.Fee), 0, 0, true),
+		testGlobal.senderInfo.assetKey:    newBalanceDiff(-int64(defaultAmount), 0, 0, true),
+		testGlobal.recipientInfo.assetKey: newBalanceDiff(int64(defaultAmount), 0, 0, true),
+		testGlobal.recipientInfo.wavesKey: newBalanceDiff(-transferAmount, 0, 0, true),
+		testGlobal.matcherInfo.wavesKey:   newBalanceDiff(transferAmount, 0, 0, true),
+		testGlobal.minerInfo.wavesKey:     newBalanceDiff(int64(tx.Fee), 0, 0, false),
+	}
+	assert.Equal(t, correctDiff, diff)
+
+	_, err = to.entities.invokeResults.result(tx.ID.Bytes())
+	assert.Error(t, err, "invocation result was not removed after creating diff")
+}
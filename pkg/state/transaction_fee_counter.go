@@ -3,6 +3,7 @@ package state
 import (
 	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/settings"
 )
 
 const (
@@ -19,6 +20,33 @@ func calculateCurrentBlockTxFee(txFee uint64, ngActivated bool) uint64 {
 	return txFee
 }
 
+// feeInWaves converts fee paid in sponsored asset to Waves.
+// It returns false if fee asset is Waves, asset is not sponsored or sponsorship is not in effect at given height yet.
+func feeInWaves(stor *blockchainEntitiesStorage, fee uint64, feeAsset proto.OptionalAsset, height uint64, filter bool) (uint64, bool, error) {
+	if !feeAsset.Present {
+		return fee, false, nil
+	}
+	sponsorshipActivated, err := stor.features.isActivatedForWindow(int16(settings.FeeSponsorship), height)
+	if err != nil {
+		return 0, false, err
+	}
+	if !sponsorshipActivated {
+		return 0, false, nil
+	}
+	isSponsored, err := stor.assets.newestIsSponsored(feeAsset.ID, filter)
+	if err != nil {
+		return 0, false, err
+	}
+	if !isSponsored {
+		return 0, false, nil
+	}
+	wavesFee, err := stor.assets.newestSponsoredAssetToWaves(feeAsset.ID, fee, filter)
+	if err != nil {
+		return 0, false, err
+	}
+	return wavesFee, true, nil
+}
+
 func minerFee(distr *feeDistribution, fee, curFee uint64, asset proto.OptionalAsset) error {
 	if !asset.Present {
 		distr.totalWavesFees += fee
@@ -165,3 +193,43 @@ func minerFeeMassTransferV1(transaction proto.Transaction, distr *feeDistributio
 	}
 	return minerFee(distr, tx.Fee, calculateCurrentBlockTxFee(tx.Fee, ngActivated), proto.OptionalAsset{Present: false})
 }
+
+func minerFeeDataV1(transaction proto.Transaction, distr *feeDistribution, ngActivated bool) error {
+	tx, ok := transaction.(*proto.DataV1)
+	if !ok {
+		return errors.New("failed to convert interface to DataV1 tx")
+	}
+	return minerFee(distr, tx.Fee, calculateCurrentBlockTxFee(tx.Fee, ngActivated), proto.OptionalAsset{Present: false})
+}
+
+func minerFeeSetScriptV1(transaction proto.Transaction, distr *feeDistribution, ngActivated bool) error {
+	tx, ok := transaction.(*proto.SetScriptV1)
+	if !ok {
+		return errors.New("failed to convert interface to SetScriptV1 tx")
+	}
+	return minerFee(distr, tx.Fee, calculateCurrentBlockTxFee(tx.Fee, ngActivated), proto.OptionalAsset{Present: false})
+}
+
+func minerFeeSponsorshipV1(transaction proto.Transaction, distr *feeDistribution, ngActivated bool) error {
+	tx, ok := transaction.(*proto.SponsorshipV1)
+	if !ok {
+		return errors.New("failed to convert interface to SponsorshipV1 tx")
+	}
+	return minerFee(distr, tx.Fee, calculateCurrentBlockTxFee(tx.Fee, ngActivated), proto.OptionalAsset{Present: false})
+}
+
+func minerFeeSetAssetScriptV1(transaction proto.Transaction, distr *feeDistribution, ngActivated bool) error {
+	tx, ok := transaction.(*proto.SetAssetScriptV1)
+	if !ok {
+		return errors.New("failed to convert interface to SetAssetScriptV1 tx")
+	}
+	return minerFee(distr, tx.Fee, calculateCurrentBlockTxFee(tx.Fee, ngActivated), proto.OptionalAsset{Present: false})
+}
+
+func minerFeeInvokeScriptV1(transaction proto.Transaction, distr *feeDistribution, ngActivated bool) error {
+	tx, ok := transaction.(*proto.InvokeScriptV1)
+	if !ok {
+		return errors.New("failed to convert interface to InvokeScriptV1 tx")
+	}
+	return minerFee(distr, tx.Fee, calculateCurrentBlockTxFee(tx.Fee, ngActivated), tx.FeeAsset)
+}
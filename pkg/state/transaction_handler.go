@@ -62,10 +62,10 @@ func buildHanndles(tc *transactionChecker, tp *transactionPerformer, td *transac
 			tc.checkBurnV2, tp.performBurnV2, td.createDiffBurnV2, minerFeeBurnV2,
 		},
 		proto.TransactionTypeVersion{Type: proto.ExchangeTransaction, Version: 1}: txHandleFuncs{
-			tc.checkExchange, nil, td.createDiffExchange, minerFeeExchange,
+			tc.checkExchange, tp.performExchange, td.createDiffExchange, minerFeeExchange,
 		},
 		proto.TransactionTypeVersion{Type: proto.ExchangeTransaction, Version: 2}: txHandleFuncs{
-			tc.checkExchange, nil, td.createDiffExchange, minerFeeExchange,
+			tc.checkExchange, tp.performExchange, td.createDiffExchange, minerFeeExchange,
 		},
 		proto.TransactionTypeVersion{Type: proto.LeaseTransaction, Version: 1}: txHandleFuncs{
 			tc.checkLeaseV1, tp.performLeaseV1, td.createDiffLeaseV1, minerFeeLeaseV1,
@@ -88,6 +88,21 @@ func buildHanndles(tc *transactionChecker, tp *transactionPerformer, td *transac
 		proto.TransactionTypeVersion{Type: proto.MassTransferTransaction, Version: 1}: txHandleFuncs{
 			tc.checkMassTransferV1, nil, td.createDiffMassTransferV1, minerFeeMassTransferV1,
 		},
+		proto.TransactionTypeVersion{Type: proto.DataTransaction, Version: 1}: txHandleFuncs{
+			tc.checkDataV1, tp.performDataV1, td.createDiffDataV1, minerFeeDataV1,
+		},
+		proto.TransactionTypeVersion{Type: proto.SetScriptTransaction, Version: 1}: txHandleFuncs{
+			tc.checkSetScriptV1, tp.performSetScriptV1, td.createDiffSetScriptV1, minerFeeSetScriptV1,
+		},
+		proto.TransactionTypeVersion{Type: proto.SponsorshipTransaction, Version: 1}: txHandleFuncs{
+			tc.checkSponsorshipV1, tp.performSponsorshipV1, td.createDiffSponsorshipV1, minerFeeSponsorshipV1,
+		},
+		proto.TransactionTypeVersion{Type: proto.SetAssetScriptTransaction, Version: 1}: txHandleFuncs{
+			tc.checkSetAssetScriptV1, tp.performSetAssetScriptV1, td.createDiffSetAssetScriptV1, minerFeeSetAssetScriptV1,
+		},
+		proto.TransactionTypeVersion{Type: proto.InvokeScriptTransaction, Version: 1}: txHandleFuncs{
+			tc.checkInvokeScriptV1, tp.performInvokeScriptV1, td.createDiffInvokeScriptV1, minerFeeInvokeScriptV1,
+		},
 	}
 }
 
@@ -95,8 +110,9 @@ func newTransactionHandler(
 	genesis crypto.Signature,
 	stor *blockchainEntitiesStorage,
 	settings *settings.BlockchainSettings,
+	sc *scriptCaller,
 ) (*transactionHandler, error) {
-	tc, err := newTransactionChecker(genesis, stor, settings)
+	tc, err := newTransactionChecker(genesis, stor, settings, sc)
 	if err != nil {
 		return nil, err
 	}
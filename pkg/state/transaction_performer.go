@@ -68,7 +68,25 @@ func (tp *transactionPerformer) performIssueV2(transaction proto.Transaction, in
 	if err != nil {
 		return errors.Errorf("failed to get transaction ID: %v\n", err)
 	}
-	return tp.performIssue(&tx.Issue, txID, info)
+	if err := tp.performIssue(&tx.Issue, txID, info); err != nil {
+		return err
+	}
+	if len(tx.Script) == 0 {
+		// Asset without script.
+		return nil
+	}
+	assetID, err := crypto.NewDigestFromBytes(txID)
+	if err != nil {
+		return err
+	}
+	inf, err := newScriptInfo(tx.Script)
+	if err != nil {
+		return err
+	}
+	if err := tp.stor.scriptsStorage.setAssetScript(assetID, inf, info.blockID); err != nil {
+		return errors.Wrap(err, "failed to set asset script")
+	}
+	return nil
 }
 
 func (tp *transactionPerformer) performReissue(tx *proto.Reissue, info *performerInfo) error {
@@ -220,3 +238,104 @@ func (tp *transactionPerformer) performCreateAliasV2(transaction proto.Transacti
 	}
 	return tp.performCreateAlias(&tx.CreateAlias, info)
 }
+
+func (tp *transactionPerformer) performDataV1(transaction proto.Transaction, info *performerInfo) error {
+	tx, ok := transaction.(*proto.DataV1)
+	if !ok {
+		return errors.New("failed to convert interface to DataV1 transaction")
+	}
+	senderAddr, err := proto.NewAddressFromPublicKey(tp.settings.AddressSchemeCharacter, tx.SenderPK)
+	if err != nil {
+		return err
+	}
+	for _, entry := range tx.Entries {
+		if err := tp.stor.accountsDataStor.appendEntry(senderAddr, entry, info.blockID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tp *transactionPerformer) performExchange(transaction proto.Transaction, info *performerInfo) error {
+	fills, err := exchangeFills(transaction)
+	if err != nil {
+		return err
+	}
+	for _, fill := range fills {
+		if err := tp.stor.ordersVolumes.increaseFilled(fill.orderID, fill.amount, fill.fee, info.blockID, !info.initialisation); err != nil {
+			return errors.Wrap(err, "failed to update order volume")
+		}
+	}
+	return nil
+}
+
+func (tp *transactionPerformer) performSetScriptV1(transaction proto.Transaction, info *performerInfo) error {
+	tx, ok := transaction.(*proto.SetScriptV1)
+	if !ok {
+		return errors.New("failed to convert interface to SetScriptV1 transaction")
+	}
+	senderAddr, err := proto.NewAddressFromPublicKey(tp.settings.AddressSchemeCharacter, tx.SenderPK)
+	if err != nil {
+		return err
+	}
+	inf, err := newScriptInfo(tx.Script)
+	if err != nil {
+		return err
+	}
+	if err := tp.stor.scriptsStorage.setAccountScript(senderAddr, inf, info.blockID); err != nil {
+		return errors.Wrap(err, "failed to set account script")
+	}
+	return nil
+}
+
+func (tp *transactionPerformer) performSponsorshipV1(transaction proto.Transaction, info *performerInfo) error {
+	tx, ok := transaction.(*proto.SponsorshipV1)
+	if !ok {
+		return errors.New("failed to convert interface to SponsorshipV1 transaction")
+	}
+	// Zero minimal fee cancels sponsorship.
+	if err := tp.stor.assets.sponsorAsset(tx.AssetID, tx.MinAssetFee, info.blockID); err != nil {
+		return errors.Wrap(err, "failed to sponsor asset")
+	}
+	return nil
+}
+
+func (tp *transactionPerformer) performSetAssetScriptV1(transaction proto.Transaction, info *performerInfo) error {
+	tx, ok := transaction.(*proto.SetAssetScriptV1)
+	if !ok {
+		return errors.New("failed to convert interface to SetAssetScriptV1 transaction")
+	}
+	inf, err := newScriptInfo(tx.Script)
+	if err != nil {
+		return err
+	}
+	if err := tp.stor.scriptsStorage.setAssetScript(tx.AssetID, inf, info.blockID); err != nil {
+		return errors.Wrap(err, "failed to set asset script")
+	}
+	return nil
+}
+
+func (tp *transactionPerformer) performInvokeScriptV1(transaction proto.Transaction, info *performerInfo) error {
+	tx, ok := transaction.(*proto.InvokeScriptV1)
+	if !ok {
+		return errors.New("failed to convert interface to InvokeScriptV1 transaction")
+	}
+	txID, err := tx.GetID()
+	if err != nil {
+		return err
+	}
+	res, err := tp.stor.invokeResults.result(txID)
+	if err != nil {
+		return err
+	}
+	dAppAddr, err := recipientToAddress(tx.ScriptRecipient, tp.stor.aliases, !info.initialisation)
+	if err != nil {
+		return err
+	}
+	for _, entry := range res.Writes {
+		if err := tp.stor.accountsDataStor.appendEntry(*dAppAddr, entry, info.blockID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"github.com/wavesplatform/gowaves/pkg/ride/evaluator/ast"
 	"github.com/wavesplatform/gowaves/pkg/settings"
 	"github.com/wavesplatform/gowaves/pkg/util"
 )
@@ -340,3 +342,150 @@ func TestPerformCreateAliasV2(t *testing.T) {
 	_, err = to.entities.aliases.addrByAlias(tx.Alias.Alias, true)
 	assert.Equal(t, errAliasDisabled, err)
 }
+
+func TestPerformDataV1(t *testing.T) {
+	to, path := createPerformerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	to.stor.addBlock(t, blockID0)
+	tx := createDataV1(t, 1)
+	err := to.tp.performDataV1(tx, defaultPerformerInfo(t))
+	assert.NoError(t, err, "performDataV1() failed")
+	to.stor.flush(t)
+	entry, err := to.entities.accountsDataStor.retrieveEntry(testGlobal.senderInfo.addr, tx.Entries[0].GetKey(), true)
+	assert.NoError(t, err, "retrieveEntry() failed")
+	assert.Equal(t, tx.Entries[0], entry, "invalid entry after performing DataV1 transaction")
+}
+
+func TestPerformSetScriptV1(t *testing.T) {
+	to, path := createPerformerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	to.stor.addBlock(t, blockID0)
+	tx := createSetScriptV1(t)
+	err := to.tp.performSetScriptV1(tx, defaultPerformerInfo(t))
+	assert.NoError(t, err, "performSetScriptV1() failed")
+	to.stor.flush(t)
+	info, err := to.entities.scriptsStorage.accountScriptInfo(testGlobal.senderInfo.addr, true)
+	assert.NoError(t, err, "accountScriptInfo() failed")
+	assert.Equal(t, tx.Script, info.script, "invalid script after performing SetScriptV1 transaction")
+	assert.Equal(t, uint64(1), info.complexity, "invalid script complexity after performing SetScriptV1 transaction")
+
+	// Remove script.
+	tx.Script = nil
+	err = to.tp.performSetScriptV1(tx, defaultPerformerInfo(t))
+	assert.NoError(t, err, "performSetScriptV1() failed")
+	to.stor.flush(t)
+	hasVerifier, err := to.entities.scriptsStorage.accountHasVerifier(testGlobal.senderInfo.addr, true)
+	assert.NoError(t, err, "accountHasVerifier() failed")
+	assert.Equal(t, false, hasVerifier, "account still has verifier after removing script")
+}
+
+func TestPerformExchange(t *testing.T) {
+	to, path := createPerformerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	to.stor.addBlock(t, blockID0)
+	tx := createExchangeV2(t)
+	err := to.tp.performExchange(tx, defaultPerformerInfo(t))
+	assert.NoError(t, err, "performExchange() failed")
+	err = to.tp.performExchange(tx, defaultPerformerInfo(t))
+	assert.NoError(t, err, "performExchange() failed")
+	to.stor.flush(t)
+
+	buyID, err := tx.BuyOrder.GetID()
+	assert.NoError(t, err, "GetID() failed")
+	amount, fee, err := to.entities.ordersVolumes.filled(buyID, true)
+	assert.NoError(t, err, "filled() failed")
+	assert.Equal(t, 2*tx.Amount, amount)
+	assert.Equal(t, 2*tx.BuyMatcherFee, fee)
+	sellID, err := tx.SellOrder.GetID()
+	assert.NoError(t, err, "GetID() failed")
+	amount, fee, err = to.entities.ordersVolumes.filled(sellID, true)
+	assert.NoError(t, err, "filled() failed")
+	assert.Equal(t, 2*tx.Amount, amount)
+	assert.Equal(t, 2*tx.SellMatcherFee, fee)
+}
+
+func TestPerformSponsorshipV1(t *testing.T) {
+	to, path := createPerformerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	to.stor.addBlock(t, blockID0)
+	tx := createSponsorshipV1(t)
+	err := to.tp.performSponsorshipV1(tx, defaultPerformerInfo(t))
+	assert.NoError(t, err, "performSponsorshipV1() failed")
+	to.stor.flush(t)
+	assetCost, err := to.entities.assets.assetCost(tx.AssetID, true)
+	assert.NoError(t, err, "assetCost() failed")
+	assert.Equal(t, tx.MinAssetFee, assetCost, "invalid asset cost after performing SponsorshipV1 transaction")
+}
+
+func TestPerformSetAssetScriptV1(t *testing.T) {
+	to, path := createPerformerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	// Issue smart asset.
+	to.stor.addBlock(t, blockID0)
+	issueTx := createIssueV2(t)
+	issueTx.Script = []byte{1, 6, 183, 111, 203, 71}
+	err := to.tp.performIssueV2(issueTx, defaultPerformerInfo(t))
+	assert.NoError(t, err, "performIssueV2() failed")
+	to.stor.flush(t)
+	isSmart, err := to.entities.scriptsStorage.isSmartAsset(*issueTx.ID, true)
+	assert.NoError(t, err, "isSmartAsset() failed")
+	assert.Equal(t, true, isSmart, "asset issued with script is not smart")
+
+	tx := createSetAssetScriptV1(t)
+	tx.AssetID = *issueTx.ID
+	tx.Script = []byte{1, 7, 222, 98, 89, 122}
+	err = to.tp.performSetAssetScriptV1(tx, defaultPerformerInfo(t))
+	assert.NoError(t, err, "performSetAssetScriptV1() failed")
+	to.stor.flush(t)
+	info, err := to.entities.scriptsStorage.assetScriptInfo(tx.AssetID, true)
+	assert.NoError(t, err, "assetScriptInfo() failed")
+	assert.Equal(t, tx.Script, info.script, "invalid script after performing SetAssetScriptV1 transaction")
+}
+
+func TestPerformInvokeScriptV1(t *testing.T) {
+	to, path := createPerformerTestObjects(t)
+
+	defer func() {
+		err := util.CleanTemporaryDirs(path)
+		assert.NoError(t, err, "failed to clean test data dirs")
+	}()
+
+	to.stor.addBlock(t, blockID0)
+	tx := createInvokeScriptV1(t, proto.FunctionCall{Default: true}, nil)
+	err := to.tp.performInvokeScriptV1(tx, defaultPerformerInfo(t))
+	assert.Error(t, err, "performInvokeScriptV1() did not fail without invocation result")
+
+	entry := proto.StringDataEntry{Key: "key", Value: "value"}
+	to.entities.invokeResults.saveResult(tx.ID.Bytes(), &ast.ScriptResult{Writes: []proto.DataEntry{entry}})
+	err = to.tp.performInvokeScriptV1(tx, defaultPerformerInfo(t))
+	assert.NoError(t, err, "performInvokeScriptV1() failed")
+	to.stor.flush(t)
+	dAppEntry, err := to.entities.accountsDataStor.retrieveEntry(testGlobal.recipientInfo.addr, entry.Key, true)
+	assert.NoError(t, err, "retrieveEntry() failed")
+	assert.Equal(t, entry, dAppEntry, "invalid entry of DApp after performing InvokeScriptV1 transaction")
+}
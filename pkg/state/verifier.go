@@ -32,12 +32,82 @@ type verifyTask struct {
 	block      *proto.Block
 	blockBytes []byte
 	tx         proto.Transaction
+	// checkTxSig is false for transactions from scripted accounts, they are verified by scripts.
+	checkTxSig bool
 }
 
-func checkTx(tx proto.Transaction) error {
+// txSenderPK returns public key of transaction sender, false is returned for transactions without sender.
+func txSenderPK(tx proto.Transaction) (crypto.PublicKey, bool) {
+	switch t := tx.(type) {
+	case *proto.Payment:
+		return t.SenderPK, true
+	case *proto.TransferV1:
+		return t.SenderPK, true
+	case *proto.TransferV2:
+		return t.SenderPK, true
+	case *proto.IssueV1:
+		return t.SenderPK, true
+	case *proto.IssueV2:
+		return t.SenderPK, true
+	case *proto.ReissueV1:
+		return t.SenderPK, true
+	case *proto.ReissueV2:
+		return t.SenderPK, true
+	case *proto.BurnV1:
+		return t.SenderPK, true
+	case *proto.BurnV2:
+		return t.SenderPK, true
+	case *proto.ExchangeV1:
+		return t.SenderPK, true
+	case *proto.ExchangeV2:
+		return t.SenderPK, true
+	case *proto.LeaseV1:
+		return t.SenderPK, true
+	case *proto.LeaseV2:
+		return t.SenderPK, true
+	case *proto.LeaseCancelV1:
+		return t.SenderPK, true
+	case *proto.LeaseCancelV2:
+		return t.SenderPK, true
+	case *proto.CreateAliasV1:
+		return t.SenderPK, true
+	case *proto.CreateAliasV2:
+		return t.SenderPK, true
+	case *proto.SponsorshipV1:
+		return t.SenderPK, true
+	case *proto.MassTransferV1:
+		return t.SenderPK, true
+	case *proto.DataV1:
+		return t.SenderPK, true
+	case *proto.SetScriptV1:
+		return t.SenderPK, true
+	case *proto.SetAssetScriptV1:
+		return t.SenderPK, true
+	case *proto.InvokeScriptV1:
+		return t.SenderPK, true
+	default:
+		return crypto.PublicKey{}, false
+	}
+}
+
+// isProvenTx checks if transaction is authorized by proofs, only such transactions can be sent from scripted accounts.
+func isProvenTx(tx proto.Transaction) bool {
+	switch tx.(type) {
+	case *proto.Genesis, *proto.Payment, *proto.TransferV1, *proto.IssueV1, *proto.ReissueV1, *proto.BurnV1,
+		*proto.ExchangeV1, *proto.LeaseV1, *proto.LeaseCancelV1, *proto.CreateAliasV1:
+		return false
+	default:
+		return true
+	}
+}
+
+func checkTx(tx proto.Transaction, checkTxSig bool) error {
 	if ok, err := tx.Valid(); !ok {
 		return errors.Wrap(err, "invalid tx data")
 	}
+	if !checkTxSig {
+		return nil
+	}
 	switch t := tx.(type) {
 	case *proto.Genesis:
 	case *proto.Payment:
@@ -150,7 +220,7 @@ func handleTask(task *verifyTask) error {
 			return errors.New("invalid block signature")
 		}
 	case verifyTx:
-		if err := checkTx(task.tx); err != nil {
+		if err := checkTx(task.tx, task.checkTxSig); err != nil {
 			return err
 		}
 	}